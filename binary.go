@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// binaryPrompt holds a downloaded object that looks binary until the user decides
+// whether to open it anyway.
+type binaryPrompt struct {
+	item    item
+	builtin bool
+	// editor is set when the object is being edited rather than viewed.
+	editor []string
+	obj    *s3.GetObjectOutput
+	body   io.Reader
+}
+
+// sniffBody reads the first sniffLen bytes of r to guess whether it is binary, and
+// returns a reader that still yields the whole content.
+func sniffBody(r io.Reader) (io.Reader, bool, error) {
+	sample := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, sample)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, false, err
+	}
+	sample = sample[:n]
+	return io.MultiReader(bytes.NewReader(sample), r), looksBinary(sample), nil
+}
+
+// editObject downloads an object into a temp file and opens it in the editor, asking
+// first if it looks binary.
+func (m *Model) editObject(i item) tea.Cmd {
+	editor, err := editorCommand()
+	if err != nil {
+		m.setErrorStatus(err.Error())
+		return nil
+	}
+
+	obj, err := m.client.GetObject(m.ctx, &s3.GetObjectInput{
+		Bucket: aws.String(m.bucketName),
+		Key:    aws.String(i.key),
+	})
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	body, binary, err := sniffBody(obj.Body)
+	if err != nil {
+		obj.Body.Close()
+		return func() tea.Msg { return err }
+	}
+	if binary {
+		m.binaryPrompt = &binaryPrompt{item: i, editor: editor, obj: obj, body: body}
+		return nil
+	}
+	return m.openEditor(i, editor, obj, body)
+}
+
+// openEditor writes a downloaded object, read from body, to a temp file and edits it.
+func (m *Model) openEditor(i item, editor []string, obj *s3.GetObjectOutput, body io.Reader) tea.Cmd {
+	defer obj.Body.Close()
+
+	tmpFile, err := writeToTmpFile(m.opts.tmpDir, "", body, m.bucketName+"-"+i.key)
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	originalHash, err := fileHash(tmpFile)
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+
+	return tea.ExecProcess(exec.Command(editor[0], append(editor[1:], tmpFile)...), func(err error) tea.Msg {
+		return EditFinishedMsg{
+			err:          err,
+			filename:     tmpFile,
+			key:          i.key,
+			contentType:  aws.StringValue(obj.ContentType),
+			metadata:     obj.Metadata,
+			originalHash: originalHash,
+			etag:         aws.StringValue(obj.ETag),
+		}
+	})
+}
+
+func (m Model) binaryPromptView() string {
+	return fmt.Sprintf("%s looks like a binary file. Open anyway? (y/N)", m.binaryPrompt.item.displayKey)
+}
+
+// resolveBinaryPrompt opens the object on y and drops the download on any other key.
+func (m *Model) resolveBinaryPrompt(msg tea.KeyMsg) tea.Cmd {
+	p := m.binaryPrompt
+	m.binaryPrompt = nil
+	if msg.String() != "y" && msg.String() != "Y" {
+		p.obj.Body.Close()
+		return nil
+	}
+	if p.editor != nil {
+		return m.openEditor(p.item, p.editor, p.obj, p.body)
+	}
+	return m.openViewer(p.item, p.builtin, p.obj, p.body)
+}
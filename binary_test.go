@@ -0,0 +1,88 @@
+// ABOUTME: Tests for the binary file check in binary.go.
+// ABOUTME: Covers sniffing without losing data and asking before opening binary objects.
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSniffBodyKeepsAllData(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		binary bool
+	}{
+		{"short text", []byte("hello\n"), false},
+		{"long text", bytes.Repeat([]byte("line of text\n"), 200), false},
+		{"zip", append([]byte("PK\x03\x04\x14\x00\x00\x00"), bytes.Repeat([]byte{0}, 1000)...), true},
+		{"parquet", append([]byte("PAR1\x15\x04\x15\x80"), bytes.Repeat([]byte{0xff, 0x00}, 600)...), true},
+		{"empty", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, binary, err := sniffBody(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if binary != tt.binary {
+				t.Errorf("binary = %v, want %v", binary, tt.binary)
+			}
+			got, _ := io.ReadAll(body)
+			if !bytes.Equal(got, tt.data) {
+				t.Errorf("read back %d bytes, want all %d", len(got), len(tt.data))
+			}
+		})
+	}
+}
+
+func binaryObjectModel(t *testing.T, content []byte) Model {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(content)
+	})
+	m := initialModel("test-bucket", options{noImages: true})
+	m.client = client
+	m.loading = false
+	m.list.SetItems([]list.Item{item{key: "data.bin", displayKey: "data.bin"}})
+	return m
+}
+
+func TestViewingBinaryObjectAsksFirst(t *testing.T) {
+	content := append([]byte("\x00\x01\x02"), bytes.Repeat([]byte("x"), 2000)...)
+	m := binaryObjectModel(t, content)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	m = updated.(Model)
+	if cmd != nil || m.binaryPrompt == nil || m.viewer != nil {
+		t.Fatalf("expected a prompt instead of opening the viewer")
+	}
+	if !strings.Contains(m.footer(), "data.bin looks like a binary file. Open anyway? (y/N)") {
+		t.Errorf("footer = %q", m.footer())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if m.binaryPrompt != nil || m.viewer == nil {
+		t.Fatalf("expected y to open the viewer")
+	}
+	if !strings.HasSuffix(m.viewer.body, string(content[len(content)-10:])) || !strings.Contains(m.viewer.body, "\x00\x01\x02") {
+		t.Errorf("expected the viewer to get the whole object, sampled bytes included")
+	}
+}
+
+func TestDecliningBinaryObjectOpensNothing(t *testing.T) {
+	m := binaryObjectModel(t, []byte("\x00\x01\x02\x03"))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'v'}})
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if cmd != nil || m.binaryPrompt != nil || m.viewer != nil {
+		t.Errorf("expected n to drop the download")
+	}
+}
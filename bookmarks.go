@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bookmark is a saved bucket and prefix the user can jump back to.
+type bookmark struct {
+	Label  string `json:"label"`
+	Bucket string `json:"bucket"`
+	Prefix string `json:"prefix"`
+}
+
+func (b bookmark) Title() string       { return "🔖 " + b.Label }
+func (b bookmark) Description() string { return s3URI(b.Bucket, b.Prefix) }
+func (b bookmark) FilterValue() string { return b.Label }
+
+// configDir is where s3n keeps its state, e.g. ~/.config/s3n.
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "s3n"), nil
+}
+
+func bookmarksFile() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bookmarks.json"), nil
+}
+
+// loadBookmarks reads the saved bookmarks; a missing file means there are none yet.
+func loadBookmarks() ([]bookmark, error) {
+	path, err := bookmarksFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var bookmarks []bookmark
+	if err := json.Unmarshal(data, &bookmarks); err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks from %s: %w", path, err)
+	}
+	return bookmarks, nil
+}
+
+func saveBookmarks(bookmarks []bookmark) error {
+	path, err := bookmarksFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// withBookmark adds b, replacing any bookmark for the same bucket and prefix.
+func withBookmark(bookmarks []bookmark, b bookmark) []bookmark {
+	for i, existing := range bookmarks {
+		if existing.Bucket == b.Bucket && existing.Prefix == b.Prefix {
+			bookmarks[i] = b
+			return bookmarks
+		}
+	}
+	return append(bookmarks, b)
+}
+
+// addBookmark saves the current bucket and prefix under label.
+func (m *Model) addBookmark(label string) tea.Cmd {
+	if label == "" {
+		label = fmt.Sprintf("%s/%s", m.bucketName, m.currentPrefix)
+	}
+	bookmarks, err := loadBookmarks()
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	b := bookmark{Label: label, Bucket: m.bucketName, Prefix: m.currentPrefix}
+	if err := saveBookmarks(withBookmark(bookmarks, b)); err != nil {
+		return func() tea.Msg { return err }
+	}
+	return m.setStatus(fmt.Sprintf("Bookmarked %s as %q", b.Description(), label))
+}
+
+func (m *Model) removeBookmark(b bookmark) tea.Cmd {
+	bookmarks, err := loadBookmarks()
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	var kept []bookmark
+	for _, existing := range bookmarks {
+		if existing != b {
+			kept = append(kept, existing)
+		}
+	}
+	if err := saveBookmarks(kept); err != nil {
+		return func() tea.Msg { return err }
+	}
+	m.picker.RemoveItem(m.picker.Index())
+	return m.setStatus(fmt.Sprintf("Removed bookmark %q", b.Label))
+}
+
+func (m *Model) openBookmarks() tea.Cmd {
+	bookmarks, err := loadBookmarks()
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	if len(bookmarks) == 0 {
+		return m.setStatus(fmt.Sprintf("No bookmarks yet, press %s to add one", m.keys.AddBookmark.Help().Key))
+	}
+	items := make([]list.Item, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		items = append(items, b)
+	}
+	m.openPicker(pickerBookmarks, "Bookmarks", items)
+	return nil
+}
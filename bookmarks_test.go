@@ -0,0 +1,68 @@
+// ABOUTME: Tests for saving, listing and jumping to bookmarks in bookmarks.go.
+// ABOUTME: Bookmarks are written under a temporary XDG_CONFIG_HOME.
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWithBookmarkReplacesSamePrefix(t *testing.T) {
+	bookmarks := []bookmark{{Label: "old", Bucket: "b", Prefix: "logs/"}}
+
+	bookmarks = withBookmark(bookmarks, bookmark{Label: "new", Bucket: "b", Prefix: "logs/"})
+	bookmarks = withBookmark(bookmarks, bookmark{Label: "other", Bucket: "b", Prefix: "data/"})
+
+	if len(bookmarks) != 2 || bookmarks[0].Label != "new" {
+		t.Errorf("expected the logs/ bookmark to be relabelled, got %+v", bookmarks)
+	}
+}
+
+func TestBookmarksRoundTrip(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if got, err := loadBookmarks(); err != nil || len(got) != 0 {
+		t.Fatalf("expected no bookmarks before saving, got %v, %v", got, err)
+	}
+	want := []bookmark{{Label: "logs", Bucket: "b", Prefix: "logs/2024/"}}
+	if err := saveBookmarks(want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadBookmarks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("loadBookmarks() = %+v, want %+v", got, want)
+	}
+}
+
+func TestJumpToBookmark(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := saveBookmarks([]bookmark{{Label: "deep", Bucket: "other-bucket", Prefix: "a/b/c/"}}); err != nil {
+		t.Fatal(err)
+	}
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.nextPageToken = new(string)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'\''}})
+	m = updated.(Model)
+	if m.pickerKind != pickerBookmarks {
+		t.Fatalf("expected the bookmarks picker to open")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.bucketName != "other-bucket" || m.currentPrefix != "a/b/c/" {
+		t.Errorf("expected to jump to other-bucket/a/b/c/, got %s/%s", m.bucketName, m.currentPrefix)
+	}
+	if m.nextPageToken != nil || !m.loading {
+		t.Errorf("expected pagination to be reset and a load started")
+	}
+	if m.pickerKind != pickerNone {
+		t.Errorf("expected the picker to close after jumping")
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const breadcrumbSeparator = " / "
+
+var breadcrumbSelectedStyle = lipgloss.NewStyle().Reverse(true).Bold(true)
+
+type breadcrumbKeyMap struct {
+	Left   key.Binding
+	Right  key.Binding
+	Jump   key.Binding
+	Cancel key.Binding
+}
+
+func newBreadcrumbKeyMap() breadcrumbKeyMap {
+	return breadcrumbKeyMap{
+		Left:   key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/→", "choose level")),
+		Right:  key.NewBinding(key.WithKeys("right", "l")),
+		Jump:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "go there")),
+		Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+	}
+}
+
+// breadcrumbs picks one level of the current path to jump to: segment 0 is the bucket
+// root, segment n the nth directory of the prefix.
+type breadcrumbs struct {
+	segments []string
+	selected int
+	keys     breadcrumbKeyMap
+}
+
+// breadcrumbSegments splits the shown location into the bucket and its directories.
+func breadcrumbSegments(bucket, prefix string) []string {
+	segments := []string{bucket}
+	if prefix = strings.TrimSuffix(prefix, "/"); prefix != "" {
+		segments = append(segments, strings.Split(prefix, "/")...)
+	}
+	return segments
+}
+
+// breadcrumbPrefix is the prefix of segment idx of prefix: "" for the bucket root, and
+// the first idx directories otherwise.
+func breadcrumbPrefix(prefix string, idx int) string {
+	if idx <= 0 {
+		return ""
+	}
+	dirs := strings.Split(strings.TrimSuffix(prefix, "/"), "/")
+	if idx >= len(dirs) {
+		return prefix
+	}
+	return strings.Join(dirs[:idx], "/") + "/"
+}
+
+// renderBreadcrumbs joins segments, highlighting the selected one. When they do not fit
+// in width, segments from the middle are replaced by an ellipsis; the first, the last
+// and the selected segment are always shown.
+func renderBreadcrumbs(segments []string, selected, width int) string {
+	shown := make([]bool, len(segments))
+	for i := range shown {
+		shown[i] = true
+	}
+	for _, i := range middleOut(len(segments)) {
+		if width <= 0 || breadcrumbsWidth(segments, shown) <= width {
+			break
+		}
+		if i != selected {
+			shown[i] = false
+		}
+	}
+
+	var parts []string
+	for i, s := range segments {
+		switch {
+		case shown[i] && i == selected:
+			parts = append(parts, breadcrumbSelectedStyle.Render(s))
+		case shown[i]:
+			parts = append(parts, s)
+		case shown[i-1]:
+			parts = append(parts, "…")
+		}
+	}
+	return strings.Join(parts, breadcrumbSeparator)
+}
+
+// middleOut lists the inner indexes of n segments, starting from the middle.
+func middleOut(n int) []int {
+	var order []int
+	for lo, hi := (n-1)/2, (n-1)/2+1; lo >= 1 || hi <= n-2; lo, hi = lo-1, hi+1 {
+		if lo >= 1 {
+			order = append(order, lo)
+		}
+		if hi <= n-2 {
+			order = append(order, hi)
+		}
+	}
+	return order
+}
+
+func breadcrumbsWidth(segments []string, shown []bool) int {
+	width, parts := 0, 0
+	for i, s := range segments {
+		switch {
+		case shown[i]:
+			width += lipgloss.Width(s)
+		case shown[i-1]:
+			width += lipgloss.Width("…")
+		default:
+			continue
+		}
+		parts++
+	}
+	return width + (parts-1)*lipgloss.Width(breadcrumbSeparator)
+}
+
+// openBreadcrumbs starts choosing a level of the current path, from the deepest one.
+func (m *Model) openBreadcrumbs() {
+	segments := breadcrumbSegments(m.bucketName, m.currentPrefix)
+	m.breadcrumbs = &breadcrumbs{segments: segments, selected: len(segments) - 1, keys: newBreadcrumbKeyMap()}
+}
+
+// updateBreadcrumbs handles a key press while a level is being chosen.
+func (m Model) updateBreadcrumbs(msg tea.KeyMsg) (Model, tea.Cmd) {
+	b := m.breadcrumbs
+	switch {
+	case key.Matches(msg, b.keys.Cancel):
+		m.breadcrumbs = nil
+	case key.Matches(msg, b.keys.Left):
+		b.selected = max(b.selected-1, 0)
+	case key.Matches(msg, b.keys.Right):
+		b.selected = min(b.selected+1, len(b.segments)-1)
+	case key.Matches(msg, b.keys.Jump):
+		m.breadcrumbs = nil
+		if b.selected == len(b.segments)-1 && m.searchTerm == "" {
+			return m, nil
+		}
+		cmd := m.jumpTo(m.bucketName, breadcrumbPrefix(m.currentPrefix, b.selected))
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) breadcrumbsView() string {
+	b := m.breadcrumbs
+	var help []string
+	for _, k := range []key.Binding{b.keys.Left, b.keys.Jump, b.keys.Cancel} {
+		help = append(help, helpStyleKey.Render(k.Help().Key)+" "+helpStyleVal.Render(k.Help().Desc))
+	}
+	width := m.lastWindowSize.Width - docStyle.GetHorizontalFrameSize()
+	return lipgloss.JoinVertical(lipgloss.Left, renderBreadcrumbs(b.segments, b.selected, width), strings.Join(help, " • "))
+}
@@ -0,0 +1,85 @@
+// ABOUTME: Tests for the breadcrumb path picker in breadcrumbs.go.
+// ABOUTME: Covers trimming the prefix to a chosen level, shortening long paths and the key flow.
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestBreadcrumbPrefix(t *testing.T) {
+	tests := []struct {
+		prefix string
+		idx    int
+		want   string
+	}{
+		{"logs/2024/01/", 0, ""},
+		{"logs/2024/01/", 1, "logs/"},
+		{"logs/2024/01/", 2, "logs/2024/"},
+		{"logs/2024/01/", 3, "logs/2024/01/"},
+		{"logs/2024/01/", 7, "logs/2024/01/"},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := breadcrumbPrefix(tt.prefix, tt.idx); got != tt.want {
+			t.Errorf("breadcrumbPrefix(%q, %d) = %q, want %q", tt.prefix, tt.idx, got, tt.want)
+		}
+	}
+}
+
+func TestBreadcrumbSegments(t *testing.T) {
+	got := breadcrumbSegments("bucket", "logs/2024/")
+	if strings.Join(got, ",") != "bucket,logs,2024" {
+		t.Errorf("segments = %q", got)
+	}
+	if got := breadcrumbSegments("bucket", ""); len(got) != 1 {
+		t.Errorf("expected only the bucket at the root, got %q", got)
+	}
+}
+
+func TestRenderBreadcrumbsTruncatesMiddle(t *testing.T) {
+	segments := []string{"bucket", "aaaaaaaa", "bbbbbbbb", "cccccccc", "dddddddd", "last"}
+	if got := renderBreadcrumbs(segments, 5, 0); got != strings.Join(segments, " / ") {
+		t.Errorf("expected the full path without a width, got %q", got)
+	}
+
+	got := renderBreadcrumbs(segments, 5, 40)
+	if got != "bucket / aaaaaaaa / … / dddddddd / last" {
+		t.Errorf("got %q", got)
+	}
+	if w := lipgloss.Width(got); w > 40 {
+		t.Errorf("rendered %d columns, want at most 40", w)
+	}
+
+	got = renderBreadcrumbs(segments, 3, 40)
+	if !strings.Contains(got, "cccccccc") || !strings.HasPrefix(got, "bucket / ") || !strings.HasSuffix(got, " / last") {
+		t.Errorf("expected the selected segment to stay visible, got %q", got)
+	}
+}
+
+func TestBreadcrumbsJumpUp(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.currentPrefix = "logs/2024/01/"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	m = updated.(Model)
+	if m.breadcrumbs == nil || m.breadcrumbs.selected != 3 {
+		t.Fatalf("expected P to start at the deepest level, got %+v", m.breadcrumbs)
+	}
+	for _, k := range []tea.KeyType{tea.KeyLeft, tea.KeyLeft} {
+		updated, _ = m.Update(tea.KeyMsg{Type: k})
+		m = updated.(Model)
+	}
+	if !strings.Contains(m.footer(), "2024") {
+		t.Errorf("expected the breadcrumbs in the footer, got %q", m.footer())
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.breadcrumbs != nil || m.currentPrefix != "logs/" || cmd == nil {
+		t.Errorf("expected enter to list logs/, got prefix %q", m.currentPrefix)
+	}
+}
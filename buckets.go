@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bucketEntry is a bucket shown in the bucket picker.
+type bucketEntry struct {
+	name    string
+	created time.Time
+}
+
+func (b bucketEntry) Title() string { return "🪣 " + b.name }
+func (b bucketEntry) Description() string {
+	if b.created.IsZero() {
+		return "Bucket"
+	}
+	return fmt.Sprintf("Created: %s", b.created.Format("2006-01-02 15:04:05"))
+}
+func (b bucketEntry) FilterValue() string { return b.name }
+
+type bucketsLoadedMsg struct {
+	buckets []list.Item
+}
+
+// listBuckets fetches the buckets the credentials can see for the bucket picker.
+func listBuckets(ctx context.Context, client S3API) tea.Cmd {
+	return func() tea.Msg {
+		output, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to list buckets: %w", err), retry: listBuckets(ctx, client)}
+		}
+		buckets := make([]list.Item, 0, len(output.Buckets))
+		for _, b := range output.Buckets {
+			if b.Name == nil {
+				continue
+			}
+			buckets = append(buckets, bucketEntry{name: *b.Name, created: aws.TimeValue(b.CreationDate)})
+		}
+		return bucketsLoadedMsg{buckets: buckets}
+	}
+}
+
+// openBuckets shows the loaded buckets in the picker with the current one selected. Before
+// a bucket is chosen, recent locations are offered above them.
+func (m *Model) openBuckets(buckets []list.Item) tea.Cmd {
+	if m.bucketName == "" {
+		buckets = append(m.recentItems(), buckets...)
+	}
+	if len(buckets) == 0 {
+		return m.setStatus("No buckets found")
+	}
+	m.openPicker(pickerBuckets, "Buckets", buckets)
+	for i, b := range buckets {
+		if b, ok := b.(bucketEntry); ok && b.name == m.bucketName {
+			m.picker.Select(i)
+			break
+		}
+	}
+	return nil
+}
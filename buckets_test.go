@@ -0,0 +1,88 @@
+// ABOUTME: Tests for the bucket picker in buckets.go.
+// ABOUTME: Covers selecting the current bucket, switching buckets and starting without a bucket.
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPickingBucketSwitchesAndResetsPrefix(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.currentPrefix = "logs/"
+	m.nextPageToken = new(string)
+
+	updated, _ := m.Update(bucketsLoadedMsg{buckets: []list.Item{
+		bucketEntry{name: "other-bucket"},
+		bucketEntry{name: "test-bucket"},
+	}})
+	m = updated.(Model)
+	if m.pickerKind != pickerBuckets {
+		t.Fatalf("expected the bucket picker to open")
+	}
+	if b := m.picker.SelectedItem().(bucketEntry); b.name != "test-bucket" {
+		t.Errorf("picker selected %q, want the current bucket", b.name)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.bucketName != "other-bucket" || m.currentPrefix != "" || m.nextPageToken != nil || !m.loading {
+		t.Errorf("bucket=%q prefix=%q token=%v loading=%v, want a fresh listing of other-bucket",
+			m.bucketName, m.currentPrefix, m.nextPageToken, m.loading)
+	}
+}
+
+func TestNoBucketsShowsStatus(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+
+	updated, _ := m.Update(bucketsLoadedMsg{})
+	m = updated.(Model)
+	if m.pickerKind != pickerNone || m.statusMsg != "No buckets found" {
+		t.Errorf("picker=%v status=%q, want no picker and a status message", m.pickerKind, m.statusMsg)
+	}
+}
+
+func TestNoBucketArgumentStartsOnBucketList(t *testing.T) {
+	m := initialModel("", options{})
+	if m.Init() == nil {
+		t.Fatal("expected Init to list buckets")
+	}
+
+	updated, _ := m.Update(bucketsLoadedMsg{buckets: []list.Item{
+		bucketEntry{name: "logs"},
+		bucketEntry{name: "photos"},
+	}})
+	m = updated.(Model)
+	if m.pickerKind != pickerBuckets {
+		t.Fatalf("expected the bucket list as the first screen")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.pickerKind != pickerBuckets {
+		t.Fatalf("expected esc to keep the bucket list open before a bucket is picked")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.pickerKind != pickerNone || m.bucketName != "photos" || m.currentPrefix != "" || !m.loading {
+		t.Fatalf("picker=%v bucket=%q prefix=%q loading=%v, want the object browser for photos",
+			m.pickerKind, m.bucketName, m.currentPrefix, m.loading)
+	}
+}
+
+func TestBackFromBucketRootListsBuckets(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+	if cmd == nil || m.statusMsg != "Loading buckets..." {
+		t.Errorf("expected back at the bucket root to load the bucket list, status %q", m.statusMsg)
+	}
+}
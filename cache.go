@@ -0,0 +1,197 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+const (
+	// listingCacheSize is how many first pages of listings are kept.
+	listingCacheSize = 32
+	// listingCacheTTL bounds how stale a cached listing can be.
+	listingCacheTTL = 30 * time.Second
+)
+
+type listingKey struct {
+	bucket     string
+	prefix     string
+	searchTerm string
+	// contentTypes is set for listings that fetched each object's Content-Type.
+	contentTypes bool
+}
+
+// dirKey names a directory whose children were counted or whose tree was summarized.
+type dirKey struct {
+	bucket string
+	prefix string
+}
+
+type cachedListing struct {
+	key    listingKey
+	msg    itemsLoadedMsg
+	stored time.Time
+}
+
+// listingCache is a small LRU of first listing pages, so going back to a prefix that was
+// just shown needs no request. It also keeps the peeked child counts and the summaries of
+// directories, for the session, until something under them changes. It is shared by
+// every copy of the Model, and loadItems uses it from commands, hence the lock. A nil
+// cache stores nothing.
+type listingCache struct {
+	mu        sync.Mutex
+	entries   []cachedListing // most recently used first
+	counts    map[dirKey]dirCount
+	summaries map[dirKey]*prefixSummary
+	now       func() time.Time
+}
+
+func newListingCache() *listingCache {
+	return &listingCache{counts: map[dirKey]dirCount{}, summaries: map[dirKey]*prefixSummary{}, now: time.Now}
+}
+
+func (c *listingCache) get(k listingKey) (itemsLoadedMsg, bool) {
+	if c == nil {
+		return itemsLoadedMsg{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for idx, e := range c.entries {
+		if e.key != k {
+			continue
+		}
+		if c.now().Sub(e.stored) > listingCacheTTL {
+			c.entries = append(c.entries[:idx], c.entries[idx+1:]...)
+			return itemsLoadedMsg{}, false
+		}
+		copy(c.entries[1:idx+1], c.entries[:idx])
+		c.entries[0] = e
+		msg := e.msg
+		// Callers append further pages to the items; keep them off the cached array.
+		msg.items = append([]list.Item(nil), e.msg.items...)
+		return msg, true
+	}
+	return itemsLoadedMsg{}, false
+}
+
+func (c *listingCache) put(k listingKey, msg itemsLoadedMsg) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(func(e cachedListing) bool { return e.key == k })
+	msg.items = append([]list.Item(nil), msg.items...)
+	c.entries = append([]cachedListing{{key: k, msg: msg, stored: c.now()}}, c.entries...)
+	if len(c.entries) > listingCacheSize {
+		c.entries = c.entries[:listingCacheSize]
+	}
+}
+
+// getCount returns the peeked child count of the directory prefix in bucket.
+func (c *listingCache) getCount(bucket, prefix string) (dirCount, bool) {
+	if c == nil {
+		return dirCount{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count, ok := c.counts[dirKey{bucket: bucket, prefix: prefix}]
+	return count, ok
+}
+
+func (c *listingCache) putCount(bucket, prefix string, count dirCount) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[dirKey{bucket: bucket, prefix: prefix}] = count
+}
+
+// getSummary returns the summary of everything under prefix in bucket.
+func (c *listingCache) getSummary(bucket, prefix string) (*prefixSummary, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.summaries[dirKey{bucket: bucket, prefix: prefix}]
+	return s, ok
+}
+
+func (c *listingCache) putSummary(s *prefixSummary) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.summaries[dirKey{bucket: s.bucket, prefix: s.prefix}] = s
+}
+
+// invalidate drops the cached listing of one location, and the counts and summaries of
+// the directories in it.
+func (c *listingCache) invalidate(k listingKey) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(func(e cachedListing) bool { return e.key == k })
+	c.removeDirs(func(d dirKey) bool { return d.bucket == k.bucket && strings.HasPrefix(d.prefix, k.prefix) })
+}
+
+// invalidateKey drops every cached listing in bucket that could show objectKey, or a
+// directory containing it, after the object was created, changed or deleted.
+func (c *listingCache) invalidateKey(bucket, objectKey string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(func(e cachedListing) bool {
+		return e.key.bucket == bucket && strings.HasPrefix(objectKey, e.key.prefix+e.key.searchTerm)
+	})
+	c.removeDirs(func(d dirKey) bool { return d.bucket == bucket && strings.HasPrefix(objectKey, d.prefix) })
+}
+
+// invalidatePrefix drops what invalidateKey does for prefix, plus every listing beneath
+// it, after everything under prefix was deleted.
+func (c *listingCache) invalidatePrefix(bucket, prefix string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(func(e cachedListing) bool {
+		listed := e.key.prefix + e.key.searchTerm
+		return e.key.bucket == bucket && (strings.HasPrefix(prefix, listed) || strings.HasPrefix(listed, prefix))
+	})
+	c.removeDirs(func(d dirKey) bool {
+		return d.bucket == bucket && (strings.HasPrefix(prefix, d.prefix) || strings.HasPrefix(d.prefix, prefix))
+	})
+}
+
+func (c *listingCache) remove(match func(cachedListing) bool) {
+	kept := c.entries[:0]
+	for _, e := range c.entries {
+		if !match(e) {
+			kept = append(kept, e)
+		}
+	}
+	c.entries = kept
+}
+
+func (c *listingCache) removeDirs(match func(dirKey) bool) {
+	for d := range c.counts {
+		if match(d) {
+			delete(c.counts, d)
+		}
+	}
+	for d := range c.summaries {
+		if match(d) {
+			delete(c.summaries, d)
+		}
+	}
+}
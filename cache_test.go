@@ -0,0 +1,87 @@
+// ABOUTME: Tests for the listing cache in cache.go.
+// ABOUTME: Covers hits, expiry, LRU eviction, invalidation and serving Back from the cache.
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func testListing(keys ...string) itemsLoadedMsg {
+	var items []list.Item
+	for _, k := range keys {
+		items = append(items, item{key: k, displayKey: k})
+	}
+	return itemsLoadedMsg{items: items}
+}
+
+func TestListingCacheExpires(t *testing.T) {
+	now := time.Now()
+	c := newListingCache()
+	c.now = func() time.Time { return now }
+	k := listingKey{bucket: "b", prefix: "logs/"}
+	c.put(k, testListing("logs/a"))
+
+	if msg, ok := c.get(k); !ok || len(msg.items) != 1 {
+		t.Fatalf("expected a cache hit, got %v %v", msg, ok)
+	}
+	now = now.Add(listingCacheTTL + time.Second)
+	if _, ok := c.get(k); ok {
+		t.Errorf("expected the entry to expire after the TTL")
+	}
+}
+
+func TestListingCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newListingCache()
+	first := listingKey{bucket: "b", prefix: "0/"}
+	for i := 0; i < listingCacheSize; i++ {
+		c.put(listingKey{bucket: "b", prefix: fmt.Sprintf("%d/", i)}, testListing())
+	}
+	c.get(first)
+	c.put(listingKey{bucket: "b", prefix: "new/"}, testListing())
+
+	if _, ok := c.get(first); !ok {
+		t.Errorf("recently used entry was evicted")
+	}
+	if _, ok := c.get(listingKey{bucket: "b", prefix: "1/"}); ok {
+		t.Errorf("expected the least recently used entry to be evicted")
+	}
+}
+
+func TestListingCacheInvalidateKey(t *testing.T) {
+	c := newListingCache()
+	root := listingKey{bucket: "b"}
+	logs := listingKey{bucket: "b", prefix: "logs/"}
+	other := listingKey{bucket: "b", prefix: "data/"}
+	otherBucket := listingKey{bucket: "c", prefix: "logs/"}
+	for _, k := range []listingKey{root, logs, other, otherBucket} {
+		c.put(k, testListing())
+	}
+
+	c.invalidateKey("b", "logs/new/a.txt")
+	for k, want := range map[listingKey]bool{root: false, logs: false, other: true, otherBucket: true} {
+		if _, ok := c.get(k); ok != want {
+			t.Errorf("%+v cached = %v, want %v", k, ok, want)
+		}
+	}
+}
+
+func TestBackIsServedFromCache(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.cache.put(listingKey{bucket: "test-bucket"}, testListing("a.txt"))
+	m.currentPrefix = "logs/"
+
+	// nil client: a real listing request would panic.
+	m.client = nil
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+	msg, ok := operationResult(t, cmd).msg.(itemsLoadedMsg)
+	if !ok || len(msg.items) != 1 {
+		t.Fatalf("expected the parent listing from the cache, got %#v", msg)
+	}
+}
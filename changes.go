@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// changeHighlightDuration is how long new and modified items stay marked after a reload.
+const changeHighlightDuration = 5 * time.Second
+
+// change is how an item differs from the listing shown before a reload.
+type change int
+
+const (
+	unchanged change = iota
+	added
+	modified
+)
+
+// changesFadedMsg clears the markers of the reload with the same id.
+type changesFadedMsg struct {
+	id int
+}
+
+// diffListings compares a reloaded listing with the one shown before it, by key. Keys
+// that were not shown are added; objects with a newer modification time or another ETag
+// are modified. Keys that are gone are not reported, as there is nothing left to mark.
+func diffListings(before, after []list.Item) map[string]change {
+	old := make(map[string]item, len(before))
+	for _, it := range before {
+		if i, ok := it.(item); ok {
+			old[i.key] = i
+		}
+	}
+	changes := map[string]change{}
+	for _, it := range after {
+		i, ok := it.(item)
+		if !ok {
+			continue
+		}
+		prev, seen := old[i.key]
+		switch {
+		case !seen:
+			changes[i.key] = added
+		case i.isDir:
+		case i.modified.After(prev.modified), i.etag != "" && prev.etag != "" && i.etag != prev.etag:
+			changes[i.key] = modified
+		}
+	}
+	return changes
+}
+
+// describeChanges counts the changes for the status bar, e.g. "2 new, 1 modified".
+func describeChanges(changes map[string]change) string {
+	var n, m int
+	for _, c := range changes {
+		if c == added {
+			n++
+		} else if c == modified {
+			m++
+		}
+	}
+	var parts []string
+	if n > 0 {
+		parts = append(parts, fmt.Sprintf("%d new", n))
+	}
+	if m > 0 {
+		parts = append(parts, fmt.Sprintf("%d modified", m))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// withChanges marks the items changed by the last reload, in place.
+func (m *Model) withChanges(items []list.Item) []list.Item {
+	for idx, it := range items {
+		if i, ok := it.(item); ok {
+			i.change = m.changes[i.key]
+			items[idx] = i
+		}
+	}
+	return items
+}
+
+// highlightChanges keeps the changes of a reload marked until changeHighlightDuration has
+// passed; an earlier highlight still fading is replaced.
+func (m *Model) highlightChanges(changes map[string]change) tea.Cmd {
+	m.changes = changes
+	m.changesID++
+	id := m.changesID
+	return tea.Tick(changeHighlightDuration, func(time.Time) tea.Msg { return changesFadedMsg{id: id} })
+}
+
+// fadeChanges removes the markers of the reload id, unless a later one replaced them.
+func (m *Model) fadeChanges(id int) {
+	if id != m.changesID || m.changes == nil {
+		return
+	}
+	m.changes = nil
+	m.currentItems = m.withChanges(m.currentItems)
+	m.list.SetItems(m.visibleItems())
+}
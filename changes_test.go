@@ -0,0 +1,92 @@
+// ABOUTME: Tests for marking what a reload added or modified, in changes.go.
+// ABOUTME: Covers classifying items between two listings and the markers fading after a reload.
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestDiffListings(t *testing.T) {
+	then := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := []list.Item{
+		item{key: "logs/", isDir: true},
+		item{key: "same.txt", modified: then, etag: `"a"`},
+		item{key: "touched.txt", modified: then, etag: `"b"`},
+		item{key: "rewritten.txt", modified: then, etag: `"c"`},
+		item{key: "gone.txt", modified: then},
+	}
+	after := []list.Item{
+		item{key: "logs/", isDir: true},
+		item{key: "new/", isDir: true},
+		item{key: "same.txt", modified: then, etag: `"a"`},
+		item{key: "touched.txt", modified: then.Add(time.Minute), etag: `"b"`},
+		item{key: "rewritten.txt", modified: then, etag: `"d"`},
+		item{key: "new.txt", modified: then},
+	}
+	want := map[string]change{"new/": added, "new.txt": added, "touched.txt": modified, "rewritten.txt": modified}
+	if got := diffListings(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffListings() = %v, want %v", got, want)
+	}
+	if got := describeChanges(want); got != "2 new, 2 modified" {
+		t.Errorf("describeChanges() = %q", got)
+	}
+}
+
+func TestReloadMarksChangesUntilTheyFade(t *testing.T) {
+	then := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "a.txt", displayKey: "a.txt", modified: then},
+		item{key: "b.txt", displayKey: "b.txt", modified: then},
+	}})
+	m = updated.(Model)
+	if m.changes != nil {
+		t.Fatalf("expected nothing marked on the first listing")
+	}
+
+	updated, _ = m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "a.txt", displayKey: "a.txt", modified: then},
+		item{key: "b.txt", displayKey: "b.txt", modified: then.Add(time.Hour)},
+		item{key: "c.txt", displayKey: "c.txt", modified: then},
+	}})
+	m = updated.(Model)
+	var titles []string
+	for _, it := range m.list.Items() {
+		titles = append(titles, it.(item).Title())
+	}
+	if want := []string{"📄 a.txt", "🟡 b.txt", "🟢 c.txt"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("titles = %v, want %v", titles, want)
+	}
+	if !strings.HasSuffix(m.statusMsg, "1 new, 1 modified since the last listing") {
+		t.Errorf("status = %q, want the changes counted", m.statusMsg)
+	}
+
+	updated, _ = m.Update(changesFadedMsg{id: m.changesID - 1})
+	if m = updated.(Model); m.changes == nil {
+		t.Fatalf("expected the fade of an earlier reload to be ignored")
+	}
+	updated, _ = m.Update(changesFadedMsg{id: m.changesID})
+	m = updated.(Model)
+	for _, it := range m.list.Items() {
+		if i := it.(item); i.change != unchanged {
+			t.Errorf("expected %s to be unmarked after fading", i.key)
+		}
+	}
+}
+
+func TestListingAnotherPrefixMarksNothing(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{item{key: "a.txt", displayKey: "a.txt"}}})
+	m = updated.(Model)
+
+	m.currentPrefix = "logs/"
+	updated, _ = m.Update(itemsLoadedMsg{items: []list.Item{item{key: "logs/b.txt", displayKey: "b.txt"}}})
+	if m = updated.(Model); m.changes != nil {
+		t.Errorf("expected a different listing not to be compared, got %v", m.changes)
+	}
+}
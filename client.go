@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// credentialsCheckTimeout bounds the pre-flight lookup, which may query instance metadata.
+const credentialsCheckTimeout = 10 * time.Second
+
+const credentialsHelp = `No AWS credentials were found. s3n uses the standard AWS configuration, so set up one of:
+
+  - a profile in ~/.aws/credentials, e.g. with "aws configure"
+    https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-files.html
+  - AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+    https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-envvars.html
+  - an SSO session with "aws sso login", selected with AWS_PROFILE
+
+For public buckets, run s3n with --no-sign-request instead.`
+
+// S3API is the part of the S3 client s3n calls. *s3.Client implements it; tests pass a
+// fake instead of talking to S3.
+type S3API interface {
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	ListObjectVersions(ctx context.Context, params *s3.ListObjectVersionsInput, optFns ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	RestoreObject(ctx context.Context, params *s3.RestoreObjectInput, optFns ...func(*s3.Options)) (*s3.RestoreObjectOutput, error)
+	GetObjectTagging(ctx context.Context, params *s3.GetObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error)
+	PutObjectTagging(ctx context.Context, params *s3.PutObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.PutObjectTaggingOutput, error)
+	GetObjectRetention(ctx context.Context, params *s3.GetObjectRetentionInput, optFns ...func(*s3.Options)) (*s3.GetObjectRetentionOutput, error)
+	GetObjectLegalHold(ctx context.Context, params *s3.GetObjectLegalHoldInput, optFns ...func(*s3.Options)) (*s3.GetObjectLegalHoldOutput, error)
+}
+
+var _ S3API = (*s3.Client)(nil)
+
+// newS3Client builds the S3 client from the default AWS configuration chain.
+func newS3Client(opts options) (*s3.Client, error) {
+	cfg, err := loadConfig(context.TODO(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.noSignRequest {
+		cfg.Credentials = awsv2.AnonymousCredentials{}
+	}
+
+	return s3.NewFromConfig(cfg, s3ClientOptions(opts)), nil
+}
+
+// loadOptions applies --region and --profile; left out, the SDK resolves them as usual
+// from AWS_REGION, AWS_PROFILE and the shared config files.
+func loadOptions(opts options) []func(*config.LoadOptions) error {
+	var loadOpts []func(*config.LoadOptions) error
+	if opts.region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(opts.region))
+	}
+	if opts.profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(opts.profile))
+	}
+	return loadOpts
+}
+
+// loadConfig loads the default AWS configuration. With --role-arn, the credentials it
+// resolves are only used to assume the role, and requests are signed with the role's.
+func loadConfig(ctx context.Context, opts options) (awsv2.Config, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions(opts)...)
+	if err != nil || opts.roleARN == "" {
+		return cfg, err
+	}
+	return config.LoadDefaultConfig(ctx, append(loadOptions(opts), config.WithCredentialsProvider(assumeRoleProvider(cfg, opts)))...)
+}
+
+// assumedRole names the role in the errors of assuming it, which otherwise only say that
+// STS refused.
+type assumedRole struct {
+	arn      string
+	provider awsv2.CredentialsProvider
+}
+
+func (r assumedRole) Retrieve(ctx context.Context) (awsv2.Credentials, error) {
+	creds, err := r.provider.Retrieve(ctx)
+	if err != nil {
+		return creds, fmt.Errorf("failed to assume role %s: %w", r.arn, err)
+	}
+	return creds, nil
+}
+
+// assumeRoleProvider assumes --role-arn with the credentials of cfg, passing
+// --role-session-name and --external-id along.
+func assumeRoleProvider(cfg awsv2.Config, opts options) awsv2.CredentialsProvider {
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), opts.roleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = opts.roleSessionName
+		if opts.externalID != "" {
+			o.ExternalID = aws.String(opts.externalID)
+		}
+	})
+	return assumedRole{arn: opts.roleARN, provider: provider}
+}
+
+// s3ClientOptions points the client at --endpoint, e.g. LocalStack or MinIO, when one is
+// given; otherwise the SDK resolves the AWS endpoint for the region as usual. Requests
+// are retried --max-attempts times.
+func s3ClientOptions(opts options) func(*s3.Options) {
+	return func(o *s3.Options) {
+		if opts.endpoint != "" {
+			o.BaseEndpoint = aws.String(opts.endpoint)
+		}
+		if opts.pathStyle {
+			o.UsePathStyle = true
+		}
+		if opts.maxAttempts > 0 {
+			o.Retryer = newRetryer(opts.maxAttempts, maxRetryBackoff)
+		}
+	}
+}
+
+// checkCredentials makes sure credentials can be resolved before the TUI starts, since
+// otherwise the first listing fails with an error that does not say what to set up.
+// Anonymous access needs no credentials and is not checked.
+func checkCredentials(opts options) error {
+	if opts.noSignRequest {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), credentialsCheckTimeout)
+	defer cancel()
+	cfg, err := loadConfig(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if cfg.Credentials == nil {
+		return fmt.Errorf("no credentials provider configured")
+	}
+	_, err = cfg.Credentials.Retrieve(ctx)
+	return err
+}
@@ -0,0 +1,181 @@
+// ABOUTME: Tests for S3 client setup in client.go.
+// ABOUTME: Covers region/profile and endpoint options, the pre-flight credentials check, anonymous mode and assuming a role.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// isolateAWSConfig hides the user's AWS configuration and instance metadata from a test.
+func isolateAWSConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	for _, name := range []string{"AWS_PROFILE", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_WEB_IDENTITY_TOKEN_FILE"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_REGION", "us-east-1")
+}
+
+func TestCheckCredentialsFailsWithoutCredentials(t *testing.T) {
+	isolateAWSConfig(t)
+	if err := checkCredentials(options{}); err == nil {
+		t.Errorf("expected missing credentials to be reported")
+	}
+}
+
+func TestCheckCredentialsFromEnvironment(t *testing.T) {
+	isolateAWSConfig(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	if err := checkCredentials(options{}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestCheckCredentialsSkippedWhenAnonymous(t *testing.T) {
+	isolateAWSConfig(t)
+	if err := checkCredentials(options{noSignRequest: true}); err != nil {
+		t.Errorf("expected anonymous mode to skip the check, got %v", err)
+	}
+}
+
+func TestS3ClientOptionsOnlyOverrideEndpointWhenSet(t *testing.T) {
+	var o s3.Options
+	s3ClientOptions(options{})(&o)
+	if o.BaseEndpoint != nil || o.UsePathStyle {
+		t.Errorf("expected the default resolver without --endpoint, got %v path-style=%v", o.BaseEndpoint, o.UsePathStyle)
+	}
+
+	o = s3.Options{}
+	s3ClientOptions(options{endpoint: "http://localhost:4566", pathStyle: true})(&o)
+	if o.BaseEndpoint == nil || *o.BaseEndpoint != "http://localhost:4566" || !o.UsePathStyle {
+		t.Errorf("expected the endpoint override with path-style, got %v path-style=%v", o.BaseEndpoint, o.UsePathStyle)
+	}
+}
+
+func TestLoadOptionsFromFlags(t *testing.T) {
+	if got := loadOptions(options{}); len(got) != 0 {
+		t.Errorf("expected SDK defaults without flags, got %d options", len(got))
+	}
+
+	var lo config.LoadOptions
+	for _, apply := range loadOptions(options{region: "eu-west-1", profile: "prod"}) {
+		if err := apply(&lo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lo.Region != "eu-west-1" || lo.SharedConfigProfile != "prod" {
+		t.Errorf("region=%q profile=%q, want the flag values", lo.Region, lo.SharedConfigProfile)
+	}
+}
+
+// stsServer answers AssumeRole with temporary credentials, recording the form of each
+// request, or with AccessDenied when deny is set.
+func stsServer(t *testing.T, deny bool) (*httptest.Server, *[]map[string]string) {
+	t.Helper()
+	var requests []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		form := map[string]string{}
+		for name := range r.PostForm {
+			form[name] = r.PostForm.Get(name)
+		}
+		requests = append(requests, form)
+		w.Header().Set("Content-Type", "text/xml")
+		if deny {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `<ErrorResponse><Error><Type>Sender</Type><Code>AccessDenied</Code><Message>not authorized to perform sts:AssumeRole</Message></Error></ErrorResponse>`)
+			return
+		}
+		fmt.Fprint(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>
+<AccessKeyId>ASIAROLE</AccessKeyId><SecretAccessKey>role-secret</SecretAccessKey><SessionToken>role-token</SessionToken>
+<Expiration>2099-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestAssumeRoleProviderPassesTheFlags(t *testing.T) {
+	srv, requests := stsServer(t, false)
+	cfg := awsv2.Config{
+		Region:       "us-east-1",
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDBASE", "base-secret", ""),
+		BaseEndpoint: awsv2.String(srv.URL),
+	}
+	provider := assumeRoleProvider(cfg, options{roleARN: "arn:aws:iam::123456789012:role/reader", roleSessionName: "alice", externalID: "xyz"})
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.AccessKeyID != "ASIAROLE" || creds.SessionToken != "role-token" {
+		t.Errorf("expected the role's credentials, got %+v", creds)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("expected one AssumeRole request, got %d", len(*requests))
+	}
+	got := (*requests)[0]
+	want := map[string]string{"Action": "AssumeRole", "RoleArn": "arn:aws:iam::123456789012:role/reader", "RoleSessionName": "alice", "ExternalId": "xyz"}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestAssumeRoleProviderLeavesOutEmptyExternalID(t *testing.T) {
+	srv, requests := stsServer(t, false)
+	cfg := awsv2.Config{Region: "us-east-1", Credentials: credentials.NewStaticCredentialsProvider("AKIDBASE", "base-secret", ""), BaseEndpoint: awsv2.String(srv.URL)}
+	if _, err := assumeRoleProvider(cfg, options{roleARN: "arn:aws:iam::123456789012:role/reader", roleSessionName: "s3n"}).Retrieve(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := (*requests)[0]["ExternalId"]; ok {
+		t.Errorf("expected no ExternalId without --external-id")
+	}
+}
+
+func TestCheckCredentialsNamesTheRoleItCannotAssume(t *testing.T) {
+	isolateAWSConfig(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	srv, requests := stsServer(t, true)
+	t.Setenv("AWS_ENDPOINT_URL_STS", srv.URL)
+
+	err := checkCredentials(options{roleARN: "arn:aws:iam::123456789012:role/reader", roleSessionName: "s3n"})
+	if err == nil || !strings.Contains(err.Error(), "arn:aws:iam::123456789012:role/reader") {
+		t.Errorf("expected the error to name the role, got %v", err)
+	}
+	if len(*requests) == 0 {
+		t.Errorf("expected the role to be assumed through STS")
+	}
+}
+
+func TestCheckCredentialsWithoutRoleSkipsSTS(t *testing.T) {
+	isolateAWSConfig(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	srv, requests := stsServer(t, true)
+	t.Setenv("AWS_ENDPOINT_URL_STS", srv.URL)
+
+	if err := checkCredentials(options{}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no role to be assumed without --role-arn")
+	}
+}
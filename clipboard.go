@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/atotto/clipboard"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// maxClipboardBytes is the largest object whose content is copied to the clipboard.
+const maxClipboardBytes = 1 << 20
+
+type contentCopiedMsg struct {
+	key   string
+	bytes int
+}
+
+// textCopiedMsg reports text put on the clipboard.
+type textCopiedMsg struct {
+	text string
+}
+
+// clipboardReference is what copying i's name puts on the clipboard: its key, or its
+// s3:// URI when uri is set. A directory gives its prefix, with the trailing slash.
+func clipboardReference(bucket string, i item, uri bool) string {
+	if uri {
+		return s3URI(bucket, i.key)
+	}
+	return i.key
+}
+
+// copyReference puts the key or s3:// URI of i on the clipboard.
+func (m *Model) copyReference(i item, uri bool) tea.Cmd {
+	text := clipboardReference(m.bucketName, i, uri)
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err != nil {
+			return fmt.Errorf("failed to write to the clipboard: %w", err)
+		}
+		return textCopiedMsg{text: text}
+	}
+}
+
+// copyContent downloads a small text object and puts its content on the clipboard.
+func (m *Model) copyContent(i item) tea.Cmd {
+	if i.isDir {
+		return m.setStatus("Cannot copy the content of a directory")
+	}
+	if i.size > maxClipboardBytes {
+		return m.setStatus(fmt.Sprintf("%s is %s, larger than the %s clipboard limit", i.displayKey, humanize.Bytes(uint64(i.size)), humanize.Bytes(maxClipboardBytes)))
+	}
+	ctx, client, bucket := m.ctx, m.client, m.bucketName
+	return func() tea.Msg {
+		obj, err := client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(i.key),
+		})
+		if err != nil {
+			return err
+		}
+		defer obj.Body.Close()
+
+		data, err := io.ReadAll(io.LimitReader(obj.Body, maxClipboardBytes+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > maxClipboardBytes {
+			return fmt.Errorf("%s is larger than the %s clipboard limit", i.key, humanize.Bytes(maxClipboardBytes))
+		}
+		if looksBinary(data) {
+			return fmt.Errorf("%s looks like a binary file, not copying it to the clipboard", i.key)
+		}
+		if err := clipboard.WriteAll(string(data)); err != nil {
+			return fmt.Errorf("failed to write to the clipboard: %w", err)
+		}
+		return contentCopiedMsg{key: i.key, bytes: len(data)}
+	}
+}
@@ -0,0 +1,40 @@
+// ABOUTME: Tests for copying object content, keys and URIs to the clipboard in clipboard.go.
+// ABOUTME: Covers the checks made before anything is downloaded and the key and URI references copied.
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCopyContentRejectsDirectoriesAndLargeFiles(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+
+	m.copyContent(item{key: "logs/", displayKey: "logs", isDir: true})
+	if !strings.Contains(m.statusMsg, "directory") {
+		t.Errorf("expected directories to be rejected, got %q", m.statusMsg)
+	}
+
+	m.copyContent(item{key: "big.log", displayKey: "big.log", size: maxClipboardBytes + 1})
+	if !strings.Contains(m.statusMsg, "clipboard limit") {
+		t.Errorf("expected large objects to be rejected before downloading, got %q", m.statusMsg)
+	}
+}
+
+func TestClipboardReference(t *testing.T) {
+	tests := []struct {
+		item item
+		uri  bool
+		want string
+	}{
+		{item{key: "logs/app.log"}, false, "logs/app.log"},
+		{item{key: "logs/app.log"}, true, "s3://test-bucket/logs/app.log"},
+		{item{key: "logs/2024/", isDir: true}, false, "logs/2024/"},
+		{item{key: "logs/2024/", isDir: true}, true, "s3://test-bucket/logs/2024/"},
+	}
+	for _, tt := range tests {
+		if got := clipboardReference("test-bucket", tt.item, tt.uri); got != tt.want {
+			t.Errorf("clipboardReference(%q, uri=%v) = %q, want %q", tt.item.key, tt.uri, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// lookPath finds programs on PATH; tests replace it.
+var lookPath = exec.LookPath
+
+// resolveCommand returns the first installed command among the values of envVars and
+// then fallbacks, split into program and arguments. Unset variables are skipped.
+func resolveCommand(envVars []string, fallbacks []string) ([]string, error) {
+	var candidates []string
+	for _, name := range envVars {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			candidates = append(candidates, v)
+		}
+	}
+	candidates = append(candidates, fallbacks...)
+	return firstInstalled(candidates)
+}
+
+func firstInstalled(candidates []string) ([]string, error) {
+	var tried []string
+	for _, c := range candidates {
+		fields := strings.Fields(c)
+		if len(fields) == 0 {
+			continue
+		}
+		if _, err := lookPath(fields[0]); err == nil {
+			return fields, nil
+		}
+		tried = append(tried, fields[0])
+	}
+	return nil, fmt.Errorf("none of %s found on PATH", strings.Join(tried, ", "))
+}
+
+// pagerCommand returns the pager to view objects with: pager when set, otherwise
+// $PAGER, less or more.
+func pagerCommand(pager string) ([]string, error) {
+	if pager != "" {
+		return firstInstalled([]string{pager})
+	}
+	return resolveCommand([]string{"PAGER"}, []string{"less", "more"})
+}
+
+// streamCommand runs pager reading header and then body from stdin, so an object is
+// shown while it downloads instead of after it is written to a temp file.
+func streamCommand(pager []string, header string, body io.Reader) *exec.Cmd {
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = io.MultiReader(strings.NewReader(header), body)
+	return cmd
+}
+
+// editorCommand returns the editor to edit objects with: $EDITOR, $VISUAL, or the
+// platform's default editor.
+func editorCommand() ([]string, error) {
+	fallback := "vi"
+	if runtime.GOOS == "windows" {
+		fallback = "notepad"
+	}
+	editor, err := resolveCommand([]string{"EDITOR", "VISUAL"}, []string{fallback})
+	if err != nil {
+		return nil, fmt.Errorf("no editor found, set $EDITOR: %w", err)
+	}
+	return editor, nil
+}
+
+// openerCommand returns the program that opens a file with its default application on
+// goos; the file name is appended to it. The empty argument to start is the window title,
+// without which a quoted file name would be taken as the title.
+func openerCommand(goos string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"open"}
+	case "windows":
+		return []string{"cmd", "/c", "start", ""}
+	default:
+		return []string{"xdg-open"}
+	}
+}
+
+// systemOpener returns the opener for this platform, or an error when it is not installed.
+func systemOpener() ([]string, error) {
+	opener := openerCommand(runtime.GOOS)
+	if _, err := lookPath(opener[0]); err != nil {
+		return nil, fmt.Errorf("cannot open files with their default application: %s not found on PATH", opener[0])
+	}
+	return opener, nil
+}
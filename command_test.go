@@ -0,0 +1,138 @@
+// ABOUTME: Tests for finding the pager, editor and system opener in command.go.
+// ABOUTME: Covers environment variables, fallbacks, reporting when nothing is installed and streaming into the pager.
+package main
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// installed makes lookPath find only the given programs for the rest of the test.
+func installed(t *testing.T, programs ...string) {
+	old := lookPath
+	t.Cleanup(func() { lookPath = old })
+	lookPath = func(file string) (string, error) {
+		for _, p := range programs {
+			if p == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestResolveCommand(t *testing.T) {
+	installed(t, "nano", "vi")
+	t.Setenv("S3N_TEST_EDITOR", "")
+	t.Setenv("S3N_TEST_VISUAL", "nano -w")
+
+	got, err := resolveCommand([]string{"S3N_TEST_EDITOR", "S3N_TEST_VISUAL"}, []string{"vi"})
+	if err != nil || !reflect.DeepEqual(got, []string{"nano", "-w"}) {
+		t.Errorf("expected the first set variable split into arguments, got %v %v", got, err)
+	}
+
+	t.Setenv("S3N_TEST_VISUAL", "code --wait")
+	got, err = resolveCommand([]string{"S3N_TEST_EDITOR", "S3N_TEST_VISUAL"}, []string{"vi"})
+	if err != nil || !reflect.DeepEqual(got, []string{"vi"}) {
+		t.Errorf("expected an uninstalled command to fall through to vi, got %v %v", got, err)
+	}
+
+	_, err = resolveCommand([]string{"S3N_TEST_VISUAL"}, []string{"emacs"})
+	if err == nil || err.Error() != "none of code, emacs found on PATH" {
+		t.Errorf("expected every tried program in the error, got %v", err)
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	installed(t, "bat", "most", "more")
+	t.Setenv("PAGER", "most")
+	if got, _ := pagerCommand("bat --style=plain"); !reflect.DeepEqual(got, []string{"bat", "--style=plain"}) {
+		t.Errorf("expected flag to win and be split into arguments, got %v", got)
+	}
+	if got, _ := pagerCommand(""); !reflect.DeepEqual(got, []string{"most"}) {
+		t.Errorf("expected $PAGER fallback, got %v", got)
+	}
+
+	t.Setenv("PAGER", "")
+	if got, _ := pagerCommand(""); !reflect.DeepEqual(got, []string{"more"}) {
+		t.Errorf("expected more when less is not installed, got %v", got)
+	}
+	if _, err := pagerCommand("missing"); err == nil {
+		t.Errorf("expected an error for a --pager that is not installed")
+	}
+}
+
+func TestEditWithoutEditorShowsStatus(t *testing.T) {
+	installed(t)
+	t.Setenv("EDITOR", "")
+	t.Setenv("VISUAL", "")
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.client = nil // an attempted download would panic
+	m.list.SetItems([]list.Item{item{key: "a.txt", displayKey: "a.txt"}})
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	m = updated.(Model)
+
+	if cmd != nil {
+		t.Errorf("expected no editor to be started")
+	}
+	if !m.statusIsError || !strings.Contains(m.statusMsg, "no editor found, set $EDITOR") {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
+
+func TestOpenerCommand(t *testing.T) {
+	tests := map[string][]string{
+		"darwin":  {"open"},
+		"linux":   {"xdg-open"},
+		"freebsd": {"xdg-open"},
+		"windows": {"cmd", "/c", "start", ""},
+	}
+	for goos, want := range tests {
+		if got := openerCommand(goos); !reflect.DeepEqual(got, want) {
+			t.Errorf("openerCommand(%q) = %q, want %q", goos, got, want)
+		}
+	}
+}
+
+func TestOpenWithoutOpenerShowsStatus(t *testing.T) {
+	installed(t)
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.client = nil // an attempted download would panic
+	m.list.SetItems([]list.Item{item{key: "report.pdf", displayKey: "report.pdf"}})
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'O'}})
+	m = updated.(Model)
+
+	if cmd != nil || m.operation != nil {
+		t.Errorf("expected nothing to be downloaded without an opener")
+	}
+	if !m.statusIsError || !strings.Contains(m.statusMsg, "not found on PATH") {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
+
+func TestStreamCommandPipesHeaderAndBody(t *testing.T) {
+	cmd := streamCommand([]string{"less", "-R"}, "s3://b/k\n\n", strings.NewReader("line 1\nline 2\n"))
+	if got := strings.Join(cmd.Args, " "); got != "less -R" {
+		t.Errorf("args = %q, want the pager alone with no file", got)
+	}
+	if cmd.Stdin == nil {
+		t.Fatal("stdin is not wired to the object")
+	}
+	got, err := io.ReadAll(cmd.Stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "s3://b/k\n\nline 1\nline 2\n" {
+		t.Errorf("stdin = %q, want the header followed by the body", got)
+	}
+}
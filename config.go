@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// userConfig is the optional user configuration in ~/.config/s3n/config.json.
+type userConfig struct {
+	// Keys maps an action name (see keyActions) to the keys that trigger it,
+	// e.g. {"back": ["backspace", "left"]}. Actions left out keep their defaults.
+	Keys map[string][]string `json:"keys"`
+}
+
+func configFile() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// loadUserConfig reads the user configuration; a missing file means all defaults.
+func loadUserConfig() (userConfig, error) {
+	var cfg userConfig
+	path, err := configFile()
+	if err != nil {
+		return cfg, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to read config from %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// keyActions names the remappable bindings of the object list.
+func (k *keyMap) keyActions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"enter":        &k.Enter,
+		"view_builtin": &k.ViewBuiltin,
+		"back":         &k.Back,
+		"edit":         &k.Edit,
+		"quit":         &k.Quit,
+		"reload":       &k.Reload,
+		"add":          &k.Add,
+		"delete":       &k.Delete,
+		"search":       &k.Search,
+		"next_page":    &k.NextPage,
+		"dismiss":      &k.Dismiss,
+		"retry":        &k.Retry,
+		"add_bookmark": &k.AddBookmark,
+		"bookmarks":    &k.Bookmarks,
+		"history_back": &k.HistoryBack,
+		"history_fwd":  &k.HistoryFwd,
+		"copy_content": &k.CopyContent,
+		"reveal":       &k.Reveal,
+		"buckets":      &k.Buckets,
+		"toggle_time":  &k.ToggleTime,
+		"content_type": &k.ContentType,
+		"sort":         &k.Sort,
+		"go_to":        &k.GoTo,
+		"restore":      &k.Restore,
+		"info":         &k.Info,
+		"edit_tags":    &k.EditTags,
+		"find":         &k.Find,
+		"new_folder":   &k.NewFolder,
+		"copy":         &k.Copy,
+		"move":         &k.Move,
+		"versions":     &k.Versions,
+		"item_filter":  &k.ItemFilter,
+		"item_kind":    &k.ItemKind,
+		"split_view":   &k.SplitView,
+		"upload":       &k.Upload,
+		"breadcrumbs":  &k.Breadcrumbs,
+		"recents":      &k.Recents,
+		"select":       &k.Select,
+		"download":     &k.Download,
+		"copy_key":     &k.CopyKey,
+		"copy_uri":     &k.CopyURI,
+		"version":      &k.Version,
+		"open_system":  &k.OpenSystem,
+		"console":      &k.Console,
+		"count":        &k.Count,
+		"summary":      &k.Summary,
+	}
+}
+
+// newKeyMap returns the default bindings with overrides from the config file applied on
+// top. Any problem applyKeyOverrides finds is an error, since a bad binding could leave
+// an action unreachable.
+func newKeyMap(overrides map[string][]string) (keyMap, error) {
+	k := defaultKeyMap()
+	if problems := applyKeyOverrides(&k, overrides); len(problems) > 0 {
+		return k, errors.New(strings.Join(problems, "; "))
+	}
+	return k, nil
+}
+
+// applyKeyOverrides remaps the actions in overrides and returns the problems found:
+// unknown actions, actions given no keys or an empty key, and keys now bound to more
+// than one action.
+func applyKeyOverrides(k *keyMap, overrides map[string][]string) []string {
+	var problems []string
+	actions := k.keyActions()
+	for name, keys := range overrides {
+		b, ok := actions[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown action %q", name))
+			continue
+		}
+		if len(keys) == 0 {
+			problems = append(problems, fmt.Sprintf("no keys given for %q", name))
+			continue
+		}
+		if slices.Contains(keys, "") {
+			problems = append(problems, fmt.Sprintf("empty key given for %q", name))
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+
+	owners := map[string][]string{}
+	for name, b := range actions {
+		for _, k := range b.Keys() {
+			owners[k] = append(owners[k], name)
+		}
+	}
+	for k, names := range owners {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		problems = append(problems, fmt.Sprintf("%q is bound to %s", k, strings.Join(names, ", ")))
+	}
+	sort.Strings(problems)
+	return problems
+}
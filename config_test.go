@@ -0,0 +1,117 @@
+// ABOUTME: Tests for the user config file in config.go.
+// ABOUTME: Covers loading the file and remapping key bindings with warnings.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLoadUserConfigMissingFileIsEmpty(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	cfg, err := loadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Keys != nil {
+		t.Errorf("expected no key overrides, got %v", cfg.Keys)
+	}
+}
+
+func TestLoadUserConfigReadsKeys(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	os.MkdirAll(filepath.Join(dir, "s3n"), 0o755)
+	os.WriteFile(filepath.Join(dir, "s3n", "config.json"), []byte(`{"keys": {"back": ["left"]}}`), 0o644)
+
+	cfg, err := loadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Keys, map[string][]string{"back": {"left"}}) {
+		t.Errorf("unexpected keys %v", cfg.Keys)
+	}
+}
+
+func TestApplyKeyOverridesRemapsAndKeepsDefaults(t *testing.T) {
+	keys := defaultKeyMap()
+	warnings := applyKeyOverrides(&keys, map[string][]string{"back": {"left", "backspace"}})
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyLeft}, keys.Back) {
+		t.Errorf("expected left to go back")
+	}
+	if key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}}, keys.Back) {
+		t.Errorf("expected h to no longer go back")
+	}
+	if keys.Back.Help().Key != "left/backspace" || keys.Back.Help().Desc != "go back" {
+		t.Errorf("unexpected help %+v", keys.Back.Help())
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlR}, keys.Reload) {
+		t.Errorf("expected unspecified actions to keep their defaults")
+	}
+}
+
+func TestApplyKeyOverridesWarns(t *testing.T) {
+	keys := defaultKeyMap()
+	warnings := applyKeyOverrides(&keys, map[string][]string{"reload": {"n"}, "teleport": {"t"}})
+	joined := strings.Join(warnings, "; ")
+	if !strings.Contains(joined, `"n" is bound to next_page, reload`) {
+		t.Errorf("expected a duplicate warning, got %q", joined)
+	}
+	if !strings.Contains(joined, `unknown action "teleport"`) {
+		t.Errorf("expected an unknown action warning, got %q", joined)
+	}
+}
+
+func TestNewKeyMapMergesOverDefaults(t *testing.T) {
+	// Giving next_page another key frees n for reload; conflicts are judged after every override.
+	keys, err := newKeyMap(map[string][]string{"reload": {"n"}, "next_page": {"ctrl+n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}}, keys.Reload) || key.Matches(tea.KeyMsg{Type: tea.KeyCtrlR}, keys.Reload) {
+		t.Errorf("expected the override to replace reload's default keys, got %v", keys.Reload.Keys())
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, keys.NextPage) {
+		t.Errorf("expected next_page remapped, got %v", keys.NextPage.Keys())
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlE}, keys.Edit) {
+		t.Errorf("expected actions left out to keep their defaults")
+	}
+}
+
+func TestNewKeyMapRejectsBadBindings(t *testing.T) {
+	for _, tc := range []struct {
+		overrides map[string][]string
+		want      string
+	}{
+		{map[string][]string{"edit": {"ctrl+r"}}, `"ctrl+r" is bound to edit, reload`},
+		{map[string][]string{"back": {}}, `no keys given for "back"`},
+		{map[string][]string{"back": {"left", ""}}, `empty key given for "back"`},
+		{map[string][]string{"teleport": {"x"}}, `unknown action "teleport"`},
+	} {
+		if _, err := newKeyMap(tc.overrides); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("newKeyMap(%v) error = %v, want %q", tc.overrides, err, tc.want)
+		}
+	}
+	if _, err := newKeyMap(nil); err != nil {
+		t.Errorf("expected the defaults to have no conflicts, got %v", err)
+	}
+}
+
+func TestKeyConfigWarningsSurviveFirstLoad(t *testing.T) {
+	m := initialModel("test-bucket", options{keyOverrides: map[string][]string{"reload": {"n"}}})
+	updated, _ := m.Update(itemsLoadedMsg{})
+	m = updated.(Model)
+	if !strings.HasPrefix(m.statusMsg, "Key config:") {
+		t.Errorf("status = %q, want the key config warning", m.statusMsg)
+	}
+}
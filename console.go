@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// consoleOpenedMsg reports a console page handed to the browser.
+type consoleOpenedMsg struct {
+	url string
+}
+
+// consoleHost returns the S3 console for region's partition: China and GovCloud regions
+// have consoles of their own.
+func consoleHost(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "https://console.amazonaws.cn/s3"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "https://console.amazonaws-us-gov.com/s3"
+	default:
+		return "https://s3.console.aws.amazon.com/s3"
+	}
+}
+
+// consoleURL returns the AWS Console page of key in bucket: the object's page, or the
+// bucket's listing of the prefix when key is a directory. An empty region is left for
+// the console to work out.
+func consoleURL(region, bucket, key string, isDir bool) string {
+	page := "object"
+	if isDir {
+		page = "buckets"
+	}
+	var query []string
+	if region != "" {
+		query = append(query, "region="+url.QueryEscape(region))
+	}
+	if key != "" {
+		query = append(query, "prefix="+url.QueryEscape(key))
+	}
+	u := fmt.Sprintf("%s/%s/%s", consoleHost(region), page, url.PathEscape(bucket))
+	if len(query) > 0 {
+		u += "?" + strings.Join(query, "&")
+	}
+	return u
+}
+
+// openInConsole opens the console page of i in the browser with the system opener. The
+// binding is disabled with a custom endpoint, which has no console.
+func (m *Model) openInConsole(i item) tea.Cmd {
+	opener, err := systemOpener()
+	if err != nil {
+		m.setErrorStatus(err.Error())
+		return nil
+	}
+	u := consoleURL(m.region, m.bucketName, i.key, i.isDir)
+	arg := u
+	if opener[0] == "cmd" {
+		// cmd would take the & between query parameters as the end of the command.
+		arg = strings.ReplaceAll(u, "&", "^&")
+	}
+	return func() tea.Msg {
+		if err := exec.Command(opener[0], append(opener[1:], arg)...).Run(); err != nil {
+			return fmt.Errorf("failed to open %s with %s: %w", u, opener[0], err)
+		}
+		return consoleOpenedMsg{url: u}
+	}
+}
@@ -0,0 +1,50 @@
+// ABOUTME: Tests for opening objects and prefixes in the AWS Console from console.go.
+// ABOUTME: Covers console URLs across regions and partitions and hiding the binding for custom endpoints.
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConsoleURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		region string
+		key    string
+		isDir  bool
+		want   string
+	}{
+		{"object", "eu-west-1", "logs/app.log", false, "https://s3.console.aws.amazon.com/s3/object/my-bucket?region=eu-west-1&prefix=logs%2Fapp.log"},
+		{"prefix", "us-east-1", "logs/2024/", true, "https://s3.console.aws.amazon.com/s3/buckets/my-bucket?region=us-east-1&prefix=logs%2F2024%2F"},
+		{"bucket root", "ap-southeast-2", "", true, "https://s3.console.aws.amazon.com/s3/buckets/my-bucket?region=ap-southeast-2"},
+		{"escaped key", "us-west-2", "a b/c&d=e.txt", false, "https://s3.console.aws.amazon.com/s3/object/my-bucket?region=us-west-2&prefix=a+b%2Fc%26d%3De.txt"},
+		{"china", "cn-north-1", "data.csv", false, "https://console.amazonaws.cn/s3/object/my-bucket?region=cn-north-1&prefix=data.csv"},
+		{"govcloud", "us-gov-west-1", "data/", true, "https://console.amazonaws-us-gov.com/s3/buckets/my-bucket?region=us-gov-west-1&prefix=data%2F"},
+		{"no region", "", "data.csv", false, "https://s3.console.aws.amazon.com/s3/object/my-bucket?prefix=data.csv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := consoleURL(tt.region, "my-bucket", tt.key, tt.isDir); got != tt.want {
+				t.Errorf("consoleURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleIsNotOfferedWithCustomEndpoint(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	if !m.keys.Console.Enabled() {
+		t.Errorf("expected the console binding to be offered against AWS")
+	}
+
+	m = initialModel("test-bucket", options{endpoint: "http://localhost:4566"})
+	if m.keys.Console.Enabled() {
+		t.Errorf("expected the console binding to be disabled with a custom endpoint")
+	}
+	if key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}}, m.keys.Console) {
+		t.Errorf("expected W to do nothing with a custom endpoint")
+	}
+}
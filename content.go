@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/mtyurt/s3n/logger"
+)
+
+// sniffLen is how many leading bytes are inspected to guess whether content is text.
+const sniffLen = 512
+
+// looksBinary guesses from a content sample whether it is binary rather than text.
+func looksBinary(sample []byte) bool {
+	if len(sample) > sniffLen {
+		sample = sample[:sniffLen]
+	}
+	if len(sample) == 0 {
+		return false
+	}
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return true
+	}
+	contentType := http.DetectContentType(sample)
+	if strings.HasPrefix(contentType, "text/") {
+		return false
+	}
+	// DetectContentType only recognizes a few text formats; treat other valid UTF-8 as text,
+	// allowing for a multi-byte rune cut off at the end of the sample.
+	for i := 0; i < utf8.UTFMax && len(sample) > 0; i++ {
+		if utf8.Valid(sample) {
+			return false
+		}
+		sample = sample[:len(sample)-1]
+	}
+	return true
+}
+
+// textTypes covers common text formats missing from mime's built-in table, which the
+// system MIME database may not provide either.
+var textTypes = map[string]string{
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".yaml":     "application/yaml",
+	".yml":      "application/yaml",
+	".csv":      "text/csv",
+	".txt":      "text/plain",
+	".log":      "text/plain",
+	".toml":     "application/toml",
+}
+
+// renderContentType is the content type rendering decisions are based on. Generic types
+// that S3 defaults to are replaced with one inferred from the key's extension; the stored
+// type is still what gets displayed.
+func renderContentType(key, stored string) string {
+	switch strings.TrimSpace(strings.SplitN(stored, ";", 2)[0]) {
+	case "", "application/octet-stream", "binary/octet-stream":
+	default:
+		return stored
+	}
+	ext := strings.ToLower(path.Ext(key))
+	if t, ok := textTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return stored
+}
+
+const (
+	// contentTypeWorkers bounds the HeadObject requests in flight while listing.
+	contentTypeWorkers = 8
+	// contentTypeTimeout caps how long a listing waits for content types.
+	contentTypeTimeout = 10 * time.Second
+	// contentTypeCacheSize is how many fetched content types are kept.
+	contentTypeCacheSize = 5000
+)
+
+// headObjectAPI is the part of the S3 client fetchContentTypes needs.
+type headObjectAPI interface {
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+}
+
+// fetchContentTypes fills in the stored Content-Type of the objects in items, in place,
+// with at most contentTypeWorkers requests at a time. Content types found in cache are
+// reused without a request, and fetched ones are added to it. Objects whose request fails
+// or does not finish within contentTypeTimeout keep an empty content type.
+func fetchContentTypes(ctx context.Context, client headObjectAPI, bucket string, items []item, cache *contentTypeCache) {
+	ctx, cancel := context.WithTimeout(ctx, contentTypeTimeout)
+	defer cancel()
+
+	sem := make(chan struct{}, contentTypeWorkers)
+	var wg sync.WaitGroup
+	for i := range items {
+		if items[i].isDir {
+			continue
+		}
+		if contentType, ok := cache.get(items[i].contentTypeKey(bucket)); ok {
+			items[i].contentType = contentType
+			continue
+		}
+		wg.Add(1)
+		go func(i *item) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			output, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(i.key),
+			})
+			if err != nil {
+				logger.Debugf("HeadObject %s: %v", s3URI(bucket, i.key), err)
+				return
+			}
+			i.contentType = aws.StringValue(output.ContentType)
+			cache.put(i.contentTypeKey(bucket), i.contentType)
+		}(&items[i])
+	}
+	wg.Wait()
+}
+
+func (i item) contentTypeKey(bucket string) contentTypeKey {
+	return contentTypeKey{bucket: bucket, key: i.key, etag: i.etag}
+}
+
+type contentTypeKey struct {
+	bucket string
+	key    string
+	etag   string
+}
+
+type cachedContentType struct {
+	key         contentTypeKey
+	contentType string
+}
+
+// contentTypeCache is an LRU of content types fetched with HeadObject, keyed by the
+// object's ETag so a changed object is fetched again. Like listingCache it is shared by
+// every copy of the Model and used from commands. A nil cache stores nothing.
+type contentTypeCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of cachedContentType, most recently used first
+	entries map[contentTypeKey]*list.Element
+}
+
+func newContentTypeCache(size int) *contentTypeCache {
+	return &contentTypeCache{size: size, order: list.New(), entries: make(map[contentTypeKey]*list.Element)}
+}
+
+func (c *contentTypeCache) get(k contentTypeKey) (string, bool) {
+	if c == nil || k.etag == "" {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[k]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(cachedContentType).contentType, true
+}
+
+// put stores a content type. Objects listed without an ETag are not cached, since a
+// change to them could not be noticed.
+func (c *contentTypeCache) put(k contentTypeKey, contentType string) {
+	if c == nil || k.etag == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[k]; ok {
+		e.Value = cachedContentType{key: k, contentType: contentType}
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[k] = c.order.PushFront(cachedContentType{key: k, contentType: contentType})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(cachedContentType).key)
+	}
+}
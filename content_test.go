@@ -0,0 +1,125 @@
+// ABOUTME: Tests for content sniffing helpers in content.go.
+// ABOUTME: Covers telling text from binary, inferring content types, fetching them concurrently and caching them.
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestLooksBinary(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample []byte
+		want   bool
+	}{
+		{"empty", nil, false},
+		{"plain text", []byte("hello world\n"), false},
+		{"json", []byte(`{"a": [1, 2, 3]}`), false},
+		{"utf-8 cut mid-rune", append(bytes.Repeat([]byte("a"), sniffLen-1), "é"...), false},
+		{"nul byte", []byte("abc\x00def"), true},
+		{"png header", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), true},
+		{"gzip", []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksBinary(tt.sample); got != tt.want {
+				t.Errorf("looksBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderContentType(t *testing.T) {
+	tests := []struct {
+		key, stored, want string
+	}{
+		{"data.json", "application/octet-stream", "application/json"},
+		{"README.md", "binary/octet-stream", "text/markdown"},
+		{"config.YAML", "", "application/yaml"},
+		{"photo.png", "application/octet-stream", "image/png"},
+		{"notes.md", "text/plain", "text/plain"},
+		{"blob", "application/octet-stream", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := renderContentType(tt.key, tt.stored); got != tt.want {
+			t.Errorf("renderContentType(%q, %q) = %q, want %q", tt.key, tt.stored, got, tt.want)
+		}
+	}
+}
+
+// fakeHeadObject answers HeadObject after a short delay, recording the most calls in flight.
+type fakeHeadObject struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeHeadObject) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	f.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	f.mu.Lock()
+	f.inFlight--
+	f.mu.Unlock()
+	return &s3.HeadObjectOutput{ContentType: aws.String("type/" + *params.Key)}, nil
+}
+
+func TestFetchContentTypesBoundsConcurrency(t *testing.T) {
+	var items []item
+	for i := 0; i < 40; i++ {
+		items = append(items, item{key: fmt.Sprintf("k%d", i)})
+	}
+	items = append(items, item{key: "dir/", isDir: true})
+	client := &fakeHeadObject{}
+
+	fetchContentTypes(context.Background(), client, "b", items, nil)
+
+	if client.maxInFlight > contentTypeWorkers {
+		t.Errorf("%d HeadObject calls in flight, want at most %d", client.maxInFlight, contentTypeWorkers)
+	}
+	if client.maxInFlight < 2 {
+		t.Errorf("expected HeadObject calls to run in parallel")
+	}
+	for i, it := range items[:40] {
+		if it.key != fmt.Sprintf("k%d", i) || it.contentType != "type/"+it.key {
+			t.Errorf("item %d = %+v, want its own content type in listing order", i, it)
+		}
+	}
+	if items[40].contentType != "" {
+		t.Errorf("expected directories to be skipped")
+	}
+}
+
+func TestContentTypeCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newContentTypeCache(2)
+	a := contentTypeKey{bucket: "b", key: "a", etag: "1"}
+	b := contentTypeKey{bucket: "b", key: "b", etag: "1"}
+	c.put(a, "text/plain")
+	c.put(b, "text/csv")
+	c.get(a)
+	c.put(contentTypeKey{bucket: "b", key: "c", etag: "1"}, "image/png")
+
+	if got, ok := c.get(a); !ok || got != "text/plain" {
+		t.Errorf("get(a) = %q, %v; want the recently used entry kept", got, ok)
+	}
+	if _, ok := c.get(b); ok {
+		t.Errorf("expected the least recently used entry to be evicted")
+	}
+	if _, ok := c.get(contentTypeKey{bucket: "b", key: "a", etag: "2"}); ok {
+		t.Errorf("expected a changed ETag to miss")
+	}
+	c.put(contentTypeKey{bucket: "b", key: "d"}, "text/plain")
+	if _, ok := c.get(contentTypeKey{bucket: "b", key: "d"}); ok {
+		t.Errorf("expected objects without an ETag not to be cached")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// maxCopySize is the largest object CopyObject copies in one request; bigger ones need a
+// multipart copy with UploadPartCopy.
+const maxCopySize = 5 * 1024 * 1024 * 1024
+
+// checkCopySize refuses objects CopyObject would reject, rather than letting the request fail.
+func checkCopySize(i item) error {
+	if i.size > maxCopySize {
+		return fmt.Errorf("%s is %s; CopyObject only copies objects up to 5 GiB and multipart copies are not supported", i.displayKey, humanize.IBytes(uint64(i.size)))
+	}
+	return nil
+}
+
+// promptCopy asks where in the bucket to copy, or with move set to move, the object i.
+func (m *Model) promptCopy(i item, move bool) tea.Cmd {
+	if i.isDir {
+		return m.setStatus("Only objects can be copied or moved")
+	}
+	if err := checkCopySize(i); err != nil {
+		m.setErrorStatus(err.Error())
+		return nil
+	}
+	m.copyItem = i
+	kind, verb := promptCopy, "Copy"
+	if move {
+		kind, verb = promptMove, "Move"
+	}
+	return m.openPrompt(kind, fmt.Sprintf("%s %s to: ", verb, i.displayKey), i.key)
+}
+
+// copyDestination resolves the prompt value for copying key: a value ending in "/" keeps
+// the object's name in that prefix.
+func copyDestination(key, value string) string {
+	value = strings.TrimLeft(strings.TrimSpace(value), "/")
+	if strings.HasSuffix(value, "/") {
+		value += path.Base(key)
+	}
+	return value
+}
+
+// startCopy copies m.copyItem to the key typed into the prompt, deleting the original when move is set.
+func (m *Model) startCopy(value string, move bool) tea.Cmd {
+	src := m.copyItem.key
+	dst := copyDestination(src, value)
+	if dst == "" || dst == src {
+		return m.setStatus("Destination is the object itself, nothing to do")
+	}
+	return copyObject(m.ctx, m.client, m.bucketName, src, m.bucketName, dst, move, m.opts.dryRun)
+}
@@ -0,0 +1,71 @@
+// ABOUTME: Tests for copying and moving objects within a bucket in copy.go.
+// ABOUTME: Covers destinations, the 5 GiB CopyObject limit and the CopySource header sent for odd keys.
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCopyDestination(t *testing.T) {
+	tests := []struct {
+		key, value, want string
+	}{
+		{"logs/a.log", "logs/b.log", "logs/b.log"},
+		{"logs/a.log", "archive/", "archive/a.log"},
+		{"logs/a.log", " /archive/old.log ", "archive/old.log"},
+		{"logs/a.log", "", ""},
+	}
+	for _, tt := range tests {
+		if got := copyDestination(tt.key, tt.value); got != tt.want {
+			t.Errorf("copyDestination(%q, %q) = %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCopyRefusesObjectsOverFiveGiB(t *testing.T) {
+	if err := checkCopySize(item{displayKey: "ok.bin", size: maxCopySize}); err != nil {
+		t.Errorf("a 5 GiB object should be copyable: %v", err)
+	}
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	if cmd := m.promptCopy(item{key: "big.bin", displayKey: "big.bin", size: maxCopySize + 1}, false); cmd != nil {
+		t.Error("expected no prompt for an object over 5 GiB")
+	}
+	if m.prompt != promptNone || !m.statusIsError || !strings.Contains(m.statusMsg, "multipart") {
+		t.Errorf("prompt %v, status %q", m.prompt, m.statusMsg)
+	}
+}
+
+func TestCopySourceHeaderEscapesSpecialCharacters(t *testing.T) {
+	keys := map[string]string{
+		"dir/a b.txt":   "b/dir/a%20b.txt",
+		"dir/a+b.txt":   "b/dir/a%2Bb.txt",
+		"dir/ü 100%.md": "b/dir/%C3%BC%20100%25.md",
+	}
+	for key, want := range keys {
+		var got string
+		var deletes int
+		client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				deletes++
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			got = r.Header.Get("X-Amz-Copy-Source")
+			w.Write([]byte(`<CopyObjectResult><ETag>"e"</ETag></CopyObjectResult>`))
+		})
+		msg := copyObject(context.Background(), client, "b", key, "b", "dst", true, false)()
+		if copied, ok := msg.(objectCopiedMsg); !ok || !copied.moved {
+			t.Fatalf("%s: unexpected message %#v", key, msg)
+		}
+		if got != want {
+			t.Errorf("%s: copy source = %q, want %q", key, got, want)
+		}
+		if deletes != 1 {
+			t.Errorf("%s: move deleted the source %d times", key, deletes)
+		}
+	}
+}
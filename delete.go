@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// deleteBatchSize is the most keys DeleteObjects accepts in one request.
+const deleteBatchSize = 1000
+
+// deletePrefixMsg asks Update to delete every object under prefix, visiting at most
+// limit objects (0 for no limit).
+type deletePrefixMsg struct {
+	prefix string
+	limit  int
+}
+
+// prefixDeletedMsg reports how many objects a recursive delete removed.
+type prefixDeletedMsg struct {
+	bucket string
+	prefix string
+	count  int
+}
+
+// deletePrefix removes every object under prefix with deleteKeys. All keys are listed
+// before anything is deleted, so more than limit objects leaves the prefix untouched:
+// the walk goes on only to count them, and the user is asked to delete them all.
+func deletePrefix(ctx context.Context, client S3API, bucket, prefix string, limit int) tea.Msg {
+	var keys []string
+	count := 0
+	err := walkObjects(ctx, client, bucket, prefix, 0, func(obj types.Object) error {
+		count++
+		if limit == 0 || count <= limit {
+			keys = append(keys, aws.StringValue(obj.Key))
+		}
+		return nil
+	})
+	if err != nil {
+		return errorMsg{err: fmt.Errorf("failed to list %s: %w", s3URI(bucket, prefix), err), retry: func() tea.Msg {
+			return deletePrefixMsg{prefix: prefix, limit: limit}
+		}}
+	}
+	if limit > 0 && count > limit {
+		return overCapMsg{
+			question: fmt.Sprintf("Delete all %s objects under %s? That is more than --max-items (%s).", humanize.Comma(int64(count)), s3URI(bucket, prefix), humanize.Comma(int64(limit))),
+			proceed:  deletePrefixMsg{prefix: prefix, limit: 0},
+		}
+	}
+
+	result := deleteKeys(ctx, client, bucket, keys)
+	if result.failed > 0 {
+		return errorMsg{err: fmt.Errorf("deleted %d objects under %s, %d failed, first %w", result.succeeded, s3URI(bucket, prefix), result.failed, result.err), retry: func() tea.Msg {
+			return deletePrefixMsg{prefix: prefix, limit: limit}
+		}}
+	}
+	return prefixDeletedMsg{bucket: bucket, prefix: prefix, count: result.succeeded}
+}
+
+// startDeletePrefix starts the recursive delete of prefix in the current bucket as an operation.
+func (m *Model) startDeletePrefix(prefix string, limit int) tea.Cmd {
+	if m.opts.dryRun {
+		return m.setStatus(skipped("DeleteObjects", m.bucketName, prefix).String())
+	}
+	bucket := m.bucketName
+	return m.startDelete("Deleting "+s3URI(bucket, prefix), func(ctx context.Context, client S3API) tea.Msg {
+		return deletePrefix(ctx, client, bucket, prefix, limit)
+	})
+}
+
+// countingDeletes counts the objects DeleteObjects removes into deleted, so a cancelled
+// delete can say how many are already gone.
+type countingDeletes struct {
+	S3API
+	deleted *atomic.Int64
+}
+
+func (c countingDeletes) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	output, err := c.S3API.DeleteObjects(ctx, params, optFns...)
+	if err == nil {
+		c.deleted.Add(int64(len(params.Delete.Objects) - len(output.Errors)))
+	}
+	return output, err
+}
+
+// startDelete runs a delete as a protected operation: navigating waits for it rather than
+// cancelling it, and cancelling it with esc says how many objects were deleted.
+func (m *Model) startDelete(label string, run func(ctx context.Context, client S3API) tea.Msg) tea.Cmd {
+	deleted := &atomic.Int64{}
+	client := countingDeletes{S3API: m.client, deleted: deleted}
+	cmd := m.startOperation(label, func(ctx context.Context) tea.Msg { return run(ctx, client) })
+	m.operation.deleted = deleted
+	m.operation.protected = true
+	return cmd
+}
+
+// deleteRefusedError is a delete S3 denied, with why in words: an object lock, MFA
+// delete or missing permissions. friendlyError shows the reason under the raw error.
+type deleteRefusedError struct {
+	reason string
+	err    error
+}
+
+func (e *deleteRefusedError) Error() string { return e.err.Error() }
+func (e *deleteRefusedError) Unwrap() error { return e.err }
+
+// deleteRefusal explains a denied delete from the error and, when they could be read, the
+// object's lock retention and legal hold at now. It returns "" when err is not a denial.
+func deleteRefusal(err error, retention *types.ObjectLockRetention, legalHold *types.ObjectLockLegalHold, now time.Time) string {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "AccessDenied" {
+		return ""
+	}
+	message := strings.ToLower(apiErr.ErrorMessage())
+	switch {
+	case strings.Contains(message, "mfa"):
+		return "Bucket requires MFA delete — delete the object with the AWS CLI and an MFA code instead."
+	case legalHold != nil && legalHold.Status == types.ObjectLockLegalHoldStatusOn:
+		return "A legal hold prevents deletion — remove it (s3:PutObjectLegalHold) first."
+	case retention != nil && retention.RetainUntilDate != nil && retention.RetainUntilDate.After(now):
+		return fmt.Sprintf("Object lock retention (%s) prevents deletion until %s.", retention.Mode, retention.RetainUntilDate.Format("2006-01-02 15:04 MST"))
+	case strings.Contains(message, "object lock"):
+		return "Object lock prevents deletion."
+	}
+	return "Access denied — need s3:DeleteObject on this object."
+}
+
+// explainDeleteError looks up key's object lock when S3 denied deleting it, so the error
+// panel can say why. Lookups that fail, e.g. because the bucket has no object lock, are
+// treated as no lock.
+func explainDeleteError(ctx context.Context, client S3API, bucket, key string, err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "AccessDenied" {
+		return err
+	}
+	var retention *types.ObjectLockRetention
+	if output, lookupErr := client.GetObjectRetention(ctx, &s3.GetObjectRetentionInput{Bucket: aws.String(bucket), Key: aws.String(key)}); lookupErr == nil {
+		retention = output.Retention
+	}
+	var legalHold *types.ObjectLockLegalHold
+	if output, lookupErr := client.GetObjectLegalHold(ctx, &s3.GetObjectLegalHoldInput{Bucket: aws.String(bucket), Key: aws.String(key)}); lookupErr == nil {
+		legalHold = output.LegalHold
+	}
+	return &deleteRefusedError{
+		reason: deleteRefusal(err, retention, legalHold, time.Now()),
+		err:    fmt.Errorf("failed to delete %s: %w", s3URI(bucket, key), err),
+	}
+}
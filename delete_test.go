@@ -0,0 +1,191 @@
+// ABOUTME: Tests for deletes in delete.go.
+// ABOUTME: Covers batching DeleteObjects, partial failures, the --max-items retry and explaining refused deletes.
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deleteServer serves listingServer's n objects and records the keys of each DeleteObjects batch.
+func deleteServer(t *testing.T, n int, batches *[][]string) http.HandlerFunc {
+	list := listingServer(t, n)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["delete"]; !ok {
+			list(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Objects []struct{ Key string } `xml:"Object"`
+		}
+		if err := xml.Unmarshal(body, &req); err != nil {
+			t.Errorf("bad DeleteObjects body: %v", err)
+		}
+		var keys []string
+		for _, o := range req.Objects {
+			keys = append(keys, o.Key)
+		}
+		*batches = append(*batches, keys)
+		w.Write([]byte(`<DeleteResult></DeleteResult>`))
+	}
+}
+
+func TestDeletePrefixBatchesDeletes(t *testing.T) {
+	var batches [][]string
+	client := newTestS3Client(t, deleteServer(t, deleteBatchSize+2, &batches))
+
+	msg := deletePrefix(context.Background(), client, "b", "a/", 0)
+	done, ok := msg.(prefixDeletedMsg)
+	if !ok {
+		t.Fatalf("expected prefixDeletedMsg, got %#v", msg)
+	}
+	if done.count != deleteBatchSize+2 {
+		t.Errorf("count = %d, want %d", done.count, deleteBatchSize+2)
+	}
+	if len(batches) != 2 || len(batches[0]) != deleteBatchSize || len(batches[1]) != 2 {
+		t.Fatalf("expected batches of %d and 2, got %d batches", deleteBatchSize, len(batches))
+	}
+	if batches[1][1] != "a/1001" {
+		t.Errorf("last key = %q", batches[1][1])
+	}
+}
+
+func TestDeletePrefixReportsObjectsNotDeleted(t *testing.T) {
+	list := listingServer(t, 2)
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["delete"]; !ok {
+			list(w, r)
+			return
+		}
+		w.Write([]byte(`<DeleteResult><Error><Key>a/1</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error></DeleteResult>`))
+	})
+
+	msg := deletePrefix(context.Background(), client, "b", "a/", 0)
+	errMsg, ok := msg.(errorMsg)
+	if !ok {
+		t.Fatalf("expected errorMsg, got %#v", msg)
+	}
+	if !strings.Contains(errMsg.Error(), "deleted 1 objects") || !strings.Contains(errMsg.Error(), "a/1: Access Denied") {
+		t.Errorf("unexpected error %q", errMsg.Error())
+	}
+}
+
+func TestDeletePrefixOverCapDeletesNothing(t *testing.T) {
+	var batches [][]string
+	client := newTestS3Client(t, deleteServer(t, 3, &batches))
+
+	msg := deletePrefix(context.Background(), client, "b", "a/", 2)
+	overCap, ok := msg.(overCapMsg)
+	if !ok {
+		t.Fatalf("expected overCapMsg, got %#v", msg)
+	}
+	if want := "Delete all 3 objects under s3://b/a/?"; !strings.HasPrefix(overCap.question, want) {
+		t.Errorf("question = %q, want it to start %q", overCap.question, want)
+	}
+	if len(batches) != 0 {
+		t.Errorf("expected nothing deleted, got %v", batches)
+	}
+	if overCap.proceed != (deletePrefixMsg{prefix: "a/", limit: 0}) {
+		t.Errorf("proceed = %#v, want the delete without a limit", overCap.proceed)
+	}
+}
+
+func TestDeletePrefixDryRunSkipsCall(t *testing.T) {
+	// nil client: an actual delete would panic.
+	m := initialModel("test-bucket", options{dryRun: true})
+	m.client = nil
+	m.loading = false
+	m.confirmDelete = true
+	m.deleteKey = "sub/"
+	m.deleteDir = true
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+
+	if m.operation != nil {
+		t.Errorf("expected no delete to start")
+	}
+	if m.statusMsg != "[dry-run] would DeleteObjects s3://test-bucket/sub/" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
+
+func TestDeleteRefusal(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	denied := &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"}
+	until := time.Date(2030, 1, 2, 3, 4, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		err       error
+		retention *types.ObjectLockRetention
+		legalHold *types.ObjectLockLegalHold
+		want      string
+	}{
+		{"mfa delete", &smithy.GenericAPIError{Code: "AccessDenied", Message: "Mfa Authentication must be used for this request"}, nil, nil, "Bucket requires MFA delete"},
+		{"retention", denied, &types.ObjectLockRetention{Mode: types.ObjectLockRetentionModeCompliance, RetainUntilDate: &until}, nil, "Object lock retention (COMPLIANCE) prevents deletion until 2030-01-02 03:04 UTC."},
+		{"expired retention", denied, &types.ObjectLockRetention{Mode: types.ObjectLockRetentionModeGovernance, RetainUntilDate: aws.Time(now.Add(-time.Hour))}, nil, "Access denied — need s3:DeleteObject"},
+		{"legal hold", denied, nil, &types.ObjectLockLegalHold{Status: types.ObjectLockLegalHoldStatusOn}, "A legal hold prevents deletion"},
+		{"legal hold off", denied, nil, &types.ObjectLockLegalHold{Status: types.ObjectLockLegalHoldStatusOff}, "Access denied — need s3:DeleteObject"},
+		{"lock in message", &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied because object protected by object lock."}, nil, nil, "Object lock prevents deletion."},
+		{"plain denial", denied, nil, nil, "Access denied — need s3:DeleteObject"},
+		{"not a denial", &smithy.GenericAPIError{Code: "SlowDown"}, nil, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deleteRefusal(tt.err, tt.retention, tt.legalHold, now)
+			if tt.want == "" && got != "" || !strings.HasPrefix(got, tt.want) {
+				t.Errorf("deleteRefusal() = %q, want it to start with %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// lockedObjectClient reports a legal hold on every object, counting the lookups.
+type lockedObjectClient struct {
+	S3API
+	lookups int
+}
+
+func (c *lockedObjectClient) GetObjectRetention(ctx context.Context, params *s3.GetObjectRetentionInput, optFns ...func(*s3.Options)) (*s3.GetObjectRetentionOutput, error) {
+	c.lookups++
+	return nil, &smithy.GenericAPIError{Code: "NoSuchObjectLockConfiguration"}
+}
+
+func (c *lockedObjectClient) GetObjectLegalHold(ctx context.Context, params *s3.GetObjectLegalHoldInput, optFns ...func(*s3.Options)) (*s3.GetObjectLegalHoldOutput, error) {
+	c.lookups++
+	return &s3.GetObjectLegalHoldOutput{LegalHold: &types.ObjectLockLegalHold{Status: types.ObjectLockLegalHoldStatusOn}}, nil
+}
+
+func TestExplainDeleteErrorLooksUpLock(t *testing.T) {
+	client := &lockedObjectClient{}
+	err := explainDeleteError(context.Background(), client, "b", "held.txt", &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"})
+	if client.lookups != 2 {
+		t.Errorf("%d lock lookups, want retention and legal hold", client.lookups)
+	}
+	if hint := friendlyError(err); !strings.HasPrefix(hint, "A legal hold prevents deletion") {
+		t.Errorf("hint = %q, want the legal hold explained", hint)
+	}
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || !strings.Contains(err.Error(), "s3://b/held.txt") {
+		t.Errorf("expected the S3 error to stay available under %q", err)
+	}
+
+	client.lookups = 0
+	other := errors.New("connection reset")
+	if got := explainDeleteError(context.Background(), client, "b", "held.txt", other); got != other || client.lookups != 0 {
+		t.Errorf("expected other failures to be returned as they are without lookups, got %v", got)
+	}
+}
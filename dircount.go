@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dirCount is how many immediate children, objects and subdirectories, a directory has.
+// Only one page of maxPageSize keys is asked for, so more is set when there are more.
+type dirCount struct {
+	n    int
+	more bool
+}
+
+// String describes the count, e.g. "17 items" or "1000+ items".
+func (c dirCount) String() string {
+	if c.more {
+		return fmt.Sprintf("%d+ items", c.n)
+	}
+	return plural(c.n, "item", "items")
+}
+
+// dirCountedMsg reports the peeked count of the directory prefix in bucket.
+type dirCountedMsg struct {
+	bucket string
+	prefix string
+	count  dirCount
+}
+
+// countChildren counts the subdirectories and objects of a listing of prefix. The empty
+// object some tools create to mark the directory itself is not a child.
+func countChildren(output *s3.ListObjectsV2Output, prefix string) dirCount {
+	n := len(output.CommonPrefixes)
+	for _, obj := range output.Contents {
+		if aws.StringValue(obj.Key) != prefix {
+			n++
+		}
+	}
+	return dirCount{n: n, more: aws.BoolValue(output.IsTruncated)}
+}
+
+func countDir(ctx context.Context, client S3API, bucket, prefix string) tea.Msg {
+	output, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+		MaxKeys:   aws.Int32(maxPageSize),
+	})
+	if err != nil {
+		return errorMsg{err: fmt.Errorf("failed to count %s: %w", s3URI(bucket, prefix), err), retry: func() tea.Msg {
+			return countRequestMsg{bucket: bucket, prefix: prefix}
+		}}
+	}
+	return dirCountedMsg{bucket: bucket, prefix: prefix, count: countChildren(output, prefix)}
+}
+
+// countRequestMsg asks for the count of the directory prefix again, from the error panel.
+type countRequestMsg struct {
+	bucket string
+	prefix string
+}
+
+// peekDir counts the children of the directory i, once per session; a count already
+// peeked is shown again without a request.
+func (m *Model) peekDir(i item) tea.Cmd {
+	if !i.isDir {
+		return m.setStatus("Only directories can be counted")
+	}
+	return m.startCount(m.bucketName, i.key)
+}
+
+// startCount counts the children of the directory prefix in bucket as an operation.
+func (m *Model) startCount(bucket, prefix string) tea.Cmd {
+	if count, ok := m.cache.getCount(bucket, prefix); ok {
+		return func() tea.Msg { return dirCountedMsg{bucket: bucket, prefix: prefix, count: count} }
+	}
+	client := m.client
+	return m.startOperation("Counting "+s3URI(bucket, prefix), func(ctx context.Context) tea.Msg {
+		return countDir(ctx, client, bucket, prefix)
+	})
+}
+
+// withDirCounts sets the peeked count of each directory, in place.
+func (m *Model) withDirCounts(items []list.Item) []list.Item {
+	for idx, it := range items {
+		if i, ok := it.(item); ok && i.isDir {
+			i.count = nil
+			if count, ok := m.cache.getCount(m.bucketName, i.key); ok {
+				i.count = &count
+			}
+			items[idx] = i
+		}
+	}
+	return items
+}
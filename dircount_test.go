@@ -0,0 +1,109 @@
+// ABOUTME: Tests for peeking at the number of children of a directory in dircount.go.
+// ABOUTME: Covers counting a listing page, showing the count on the item and reusing it from the cache.
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCountChildren(t *testing.T) {
+	output := &s3.ListObjectsV2Output{
+		CommonPrefixes: []types.CommonPrefix{{Prefix: aws.String("logs/2023/")}, {Prefix: aws.String("logs/2024/")}},
+		Contents: []types.Object{
+			{Key: aws.String("logs/")}, // the directory marker
+			{Key: aws.String("logs/a.log")},
+			{Key: aws.String("logs/b.log")},
+		},
+		IsTruncated: aws.Bool(false),
+	}
+	if got := countChildren(output, "logs/"); got != (dirCount{n: 4}) || got.String() != "4 items" {
+		t.Errorf("countChildren() = %+v (%s), want 4 items", got, got)
+	}
+
+	output.IsTruncated = aws.Bool(true)
+	if got := countChildren(output, "logs/").String(); got != "4+ items" {
+		t.Errorf("a truncated listing counts as %q, want 4+ items", got)
+	}
+	if got := (dirCount{n: 1}).String(); got != "1 item" {
+		t.Errorf("dirCount{1} = %q", got)
+	}
+}
+
+func TestPeekDirShowsCountAndReusesIt(t *testing.T) {
+	client := newFakeS3(map[string]string{"logs/a.log": "a", "logs/b.log": "b", "logs/2024/c.log": "c", "top.txt": "t"})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	m.loading = false
+	m.currentItems = []list.Item{item{key: "logs/", displayKey: "logs/", isDir: true}, item{key: "top.txt", displayKey: "top.txt"}}
+	m.list.SetItems(m.visibleItems())
+
+	peek := func() {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'#'}})
+		m = updated.(Model)
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			msg = batch[0]()
+		}
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+	}
+
+	peek()
+	if d := m.list.Items()[0].(item).Description(); d != "Directory (3 items)" {
+		t.Errorf("description = %q, want the peeked count", d)
+	}
+	peek()
+	if got := client.called("ListObjectsV2"); got != 1 {
+		t.Errorf("%d ListObjectsV2 calls, want the second peek served from the cache", got)
+	}
+
+	m.cache.invalidateKey("test-bucket", "logs/new.log")
+	if _, ok := m.cache.getCount("test-bucket", "logs/"); ok {
+		t.Errorf("expected a change inside the directory to drop its count")
+	}
+}
+
+func TestDirCountShowsThroughAFuzzyFilter(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "logs/", displayKey: "logs", isDir: true},
+		item{key: "top.txt", displayKey: "top.txt"},
+	}})
+	m = updated.(Model)
+	m.list, _ = m.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("logs")})
+	m.list, _ = m.list.Update(filterMatches(t, cmd))
+	m.list, _ = m.list.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	updated, cmd = m.Update(dirCountedMsg{bucket: "test-bucket", prefix: "logs/", count: dirCount{n: 3}})
+	m = updated.(Model)
+	m.list, _ = m.list.Update(filterMatches(t, cmd))
+	if d := m.list.VisibleItems()[0].(item).Description(); d != "Directory (3 items)" {
+		t.Errorf("description = %q, want the count on the filtered item", d)
+	}
+}
+
+func TestPeekDirRetryRunsAsAnOperation(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.client = newTestS3Client(t, failingOnce(`<ListBucketResult><Name>test-bucket</Name><Contents><Key>logs/a.log</Key><Size>1</Size></Contents></ListBucketResult>`))
+	m.loading = false
+	m.currentItems = []list.Item{item{key: "logs/", displayKey: "logs/", isDir: true}}
+	m.list.SetItems(m.visibleItems())
+
+	m, cmd := retryFailedOperation(t, m, m.peekDir(m.currentItems[0].(item)))
+	if m.operation.label != "Counting s3://test-bucket/logs/" {
+		t.Errorf("operation %q, want the count to be cancellable again", m.operation.label)
+	}
+	updated, _ := m.Update(operationResult(t, cmd))
+	if d := updated.(Model).list.Items()[0].(item).Description(); d != "Directory (1 item)" {
+		t.Errorf("description = %q, want the retried count", d)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mtyurt/s3n/logger"
+)
+
+// dryRunMsg reports a mutating S3 call that --dry-run skipped.
+type dryRunMsg struct {
+	op     string
+	bucket string
+	key    string
+}
+
+func (d dryRunMsg) String() string {
+	return fmt.Sprintf("[dry-run] would %s %s", d.op, s3URI(d.bucket, d.key))
+}
+
+// skipped logs the skipped call and returns it for the status bar.
+func skipped(op, bucket, key string) dryRunMsg {
+	d := dryRunMsg{op: op, bucket: bucket, key: key}
+	logger.Infof("%s", d)
+	return d
+}
@@ -0,0 +1,98 @@
+// ABOUTME: Tests for --dry-run in dryrun.go and the mutating actions that honor it.
+// ABOUTME: Every action runs against a server that fails the test on any write.
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// readOnlyModel returns a dry-run model whose client fails the test on any request
+// other than a read.
+func readOnlyModel(t *testing.T) Model {
+	t.Helper()
+	m := initialModel("test-bucket", options{dryRun: true})
+	m.client = newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			t.Errorf("dry run sent %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`<ListBucketResult><Name>test-bucket</Name></ListBucketResult>`))
+	})
+	m.loading = false
+	return m
+}
+
+func wantDryRun(t *testing.T, action, status string) {
+	t.Helper()
+	if !strings.HasPrefix(status, "[dry-run] would ") {
+		t.Errorf("%s: status %q, want a [dry-run] report", action, status)
+	}
+}
+
+func TestDryRunSendsNoWrites(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "local.txt")
+	if err := os.WriteFile(local, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := map[string]func(m *Model) string{
+		"delete object": func(m *Model) string {
+			updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{item{key: "a.txt", displayKey: "a.txt"}}})
+			*m = updated.(Model)
+			updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+			updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+			*m = updated.(Model)
+			return m.statusMsg
+		},
+		"delete prefix": func(m *Model) string {
+			m.startDeletePrefix("logs/", 0)
+			return m.statusMsg
+		},
+		"delete selected": func(m *Model) string {
+			m.selected = map[string]bool{"a.txt": true, "b.txt": true}
+			m.startDeleteSelected()
+			return m.statusMsg
+		},
+		"upload": func(m *Model) string {
+			m.startUpload(local)
+			return m.statusMsg
+		},
+		"new folder": func(m *Model) string {
+			m.startNewFolder("reports")
+			return m.statusMsg
+		},
+		"move": func(m *Model) string {
+			m.copyItem = item{key: "a.txt", size: 1}
+			return m.startCopy("b.txt", true)().(dryRunMsg).String()
+		},
+		"restore": func(m *Model) string {
+			m.restoreKey = "cold.bin"
+			m.restoreStorageClass = string(types.ObjectStorageClassGlacier)
+			m.startRestore("3")
+			return m.statusMsg
+		},
+		"edit": func(m *Model) string {
+			return uploadEdit(m.ctx, m.client, m.bucketName, EditFinishedMsg{key: "a.txt", filename: local}, m.opts.dryRun)().(dryRunMsg).String()
+		},
+		"tags": func(m *Model) string {
+			m.openTagForm("a.txt", []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}})
+			updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			*m = updated.(Model)
+			return m.statusMsg
+		},
+	}
+	for name, action := range actions {
+		m := readOnlyModel(t)
+		wantDryRun(t, name, action(&m))
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editConflictMsg reports that the object changed in S3 while it was being edited.
+type editConflictMsg struct {
+	edit    EditFinishedMsg
+	deleted bool
+}
+
+// editUploadedMsg reports that an edited temp file is now stored at key.
+type editUploadedMsg struct {
+	key         string
+	filename    string
+	contentType string
+}
+
+// uploadEdit uploads the editor's working copy to key. When the upload fails the
+// file is kept, even after s3n exits, and its path is reported so no edits are lost;
+// retrying from the error panel uploads the same file again. With dryRun nothing is uploaded.
+func uploadEdit(ctx context.Context, client S3API, bucket string, msg EditFinishedMsg, dryRun bool) tea.Cmd {
+	return func() tea.Msg {
+		if dryRun {
+			return skipped("PutObject", bucket, msg.key)
+		}
+		// S3 only stores the edit if the object still has the ETag it was downloaded with.
+		ifMatch := msg.etag
+		if msg.overwrite {
+			ifMatch = ""
+		}
+		if err := putFile(ctx, client, bucket, msg.key, msg.contentType, msg.metadata, ifMatch, msg.filename); err != nil {
+			if ifMatch != "" && (isPreconditionFailed(err) || isNotFound(err)) {
+				return editConflictMsg{edit: msg, deleted: isNotFound(err)}
+			}
+			keepTmpFile(msg.filename)
+			return errorMsg{
+				err:   fmt.Errorf("upload of %s failed, your edits are kept in %s: %w", msg.key, msg.filename, err),
+				retry: uploadEdit(ctx, client, bucket, msg, dryRun),
+			}
+		}
+		return editUploadedMsg{key: msg.key, filename: msg.filename, contentType: msg.contentType}
+	}
+}
+
+// putFile uploads path to key with the given content type and user metadata, so an
+// edit keeps what the object was stored with; an empty contentType lets S3 pick the default.
+// A non-empty ifMatch makes the upload conditional on the object still having that ETag.
+func putFile(ctx context.Context, client S3API, bucket, key, contentType string, metadata map[string]string, ifMatch, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	input := &s3.PutObjectInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Body:     f,
+		Metadata: metadata,
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if ifMatch != "" {
+		input.IfMatch = aws.String(ifMatch)
+	}
+	_, err = client.PutObject(ctx, input)
+	return err
+}
+
+// isPreconditionFailed reports whether a conditional request was refused because the
+// object no longer matches.
+func isPreconditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "PreconditionFailed"
+}
+
+// editChanged reports whether the working copy differs from the object as downloaded.
+// New objects have no original and always count as changed, as does a file that cannot be read.
+func editChanged(msg EditFinishedMsg) bool {
+	if msg.originalHash == nil {
+		return true
+	}
+	edited, err := fileHash(msg.filename)
+	return err != nil || !bytes.Equal(edited, msg.originalHash)
+}
+
+// confirmUploadView asks whether to upload a changed edit.
+func (m Model) confirmUploadView() string {
+	if m.confirmUpload.originalHash == nil {
+		return fmt.Sprintf("Create %s? (y/N)", s3URI(m.bucketName, m.confirmUpload.key))
+	}
+	return fmt.Sprintf("Upload your changes to %s? (y/N)", s3URI(m.bucketName, m.confirmUpload.key))
+}
+
+// resolveConfirmUpload uploads the edit on y; anything else keeps the working copy
+// so the edits are not lost.
+func (m *Model) resolveConfirmUpload(msg tea.KeyMsg) tea.Cmd {
+	edit := *m.confirmUpload
+	m.confirmUpload = nil
+	if msg.String() == "y" || msg.String() == "Y" {
+		return uploadEdit(m.ctx, m.client, m.bucketName, edit, m.opts.dryRun)
+	}
+	keepTmpFile(edit.filename)
+	m.setErrorStatus(fmt.Sprintf("Not uploaded, your edits are kept in %s", edit.filename))
+	return nil
+}
+
+// confirmOverwriteView asks whether to upload an edit over a concurrent change.
+func (m Model) confirmOverwriteView() string {
+	what := "changed in S3 since you opened it"
+	if m.editConflict.deleted {
+		what = "deleted from S3 since you opened it"
+	}
+	return fmt.Sprintf("%s was %s. Upload your edits anyway? (y/N)", s3URI(m.bucketName, m.editConflict.edit.key), what)
+}
+
+// resolveEditConflict uploads the edit over the concurrent change on y, and otherwise
+// keeps the working copy for the user to reconcile.
+func (m *Model) resolveEditConflict(msg tea.KeyMsg) tea.Cmd {
+	edit, what := m.editConflict.edit, "changed"
+	if m.editConflict.deleted {
+		what = "was deleted"
+	}
+	m.editConflict = nil
+	if msg.String() == "y" || msg.String() == "Y" {
+		edit.overwrite = true
+		return uploadEdit(m.ctx, m.client, m.bucketName, edit, m.opts.dryRun)
+	}
+	keepTmpFile(edit.filename)
+	m.setErrorStatus(fmt.Sprintf("Not uploaded, the object %s on the server; your edits are kept in %s. Press %s to reload", what, edit.filename, m.keys.Reload.Help().Key))
+	return nil
+}
@@ -0,0 +1,208 @@
+// ABOUTME: Tests for uploading edited objects in edit.go.
+// ABOUTME: Covers keeping the working copy on failure, retrying and detecting concurrent changes.
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestS3Client returns a client that sends every request to handler.
+func newTestS3Client(t *testing.T, handler http.HandlerFunc) *s3.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return s3.New(s3.Options{
+		Region:           "us-east-1",
+		BaseEndpoint:     aws.String(server.URL),
+		UsePathStyle:     true,
+		Credentials:      aws.AnonymousCredentials{},
+		RetryMaxAttempts: 1,
+	})
+}
+
+// failingS3Client returns a client whose every request is answered with 500 Internal Error.
+func failingS3Client(t *testing.T) *s3.Client {
+	return newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<Error><Code>InternalError</Code><Message>try again</Message></Error>`))
+	})
+}
+
+func TestFailedEditUploadKeepsWorkingCopy(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("my edits"), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := uploadEdit(context.Background(), failingS3Client(t), "test-bucket", EditFinishedMsg{key: "foo", filename: path, contentType: "text/plain"}, false)()
+	var opErr errorMsg
+	if err, ok := msg.(error); !ok || !errors.As(err, &opErr) {
+		t.Fatalf("expected an errorMsg, got %#v", msg)
+	}
+	if !strings.Contains(opErr.Error(), path) {
+		t.Errorf("error %q does not mention the working copy %q", opErr.Error(), path)
+	}
+
+	cleanupTmpFiles()
+	if data, err := os.ReadFile(path); err != nil || string(data) != "my edits" {
+		t.Errorf("working copy lost after a failed upload: %q, %v", data, err)
+	}
+}
+
+func TestFailedEditUploadCanBeRetried(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("my edits"), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, _ := m.Update(uploadEdit(context.Background(), failingS3Client(t), "test-bucket", EditFinishedMsg{key: "foo", filename: path, contentType: "text/plain"}, false)())
+	m = updated.(Model)
+	if m.errRetry == nil {
+		t.Fatal("expected the failed upload to offer a retry")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+	if m.errMsg != "" || cmd == nil {
+		t.Fatalf("expected r to dismiss the panel and retry the upload")
+	}
+	if _, ok := cmd().(errorMsg); !ok {
+		t.Errorf("expected the retried upload to run again and fail against the same server")
+	}
+}
+
+func TestEditUploadedRemovesWorkingCopyAndReloads(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("my edits"), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, cmd := m.Update(editUploadedMsg{key: "foo", filename: path, contentType: "text/plain"})
+	m = updated.(Model)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the working copy to be removed after a confirmed upload")
+	}
+	if !m.loading || cmd == nil {
+		t.Errorf("expected a reload after the upload")
+	}
+}
+
+func TestDryRunEditUploadSkipsPut(t *testing.T) {
+	// nil client: an upload attempt would panic.
+	msg := uploadEdit(context.Background(), nil, "test-bucket", EditFinishedMsg{key: "foo", filename: "unused", contentType: "text/plain"}, true)()
+	d, ok := msg.(dryRunMsg)
+	if !ok || d.String() != "[dry-run] would PutObject s3://test-bucket/foo" {
+		t.Errorf("expected a dry-run report, got %#v", msg)
+	}
+}
+
+func TestEditUploadDetectsConcurrentChange(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("my edits"), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ifMatch []string
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+		if m := r.Header.Get("If-Match"); m != "" && m != `"theirs"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			w.Write([]byte(`<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>`))
+			return
+		}
+		w.Header().Set("ETag", `"mine"`)
+	})
+	edit := EditFinishedMsg{key: "foo", filename: path, contentType: "text/plain", etag: `"original"`}
+
+	msg := uploadEdit(context.Background(), client, "test-bucket", edit, false)()
+	if _, ok := msg.(editConflictMsg); !ok {
+		t.Fatalf("expected a conflict, got %#v", msg)
+	}
+	if len(ifMatch) != 1 || ifMatch[0] != `"original"` {
+		t.Fatalf("expected one PutObject with If-Match \"original\", got %q", ifMatch)
+	}
+
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.client = client
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if !strings.Contains(m.footer(), "changed in S3 since you opened it") {
+		t.Errorf("expected an overwrite prompt, got %q", m.footer())
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if _, ok := cmd().(editUploadedMsg); !ok || len(ifMatch) != 2 || ifMatch[1] != "" {
+		t.Errorf("expected y to upload unconditionally, If-Match headers %q", ifMatch)
+	}
+}
+
+func TestEditUploadOfDeletedObjectConflicts(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("my edits"), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+	})
+	edit := EditFinishedMsg{key: "foo", filename: path, etag: `"original"`}
+	if msg, ok := uploadEdit(context.Background(), client, "test-bucket", edit, false)().(editConflictMsg); !ok || !msg.deleted {
+		t.Errorf("expected a deleted conflict, got %#v", msg)
+	}
+}
+
+func TestEditConflictDeclinedKeepsWorkingCopy(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, _ := m.Update(editConflictMsg{edit: EditFinishedMsg{key: "foo", filename: "/tmp/s3n-1-foo"}})
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if cmd != nil || m.editConflict != nil {
+		t.Fatalf("expected n to cancel the upload")
+	}
+	if want := "Not uploaded, the object changed on the server; your edits are kept in /tmp/s3n-1-foo. Press ctrl+r to reload"; m.statusMsg != want {
+		t.Errorf("status = %q, want %q", m.statusMsg, want)
+	}
+}
+
+func TestEditUploadKeepsContentTypeAndMetadata(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("a,b\n1,2\n"), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got http.Header
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			got = r.Header.Clone()
+		}
+	})
+	edit := EditFinishedMsg{key: "data.csv", filename: path, contentType: "text/csv", metadata: map[string]string{"owner": "ops"}}
+
+	if msg := uploadEdit(context.Background(), client, "test-bucket", edit, false)(); msg == nil {
+		t.Fatal("expected a result")
+	}
+	if got == nil {
+		t.Fatal("expected a PutObject request")
+	}
+	if ct := got.Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("Content-Type = %q, want the original text/csv", ct)
+	}
+	if owner := got.Get("X-Amz-Meta-Owner"); owner != "ops" {
+		t.Errorf("x-amz-meta-owner = %q, want the original metadata", owner)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// friendlyError turns the common S3 failures into actionable guidance.
+// It returns "" when there is nothing more useful to say than the raw error.
+func friendlyError(err error) string {
+	var refused *deleteRefusedError
+	if errors.As(err, &refused) && refused.reason != "" {
+		return refused.reason
+	}
+	var noSuchBucket *types.NoSuchBucket
+	if errors.As(err, &noSuchBucket) {
+		return "Bucket not found — check the name and region."
+	}
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return "Object not found — it may have been deleted or renamed."
+	}
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return "Not found — check the bucket name, key and region."
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchBucket":
+			return "Bucket not found — check the name and region."
+		case "NoSuchKey":
+			return "Object not found — it may have been deleted or renamed."
+		case "AccessDenied", "AllAccessDisabled":
+			return "Access denied — check that your credentials have permission for this bucket and action."
+		case "InvalidAccessKeyId", "SignatureDoesNotMatch":
+			return "Credentials were rejected — check your access key and secret, or the selected profile."
+		case "ExpiredToken", "ExpiredTokenException", "TokenRefreshRequired":
+			return "Credentials have expired — refresh your session (e.g. aws sso login) and retry."
+		case "PermanentRedirect", "AuthorizationHeaderMalformed", "IllegalLocationConstraintException":
+			return "The bucket lives in a different region — set AWS_REGION to the bucket's region."
+		}
+	}
+
+	// The SDK wraps credential resolution failures as "get identity: get credentials: ...".
+	if strings.Contains(err.Error(), "get credentials:") {
+		return "No AWS credentials found — configure ~/.aws/credentials, set AWS_PROFILE, or export AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY."
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return "Could not reach S3 — check your network connection and endpoint."
+	}
+	return ""
+}
+
+// describeError lays out an S3 API error as labelled lines (code, message, operation,
+// status and request ids) instead of the SDK's single run-on sentence; whatever the
+// error was wrapped with stays on the first line. Other errors are returned as-is.
+func describeError(err error) string {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+
+	var lines []string
+	// The wrapping context, e.g. "failed to list s3://b/p", precedes the SDK's text.
+	var opErr *smithy.OperationError
+	if errors.As(err, &opErr) {
+		if wrapped := strings.TrimSuffix(strings.TrimSuffix(err.Error(), opErr.Error()), ": "); wrapped != "" {
+			lines = append(lines, wrapped)
+		}
+	}
+	field := func(label, value string) {
+		if value != "" {
+			lines = append(lines, fmt.Sprintf("%-11s %s", label+":", value))
+		}
+	}
+	field("Code", apiErr.ErrorCode())
+	field("Message", apiErr.ErrorMessage())
+	if opErr != nil {
+		field("Operation", opErr.Service()+" "+opErr.Operation())
+	}
+	var status interface{ HTTPStatusCode() int }
+	if errors.As(err, &status) {
+		field("Status", fmt.Sprint(status.HTTPStatusCode()))
+	}
+	var requestID interface{ ServiceRequestID() string }
+	if errors.As(err, &requestID) {
+		field("Request ID", requestID.ServiceRequestID())
+	}
+	var hostID interface{ ServiceHostID() string }
+	if errors.As(err, &hostID) {
+		field("Host ID", hostID.ServiceHostID())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Tests for translating S3 errors into user-facing guidance.
+// ABOUTME: Covers typed SDK errors, smithy API error codes, credential failures and the error panel layout.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestFriendlyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"typed no such bucket", fmt.Errorf("list: %w", &types.NoSuchBucket{}), "Bucket not found"},
+		{"typed no such key", &types.NoSuchKey{}, "Object not found"},
+		{"access denied code", &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"}, "Access denied"},
+		{"no such bucket code", &smithy.GenericAPIError{Code: "NoSuchBucket"}, "Bucket not found"},
+		{"expired token", &smithy.GenericAPIError{Code: "ExpiredToken"}, "expired"},
+		{"wrong region", &smithy.GenericAPIError{Code: "PermanentRedirect"}, "region"},
+		{"missing credentials", errors.New("operation error S3: ListObjectsV2, get identity: get credentials: failed to refresh cached credentials"), "No AWS credentials"},
+		{"unknown", errors.New("something odd"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := friendlyError(tt.err)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("expected no guidance, got %q", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("friendlyError() = %q, want it to contain %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeAPIError(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amz-Request-Id", "REQ123")
+		w.Header().Set("X-Amz-Id-2", "HOST456")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>User: arn:aws:iam::123456789012:user/dev is not authorized to perform: s3:ListBucket</Message></Error>`))
+	})
+	_, err := client.ListObjectsV2(context.Background(), &s3.ListObjectsV2Input{Bucket: aws.String("b")})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	got := describeError(fmt.Errorf("failed to list s3://b/: %w", err))
+	want := strings.Join([]string{
+		"failed to list s3://b/",
+		"Code:       AccessDenied",
+		"Message:    User: arn:aws:iam::123456789012:user/dev is not authorized to perform: s3:ListBucket",
+		"Operation:  S3 ListObjectsV2",
+		"Status:     403",
+		"Request ID: REQ123",
+		"Host ID:    HOST456",
+	}, "\n")
+	if got != want {
+		t.Errorf("describeError =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDescribePlainError(t *testing.T) {
+	if got := describeError(errors.New("disk full")); got != "disk full" {
+		t.Errorf("describeError = %q", got)
+	}
+	apiErr := &smithy.GenericAPIError{Code: "SlowDown", Message: "Please reduce your request rate."}
+	if got := describeError(apiErr); got != "Code:       SlowDown\nMessage:    Please reduce your request rate." {
+		t.Errorf("describeError = %q", got)
+	}
+}
@@ -0,0 +1,250 @@
+// ABOUTME: An in-memory S3API for tests, and tests of the listing code that use it.
+// ABOUTME: fakeS3 serves one bucket with pagination and delimiters, and counts the calls made.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type fakeObject struct {
+	body         []byte
+	contentType  string
+	modified     time.Time
+	storageClass types.ObjectStorageClass // STANDARD when empty
+}
+
+func (o fakeObject) etag() string { return fmt.Sprintf(`"%x"`, md5.Sum(o.body)) }
+
+// fakeS3 keeps the objects of a single bucket in memory. Calls to S3API methods it does
+// not implement panic through the embedded nil interface, so a test notices them.
+type fakeS3 struct {
+	S3API
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	calls   map[string]int
+	// pageSize stands in for S3's default of 1000 keys per page when a listing sets no MaxKeys.
+	pageSize int
+}
+
+// newFakeS3 returns a fake holding objects, given as key to content.
+func newFakeS3(objects map[string]string) *fakeS3 {
+	f := &fakeS3{objects: map[string]fakeObject{}, calls: map[string]int{}}
+	for k, body := range objects {
+		f.objects[k] = fakeObject{body: []byte(body), contentType: "text/plain", modified: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	}
+	return f
+}
+
+func (f *fakeS3) called(op string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[op]
+}
+
+func (f *fakeS3) record(op string) {
+	f.calls[op]++
+}
+
+// ListObjectsV2 groups keys beyond the delimiter into common prefixes and pages through
+// both with MaxKeys, like S3; the continuation token is the index of the next entry.
+func (f *fakeS3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("ListObjectsV2")
+
+	prefix, delimiter := aws.ToString(params.Prefix), aws.ToString(params.Delimiter)
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	type entry struct {
+		name     string
+		isPrefix bool
+	}
+	var entries []entry
+	for _, k := range keys {
+		rest := strings.TrimPrefix(k, prefix)
+		if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+			cp := prefix + rest[:i+len(delimiter)]
+			if len(entries) == 0 || entries[len(entries)-1].name != cp {
+				entries = append(entries, entry{name: cp, isPrefix: true})
+			}
+			continue
+		}
+		entries = append(entries, entry{name: k})
+	}
+
+	start := 0
+	if params.ContinuationToken != nil {
+		start, _ = strconv.Atoi(*params.ContinuationToken)
+	}
+	end := len(entries)
+	maxKeys := int(aws.ToInt32(params.MaxKeys))
+	if maxKeys <= 0 {
+		maxKeys = f.pageSize
+	}
+	if maxKeys > 0 {
+		end = min(start+maxKeys, len(entries))
+	}
+
+	output := &s3.ListObjectsV2Output{Name: params.Bucket, Prefix: params.Prefix, IsTruncated: aws.Bool(end < len(entries))}
+	if end < len(entries) {
+		output.NextContinuationToken = aws.String(strconv.Itoa(end))
+	}
+	for _, e := range entries[start:end] {
+		if e.isPrefix {
+			output.CommonPrefixes = append(output.CommonPrefixes, types.CommonPrefix{Prefix: aws.String(e.name)})
+			continue
+		}
+		o := f.objects[e.name]
+		storageClass := o.storageClass
+		if storageClass == "" {
+			storageClass = types.ObjectStorageClassStandard
+		}
+		output.Contents = append(output.Contents, types.Object{
+			Key:          aws.String(e.name),
+			Size:         aws.Int64(int64(len(o.body))),
+			ETag:         aws.String(o.etag()),
+			LastModified: aws.Time(o.modified),
+			StorageClass: storageClass,
+		})
+	}
+	return output, nil
+}
+
+func (f *fakeS3) lookup(op string, key *string) (fakeObject, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record(op)
+	o, ok := f.objects[aws.ToString(key)]
+	if !ok {
+		return o, &types.NoSuchKey{Message: aws.String("The specified key does not exist.")}
+	}
+	return o, nil
+}
+
+func (f *fakeS3) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	o, err := f.lookup("HeadObject", params.Key)
+	if err != nil {
+		return nil, &types.NotFound{}
+	}
+	return &s3.HeadObjectOutput{
+		ContentType:   aws.String(o.contentType),
+		ContentLength: aws.Int64(int64(len(o.body))),
+		ETag:          aws.String(o.etag()),
+		LastModified:  aws.Time(o.modified),
+	}, nil
+}
+
+func (f *fakeS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	o, err := f.lookup("GetObject", params.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewReader(o.body)),
+		ContentType:   aws.String(o.contentType),
+		ContentLength: aws.Int64(int64(len(o.body))),
+		ETag:          aws.String(o.etag()),
+		LastModified:  aws.Time(o.modified),
+	}, nil
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	var body []byte
+	if params.Body != nil {
+		var err error
+		if body, err = io.ReadAll(params.Body); err != nil {
+			return nil, err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("PutObject")
+	o := fakeObject{body: body, contentType: aws.ToString(params.ContentType), modified: time.Now()}
+	f.objects[aws.ToString(params.Key)] = o
+	return &s3.PutObjectOutput{ETag: aws.String(o.etag())}, nil
+}
+
+func (f *fakeS3) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("DeleteObject")
+	delete(f.objects, aws.ToString(params.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.record("DeleteObjects")
+	for _, obj := range params.Delete.Objects {
+		delete(f.objects, aws.ToString(obj.Key))
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func TestLoadItemsPagesThroughFakeClient(t *testing.T) {
+	client := newFakeS3(map[string]string{
+		"logs/a.log":         "a",
+		"logs/b.log":         "bb",
+		"logs/2024/01/c.log": "ccc",
+		"logs/2024/02/d.log": "dddd",
+		"other.txt":          "x",
+	})
+	m := initialModel("test-bucket", options{pageSize: 2})
+	m.client = client
+	m.currentPrefix = "logs/"
+
+	first, ok := m.loadItems().(itemsLoadedMsg)
+	if !ok {
+		t.Fatalf("expected items, got %#v", m.loadItems())
+	}
+	if !first.hasMore || fmt.Sprint(keysOf(first.items)) != "[logs/2024/ logs/a.log]" {
+		t.Fatalf("first page = %v (more: %v), want the 2024/ directory and a.log", keysOf(first.items), first.hasMore)
+	}
+
+	m.nextPageToken = first.nextToken
+	second := m.loadItems().(itemsLoadedMsg)
+	if second.hasMore || fmt.Sprint(keysOf(second.items)) != "[logs/b.log]" {
+		t.Errorf("second page = %v (more: %v), want only b.log", keysOf(second.items), second.hasMore)
+	}
+	if got := client.called("ListObjectsV2"); got != 2 {
+		t.Errorf("%d ListObjectsV2 calls, want 2", got)
+	}
+}
+
+func TestLoadItemsFetchesContentTypesFromFakeClient(t *testing.T) {
+	client := newFakeS3(map[string]string{"a.txt": "a", "b.txt": "b", "dir/c.txt": "c"})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	m.showContentType = true
+
+	updated, _ := m.Update(m.loadItems())
+	m = updated.(Model)
+
+	if got := client.called("HeadObject"); got != 2 {
+		t.Errorf("%d HeadObject calls, want one per object and none for the directory", got)
+	}
+	if d := m.list.Items()[1].(item).Description(); !strings.Contains(d, "Content-Type: text/plain") {
+		t.Errorf("description %q, want the fetched content type", d)
+	}
+}
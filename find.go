@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFindResults is how many matches a recursive search collects before it stops.
+const maxFindResults = 1000
+
+// errEnoughResults stops the walk once maxFindResults matches are collected.
+var errEnoughResults = errors.New("enough results")
+
+// findResultsMsg carries the objects under prefix whose key matches term. Truncated is
+// set when the search stopped early, at maxFindResults matches or the --max-items cap.
+type findResultsMsg struct {
+	bucket    string
+	prefix    string
+	term      string
+	items     []list.Item
+	truncated bool
+}
+
+// keyMatcher returns whether an object matches term: a glob (see keyGlob) is matched
+// against its full key, and plain text is looked for in its key below the searched
+// prefix, ignoring case.
+func keyMatcher(term string) (func(key, rel string) bool, error) {
+	if isGlob(term) {
+		g, err := parseKeyGlob(term)
+		if err != nil {
+			return nil, err
+		}
+		return func(key, _ string) bool { return g.match(key) }, nil
+	}
+	needle := strings.ToLower(term)
+	return func(_, rel string) bool { return strings.Contains(strings.ToLower(rel), needle) }, nil
+}
+
+// findObjects walks every object under prefix, at any depth, and collects those that
+// match term, checking each page of keys as it arrives.
+func findObjects(ctx context.Context, client S3API, bucket, prefix, term string, limit int) tea.Msg {
+	matches, err := keyMatcher(term)
+	if err != nil {
+		return errorMsg{err: err}
+	}
+	var items []list.Item
+	err = walkObjects(ctx, client, bucket, prefix, limit, func(obj types.Object) error {
+		key := aws.StringValue(obj.Key)
+		rel := strings.TrimPrefix(key, prefix)
+		if rel == "" || !matches(key, rel) {
+			return nil
+		}
+		items = append(items, item{
+			key:        key,
+			size:       aws.Int64Value(obj.Size),
+			displayKey: rel,
+			modified:   aws.TimeValue(obj.LastModified),
+		}.withStorage(obj))
+		if len(items) >= maxFindResults {
+			return errEnoughResults
+		}
+		return nil
+	})
+	var maxErr *maxItemsError
+	truncated := errors.Is(err, errEnoughResults) || errors.As(err, &maxErr)
+	if err != nil && !truncated {
+		return errorMsg{err: fmt.Errorf("failed to search %s: %w", s3URI(bucket, prefix), err), retry: func() tea.Msg {
+			return findRequestMsg{bucket: bucket, prefix: prefix, term: term}
+		}}
+	}
+	return findResultsMsg{bucket: bucket, prefix: prefix, term: term, items: items, truncated: truncated}
+}
+
+// findRequestMsg asks for the search of term under prefix again, from the error panel.
+type findRequestMsg struct {
+	bucket string
+	prefix string
+	term   string
+}
+
+func (m *Model) promptFind() tea.Cmd {
+	return m.openPrompt(promptFind, fmt.Sprintf("Find under %s (text or glob, e.g. **/*.gz): ", s3URI(m.bucketName, m.currentPrefix)), "")
+}
+
+// startFind searches everything under the current prefix for keys matching term.
+func (m *Model) startFind(term string) tea.Cmd {
+	if term == "" {
+		return nil
+	}
+	if _, err := keyMatcher(term); err != nil {
+		m.setErrorStatus(err.Error())
+		return nil
+	}
+	return m.startFindIn(m.bucketName, m.currentPrefix, term)
+}
+
+// startFindIn searches everything under prefix in bucket for term as an operation.
+func (m *Model) startFindIn(bucket, prefix, term string) tea.Cmd {
+	client, limit := m.client, m.opts.maxItems
+	return m.startOperation(fmt.Sprintf("Searching %s for %q", s3URI(bucket, prefix), term), func(ctx context.Context) tea.Msg {
+		return findObjects(ctx, client, bucket, prefix, term, limit)
+	})
+}
+
+// showFindResults lists the matches in the picker, where enter opens an object.
+func (m *Model) showFindResults(msg findResultsMsg) tea.Cmd {
+	if len(msg.items) == 0 {
+		verb := "contain"
+		if isGlob(msg.term) {
+			verb = "match"
+		}
+		return m.setStatus(fmt.Sprintf("No keys under %s %s %q", s3URI(msg.bucket, msg.prefix), verb, msg.term))
+	}
+	title := fmt.Sprintf("%d matches for %q under %s/%s", len(msg.items), msg.term, msg.bucket, msg.prefix)
+	if msg.truncated {
+		title = fmt.Sprintf("First %d matches for %q under %s/%s (search stopped early)", len(msg.items), msg.term, msg.bucket, msg.prefix)
+	}
+	m.openPicker(pickerFind, title, msg.items)
+	m.picker.Filter = fuzzyFilter
+	m.picker.SetStatusBarItemName("match", "matches")
+	return nil
+}
@@ -0,0 +1,106 @@
+// ABOUTME: Tests for the recursive search in find.go.
+// ABOUTME: Covers collecting matches across listing pages, glob patterns, the scan cap and showing the results.
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestFindObjectsCollectsMatchesAcrossPages(t *testing.T) {
+	client := newTestS3Client(t, listingServer(t, 12))
+	msg, ok := findObjects(context.Background(), client, "b", "a/", "1", 0).(findResultsMsg)
+	if !ok {
+		t.Fatalf("expected findResultsMsg, got %T", msg)
+	}
+	if got := fmt.Sprint(keysOf(msg.items)); got != "[a/1 a/10 a/11]" {
+		t.Errorf("matches = %s", got)
+	}
+	if msg.truncated {
+		t.Error("a complete search should not be truncated")
+	}
+	if i := msg.items[0].(item); i.displayKey != "1" || i.isDir {
+		t.Errorf("unexpected result item %+v", i)
+	}
+}
+
+func TestFindObjectsStopsAtScanCap(t *testing.T) {
+	client := newTestS3Client(t, listingServer(t, 12))
+	msg := findObjects(context.Background(), client, "b", "a/", "1", 5).(findResultsMsg)
+	if got := fmt.Sprint(keysOf(msg.items)); got != "[a/1]" {
+		t.Errorf("matches = %s", got)
+	}
+	if !msg.truncated {
+		t.Error("expected the search to report it stopped early")
+	}
+}
+
+func TestFindObjectsIgnoresCase(t *testing.T) {
+	client := newTestS3Client(t, listingServer(t, 2))
+	msg := findObjects(context.Background(), client, "b", "", "A/", 0).(findResultsMsg)
+	if len(msg.items) != 2 {
+		t.Errorf("matched %s, want both keys", keysOf(msg.items))
+	}
+}
+
+func TestShowFindResults(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+
+	m.showFindResults(findResultsMsg{bucket: "test-bucket", term: "x"})
+	if m.pickerKind != pickerNone || m.statusMsg != `No keys under s3://test-bucket/ contain "x"` {
+		t.Errorf("picker %v, status %q", m.pickerKind, m.statusMsg)
+	}
+
+	m.showFindResults(findResultsMsg{bucket: "test-bucket", term: "x", items: []list.Item{item{key: "d/x", displayKey: "d/x"}}})
+	if m.pickerKind != pickerFind || len(m.picker.Items()) != 1 {
+		t.Errorf("expected the results picker, got kind %v with %d items", m.pickerKind, len(m.picker.Items()))
+	}
+}
+
+func TestFindObjectsMatchesGlobAgainstFullKeys(t *testing.T) {
+	client := newFakeS3(map[string]string{
+		"logs/2024/app.log.gz": "a",
+		"logs/2024/app.log":    "b",
+		"logs/current.gz":      "c",
+		"logs/README":          "d",
+		"other/x.gz":           "e",
+	})
+	client.pageSize = 2
+
+	msg := findObjects(context.Background(), client, "b", "logs/", "logs/**/*.gz", 0).(findResultsMsg)
+	if got := fmt.Sprint(keysOf(msg.items)); got != "[logs/2024/app.log.gz logs/current.gz]" {
+		t.Errorf("matches = %s", got)
+	}
+	if i := msg.items[0].(item); i.displayKey != "2024/app.log.gz" {
+		t.Errorf("expected results to show the key below the prefix, got %q", i.displayKey)
+	}
+
+	msg = findObjects(context.Background(), client, "b", "", "*.gz", 0).(findResultsMsg)
+	if got := fmt.Sprint(keysOf(msg.items)); got != "[logs/2024/app.log.gz logs/current.gz other/x.gz]" {
+		t.Errorf("a pattern without a slash should match names at any depth, got %s", got)
+	}
+}
+
+func TestStartFindRejectsInvalidGlob(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	if cmd := m.startFind("logs/[a-"); cmd != nil || !m.statusIsError {
+		t.Errorf("expected an invalid pattern to be reported without searching, status %q", m.statusMsg)
+	}
+}
+
+func TestFindRetryStartsANewSearch(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.client = newTestS3Client(t, failingOnce(`<ListBucketResult><Name>test-bucket</Name><Contents><Key>logs/app.log</Key><Size>1</Size></Contents></ListBucketResult>`))
+	m.loading = false
+	m.currentPrefix = "logs/"
+
+	m, cmd := retryFailedOperation(t, m, m.startFind("app"))
+	updated, _ := m.Update(operationResult(t, cmd))
+	if m = updated.(Model); m.pickerKind != pickerFind || len(m.picker.Items()) != 1 {
+		t.Errorf("expected the retried search to show its match, got picker %v, err %q", m.pickerKind, m.errMsg)
+	}
+}
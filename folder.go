@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// folderCreatedMsg reports the zero-byte marker written for a new folder.
+type folderCreatedMsg struct {
+	bucket string
+	key    string
+	// shown is the folder to select in the current listing, the first level of key below it.
+	shown string
+}
+
+// folderKey builds the marker key for a folder named name under prefix. Slashes in name
+// nest folders, so "a/b" creates prefix+"a/b/"; empty segments are dropped and "." or
+// ".." are rejected because S3 would store them literally.
+func folderKey(prefix, name string) (string, error) {
+	var segments []string
+	for _, s := range strings.Split(name, "/") {
+		s = strings.TrimSpace(s)
+		switch s {
+		case "":
+			continue
+		case ".", "..":
+			return "", fmt.Errorf("folder names cannot contain %q", s)
+		}
+		segments = append(segments, s)
+	}
+	if len(segments) == 0 {
+		return "", errors.New("folder name is empty")
+	}
+	return prefix + strings.Join(segments, "/") + "/", nil
+}
+
+func (m *Model) promptNewFolder() tea.Cmd {
+	return m.openPrompt(promptNewFolder, fmt.Sprintf("New folder in %s: ", s3URI(m.bucketName, m.currentPrefix)), "")
+}
+
+// startNewFolder writes the marker for the folder typed into the prompt.
+func (m *Model) startNewFolder(name string) tea.Cmd {
+	if strings.TrimSpace(name) == "" {
+		return nil
+	}
+	key, err := folderKey(m.currentPrefix, name)
+	if err != nil {
+		m.setErrorStatus(fmt.Sprintf("Cannot create folder: %v", err))
+		return nil
+	}
+	if m.opts.dryRun {
+		return m.setStatus(skipped("PutObject", m.bucketName, key).String())
+	}
+	shown := m.currentPrefix + strings.SplitAfter(strings.TrimPrefix(key, m.currentPrefix), "/")[0]
+	return createFolder(m.ctx, m.client, m.bucketName, key, shown)
+}
+
+// createFolder puts the empty object whose key ends in "/" that the S3 console uses as a folder.
+func createFolder(ctx context.Context, client S3API, bucket, key, shown string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:        aws.String(bucket),
+			Key:           aws.String(key),
+			Body:          strings.NewReader(""),
+			ContentLength: aws.Int64(0),
+		})
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to create folder %s: %w", s3URI(bucket, key), err), retry: createFolder(ctx, client, bucket, key, shown)}
+		}
+		return folderCreatedMsg{bucket: bucket, key: key, shown: shown}
+	}
+}
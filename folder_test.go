@@ -0,0 +1,65 @@
+// ABOUTME: Tests for creating folders in folder.go.
+// ABOUTME: Covers building marker keys for simple and nested names and writing the empty marker.
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestFolderKey(t *testing.T) {
+	tests := []struct {
+		prefix, name, want string
+	}{
+		{"", "logs", "logs/"},
+		{"data/", "logs", "data/logs/"},
+		{"data/", "2024/01", "data/2024/01/"},
+		{"data/", "/a//b/", "data/a/b/"},
+		{"data/", " my folder ", "data/my folder/"},
+	}
+	for _, tt := range tests {
+		got, err := folderKey(tt.prefix, tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("folderKey(%q, %q) = %q, %v; want %q", tt.prefix, tt.name, got, err, tt.want)
+		}
+	}
+	for _, name := range []string{"", "//", "a/../b", "."} {
+		if key, err := folderKey("data/", name); err == nil {
+			t.Errorf("folderKey(%q) = %q, want an error", name, key)
+		}
+	}
+}
+
+func TestCreateFolderWritesEmptyMarker(t *testing.T) {
+	var method, path string
+	var body []byte
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+	})
+	msg := createFolder(context.Background(), client, "b", "data/new/", "data/new/")()
+	if created, ok := msg.(folderCreatedMsg); !ok || created.key != "data/new/" {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	if method != http.MethodPut || path != "/b/data/new/" || len(body) != 0 {
+		t.Errorf("got %s %s with %d bytes", method, path, len(body))
+	}
+}
+
+func TestStartNewFolder(t *testing.T) {
+	m := initialModel("test-bucket", options{dryRun: true})
+	m.loading = false
+	m.currentPrefix = "data/"
+	m.startNewFolder("a/b")
+	if m.statusMsg != "[dry-run] would PutObject s3://test-bucket/data/a/b/" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+
+	m.opts.dryRun = false
+	m.startNewFolder("..")
+	if !m.statusIsError {
+		t.Errorf("expected an error status, got %q", m.statusMsg)
+	}
+}
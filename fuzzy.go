@@ -0,0 +1,28 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/sahilm/fuzzy"
+)
+
+// titlePrefixLen is the width in runes of the icon and space item.Title puts before the display key.
+const titlePrefixLen = 2
+
+// fuzzyFilter ranks listing items by how well their display key fuzzy-matches term, ignoring case,
+// so "apmx" finds app-max.log. Matched indexes are shifted onto the title so the delegate
+// highlights the right characters.
+func fuzzyFilter(term string, targets []string) []list.Rank {
+	matches := fuzzy.Find(term, targets)
+	sort.Stable(matches)
+	ranks := make([]list.Rank, len(matches))
+	for idx, match := range matches {
+		indexes := make([]int, len(match.MatchedIndexes))
+		for j, i := range match.MatchedIndexes {
+			indexes[j] = i + titlePrefixLen
+		}
+		ranks[idx] = list.Rank{Index: match.Index, MatchedIndexes: indexes}
+	}
+	return ranks
+}
@@ -0,0 +1,47 @@
+// ABOUTME: Tests for the fuzzy listing filter in fuzzy.go.
+// ABOUTME: Covers ranking display keys by match score, case-insensitivity and title highlighting offsets.
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func rankedKeys(term string, targets []string) []string {
+	var keys []string
+	for _, r := range fuzzyFilter(term, targets) {
+		keys = append(keys, targets[r.Index])
+	}
+	return keys
+}
+
+func TestFuzzyFilterRanksByScore(t *testing.T) {
+	targets := []string{"logs/", "application-metrics.txt", "app-max.log", "ample.txt", "readme.md"}
+	got := rankedKeys("apmx", targets)
+	want := []string{"app-max.log", "application-metrics.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ranked = %v, want %v", got, want)
+	}
+}
+
+func TestFuzzyFilterIgnoresCase(t *testing.T) {
+	targets := []string{"Reports/", "notes.txt", "REPORT-2024.csv"}
+	got := rankedKeys("report", targets)
+	if len(got) != 2 {
+		t.Errorf("matched %v, want both reports", got)
+	}
+}
+
+func TestFuzzyFilterShiftsIndexesOntoTitle(t *testing.T) {
+	ranks := fuzzyFilter("ab", []string{"ab.txt"})
+	if len(ranks) != 1 {
+		t.Fatalf("got %d ranks", len(ranks))
+	}
+	if want := []int{2, 3}; !reflect.DeepEqual(ranks[0].MatchedIndexes, want) {
+		t.Errorf("matched indexes = %v, want %v", ranks[0].MatchedIndexes, want)
+	}
+	i := item{key: "dir/ab.txt", displayKey: "ab.txt"}
+	if title := []rune(i.Title()); string(title[2:4]) != "ab" {
+		t.Errorf("title %q does not line up with the shifted indexes", i.Title())
+	}
+}
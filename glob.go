@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// isGlob reports whether a search term uses glob syntax rather than being plain text.
+func isGlob(term string) bool {
+	return strings.ContainsAny(term, "*?[")
+}
+
+// keyGlob matches whole keys against a pattern like "logs/**/*.gz". Each segment is
+// matched with path.Match, so * and ? stay within a segment, and a "**" segment matches
+// any number of segments, including none. A pattern without a slash matches the last
+// segment of a key at any depth, so "*.json" finds every JSON object; a pattern ending in
+// a slash matches everything under the directories it names.
+type keyGlob struct {
+	segments []string
+	baseName bool
+}
+
+// parseKeyGlob checks every segment of pattern is valid for path.Match.
+func parseKeyGlob(pattern string) (keyGlob, error) {
+	if strings.HasSuffix(pattern, "/") {
+		pattern += "**"
+	}
+	g := keyGlob{segments: strings.Split(pattern, "/"), baseName: !strings.Contains(pattern, "/")}
+	for _, s := range g.segments {
+		if _, err := path.Match(s, ""); err != nil {
+			return keyGlob{}, fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	return g, nil
+}
+
+func (g keyGlob) match(key string) bool {
+	if g.baseName {
+		key = key[strings.LastIndex(key, "/")+1:]
+	}
+	return matchSegments(g.segments, strings.Split(key, "/"))
+}
+
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for skip := 0; skip <= len(segments); skip++ {
+				if matchSegments(pattern[1:], segments[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,70 @@
+// ABOUTME: Tests for the key glob patterns the recursive search accepts, in glob.go.
+// ABOUTME: Covers single-segment wildcards, ** across directories, trailing slashes and invalid patterns.
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyGlobMatch(t *testing.T) {
+	keys := []string{
+		"README",
+		"config.json",
+		"logs/app.log",
+		"logs/2024/06/app.log.gz",
+		"logs/2024/06/access.gz",
+		"logs/gz",
+		"data/logs/old.gz",
+		"data/nested/settings.json",
+		"photos/",
+	}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.json", []string{"config.json", "data/nested/settings.json"}},
+		{"*.gz", []string{"logs/2024/06/app.log.gz", "logs/2024/06/access.gz", "data/logs/old.gz"}},
+		{"logs/**/*.gz", []string{"logs/2024/06/app.log.gz", "logs/2024/06/access.gz"}},
+		{"logs/*", []string{"logs/app.log", "logs/gz"}},
+		{"**/logs/*.gz", []string{"data/logs/old.gz"}},
+		{"**/*.log", []string{"logs/app.log"}},
+		{"logs/2024/", []string{"logs/2024/06/app.log.gz", "logs/2024/06/access.gz"}},
+		{"photos/", []string{"photos/"}},
+		{"READM?", []string{"README"}},
+		{"logs/**", []string{"logs/app.log", "logs/2024/06/app.log.gz", "logs/2024/06/access.gz", "logs/gz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			g, err := parseKeyGlob(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, k := range keys {
+				if g.match(k) {
+					got = append(got, k)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%q matched %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKeyGlobRejectsInvalidPatterns(t *testing.T) {
+	for _, bad := range []string{"[", "logs/[a-/*.gz"} {
+		if _, err := parseKeyGlob(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestIsGlob(t *testing.T) {
+	for term, want := range map[string]bool{"*.json": true, "app?.log": true, "[ab].txt": true, "logs/app": false, "report 2024": false} {
+		if got := isGlob(term); got != want {
+			t.Errorf("isGlob(%q) = %v, want %v", term, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// normalizePrefix turns a typed path into a prefix: no leading slash, one trailing
+// slash, and "" for the bucket root.
+func normalizePrefix(path string) string {
+	path = strings.TrimLeft(strings.TrimSpace(path), "/")
+	if path == "" {
+		return ""
+	}
+	return strings.TrimRight(path, "/") + "/"
+}
+
+// promptGoTo asks for a prefix to jump to, starting from the current one.
+func (m *Model) promptGoTo() tea.Cmd {
+	return m.openPrompt(promptGoTo, "Go to: "+m.bucketName+"/", m.currentPrefix)
+}
+
+// goTo lists the typed prefix of the current bucket; an empty prefix is reported by the listing.
+func (m *Model) goTo(path string) tea.Cmd {
+	return m.jumpTo(m.bucketName, normalizePrefix(path))
+}
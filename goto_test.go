@@ -0,0 +1,59 @@
+// ABOUTME: Tests for jumping to a typed prefix in goto.go.
+// ABOUTME: Covers normalizing slashes and listing the prefix from the go-to prompt.
+package main
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNormalizePrefix(t *testing.T) {
+	tests := map[string]string{
+		"logs/2024/06/":   "logs/2024/06/",
+		"logs/2024/06":    "logs/2024/06/",
+		"/logs/2024":      "logs/2024/",
+		"  //logs//  ":    "logs/",
+		"":                "",
+		"/":               "",
+		"a b/with space/": "a b/with space/",
+	}
+	for in, want := range tests {
+		if got := normalizePrefix(in); got != want {
+			t.Errorf("normalizePrefix(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGoToPromptJumpsToPrefix(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.currentPrefix = "logs/"
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	m = updated.(Model)
+	if m.prompt != promptGoTo || m.promptInput.Value() != "logs/" {
+		t.Fatalf("expected a go-to prompt prefilled with the current prefix, got %v %q", m.prompt, m.promptInput.Value())
+	}
+	for _, r := range "2024/06" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.currentPrefix != "logs/2024/06/" || !m.loading {
+		t.Errorf("prefix=%q loading=%v, want a listing of logs/2024/06/", m.currentPrefix, m.loading)
+	}
+}
+
+func TestGoToEmptyPrefixShowsEmptyStatus(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.goTo("nothing/here")
+
+	updated, _ := m.Update(itemsLoadedMsg{})
+	m = updated.(Model)
+	if m.errMsg != "" || m.statusMsg != "Directory is empty" {
+		t.Errorf("err=%q status=%q, want the empty directory status", m.errMsg, m.statusMsg)
+	}
+}
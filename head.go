@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+)
+
+// Exit codes of --head, so scripts can tell a missing object from a failed check.
+const (
+	headExitFound    = 0
+	headExitNotFound = 1
+	headExitError    = 2
+)
+
+// headResult is the JSON printed by --head.
+type headResult struct {
+	Bucket       string    `json:"bucket"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"content_type"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// runHead checks that key exists without starting the TUI, printing its metadata as JSON
+// to stdout or the failure to stderr, and returns the process exit code.
+func runHead(ctx context.Context, client S3API, bucket, key string, stdout, stderr io.Writer) int {
+	output, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			fmt.Fprintf(stderr, "%s not found\n", s3URI(bucket, key))
+			return headExitNotFound
+		}
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return headExitError
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(headResult{
+		Bucket:       bucket,
+		Key:          key,
+		Size:         aws.Int64Value(output.ContentLength),
+		ContentType:  aws.StringValue(output.ContentType),
+		ETag:         aws.StringValue(output.ETag),
+		LastModified: aws.TimeValue(output.LastModified),
+	}); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return headExitError
+	}
+	return headExitFound
+}
+
+// isNotFound reports whether err means the object does not exist. HeadObject responses
+// have no body, so S3 reports a missing key as a bare 404 NotFound.
+func isNotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) {
+		return true
+	}
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NoSuchKey" || apiErr.ErrorCode() == "NotFound")
+}
@@ -0,0 +1,52 @@
+// ABOUTME: Tests for the non-interactive --head check in head.go.
+// ABOUTME: Covers the JSON output and exit codes for found, missing and failing objects.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestRunHeadPrintsMetadata(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/test-bucket/dir/a.txt" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Length", "42")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	})
+
+	var stdout, stderr bytes.Buffer
+	if code := runHead(context.Background(), client, "test-bucket", "dir/a.txt", &stdout, &stderr); code != headExitFound {
+		t.Fatalf("exit code %d, want %d (stderr %q)", code, headExitFound, stderr.String())
+	}
+	var got headResult
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout.String())
+	}
+	if got.Key != "dir/a.txt" || got.Size != 42 || got.ContentType != "text/plain" || got.ETag != `"abc"` || got.LastModified.Year() != 2006 {
+		t.Errorf("unexpected metadata %+v", got)
+	}
+}
+
+func TestRunHeadExitCodes(t *testing.T) {
+	notFound := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	var stdout, stderr bytes.Buffer
+	if code := runHead(context.Background(), notFound, "test-bucket", "missing", &stdout, &stderr); code != headExitNotFound {
+		t.Errorf("missing object: exit code %d, want %d", code, headExitNotFound)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing on stdout for a missing object, got %q", stdout.String())
+	}
+
+	if code := runHead(context.Background(), failingS3Client(t), "test-bucket", "a", &stdout, &stderr); code != headExitError {
+		t.Errorf("server error: exit code %d, want %d", code, headExitError)
+	}
+}
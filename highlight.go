@@ -0,0 +1,58 @@
+package main
+
+import (
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxHighlightBytes is the largest body the viewer colours; tokenising more makes opening
+// the object noticeably slow, so bigger ones are shown as plain text.
+const maxHighlightBytes = 512 * 1024
+
+// genericContentTypes say nothing about an object's language, and some lexers claim them anyway.
+var genericContentTypes = map[string]bool{
+	"text/plain":               true,
+	"application/octet-stream": true,
+	"binary/octet-stream":      true,
+}
+
+// syntaxLexer picks the lexer for an object from its key's extension, then from its
+// content type. It returns nil for plain text and anything it does not recognise.
+func syntaxLexer(key, contentType string) chroma.Lexer {
+	name := path.Base(key)
+	lexer := lexers.Match(name)
+	if lexer == nil {
+		lexer = lexers.Match(strings.ToLower(name))
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); lexer == nil && err == nil && !genericContentTypes[mediaType] {
+		lexer = lexers.MatchMimeType(mediaType)
+	}
+	if lexer == nil || lexer.Config().Name == "plaintext" {
+		return nil
+	}
+	return lexer
+}
+
+// highlightSyntax colours body for the terminal with lexer.
+func highlightSyntax(body string, lexer chroma.Lexer) (string, error) {
+	style := styles.Get("monokailight")
+	if lipgloss.HasDarkBackground() {
+		style = styles.Get("monokai")
+	}
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, body)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := formatters.TTY256.Format(&b, style, tokens); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
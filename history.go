@@ -0,0 +1,40 @@
+package main
+
+// maxHistory caps how many visited locations the back/forward history remembers.
+const maxHistory = 100
+
+// location is a place in the browser that history can return to.
+type location struct {
+	bucket     string
+	prefix     string
+	searchTerm string
+}
+
+// navHistory is a browser-style list of visited locations with a cursor at the current one.
+type navHistory struct {
+	entries []location
+	pos     int
+}
+
+// record adds loc after the current position, dropping any forward entries.
+func (h *navHistory) record(loc location) {
+	if len(h.entries) > 0 && h.entries[h.pos] == loc {
+		return
+	}
+	if len(h.entries) > 0 {
+		h.entries = h.entries[:h.pos+1]
+	}
+	h.entries = append(h.entries, loc)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+	}
+	h.pos = len(h.entries) - 1
+}
+
+// at returns the entry at pos, if there is one.
+func (h navHistory) at(pos int) (location, bool) {
+	if pos < 0 || pos >= len(h.entries) {
+		return location{}, false
+	}
+	return h.entries[pos], true
+}
@@ -0,0 +1,94 @@
+// ABOUTME: Tests for back/forward navigation history in history.go.
+// ABOUTME: Covers recording, truncation of forward entries and moving through the model.
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHistoryRecordDropsForwardEntries(t *testing.T) {
+	var h navHistory
+	h.record(location{bucket: "b", prefix: ""})
+	h.record(location{bucket: "b", prefix: "a/"})
+	h.record(location{bucket: "b", prefix: "a/"})
+	h.record(location{bucket: "b", prefix: "a/b/"})
+	if len(h.entries) != 3 {
+		t.Fatalf("expected consecutive duplicates to be collapsed, got %+v", h.entries)
+	}
+
+	h.pos = 0
+	h.record(location{bucket: "b", prefix: "c/"})
+
+	if len(h.entries) != 2 || h.entries[1].prefix != "c/" || h.pos != 1 {
+		t.Errorf("expected forward entries to be replaced, got %+v at %d", h.entries, h.pos)
+	}
+}
+
+func TestHistoryIsCapped(t *testing.T) {
+	var h navHistory
+	for i := 0; i < maxHistory+10; i++ {
+		h.record(location{prefix: string(rune('a'+i%26)) + string(rune('0'+i/26))})
+	}
+	if len(h.entries) != maxHistory || h.pos != maxHistory-1 {
+		t.Errorf("expected history capped at %d, got %d entries at %d", maxHistory, len(h.entries), h.pos)
+	}
+}
+
+func TestHistoryBackAndForward(t *testing.T) {
+	loaded := func(m Model) Model {
+		updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{item{key: "x", displayKey: "x"}}})
+		return updated.(Model)
+	}
+	m := initialModel("test-bucket", options{})
+	m = loaded(m)
+	m.jumpTo("test-bucket", "logs/2024/")
+	m = loaded(m)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyLeft, Alt: true})
+	m = loaded(updated.(Model))
+	if m.currentPrefix != "" {
+		t.Fatalf("expected history back to return to the root, got %q", m.currentPrefix)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight, Alt: true})
+	m = loaded(updated.(Model))
+	if m.currentPrefix != "logs/2024/" {
+		t.Errorf("expected history forward to return to logs/2024/, got %q", m.currentPrefix)
+	}
+	if len(m.history.entries) != 2 {
+		t.Errorf("expected moving through history not to record new entries, got %+v", m.history.entries)
+	}
+}
+
+func TestHistoryBackListsTheSearch(t *testing.T) {
+	var prefixes []string
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get("prefix")
+		prefixes = append(prefixes, prefix)
+		fmt.Fprintf(w, `<ListBucketResult><Name>test-bucket</Name><Contents><Key>%sx</Key><Size>1</Size></Contents></ListBucketResult>`, prefix)
+	})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	run := func(m Model, cmd tea.Cmd) Model {
+		updated, _ := m.Update(operationResult(t, cmd))
+		return updated.(Model)
+	}
+	m = run(m, m.jumpToSearch("test-bucket", "logs/", "a"))
+	m = run(m, m.jumpTo("test-bucket", "data/"))
+	// Forget the listings so going back has to ask S3 again.
+	m.cache = newListingCache()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyLeft, Alt: true})
+	m = run(updated.(Model), cmd)
+	if m.searchTerm != "a" || m.list.Title != "test-bucket/logs/ [search: a]" {
+		t.Errorf("search %q, title %q, want back on the search for a", m.searchTerm, m.list.Title)
+	}
+	if last := prefixes[len(prefixes)-1]; last != "logs/a" {
+		t.Errorf("listed prefix %q, want logs/a", last)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxImagePreviewBytes bounds how much of an image object is downloaded for a preview.
+const maxImagePreviewBytes = 20 << 20
+
+// Rough cell size in pixels, used to fit sixel output into the terminal.
+const (
+	cellWidthPx  = 10
+	cellHeightPx = 20
+)
+
+// graphicsProtocol is a terminal's inline image protocol.
+type graphicsProtocol int
+
+const (
+	graphicsNone graphicsProtocol = iota
+	graphicsKitty
+	graphicsITerm2
+	graphicsSixel
+)
+
+// detectGraphics guesses the inline image protocol the terminal supports from its environment.
+func detectGraphics() graphicsProtocol {
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty", termProgram == "ghostty":
+		return graphicsKitty
+	case termProgram == "iTerm.app", termProgram == "WezTerm", os.Getenv("LC_TERMINAL") == "iTerm2":
+		return graphicsITerm2
+	case strings.Contains(term, "sixel"), term == "foot", strings.HasPrefix(term, "mlterm"), strings.HasPrefix(term, "yaft"):
+		return graphicsSixel
+	}
+	return graphicsNone
+}
+
+// describeImage summarizes an image's format and dimensions without decoding its pixels.
+func describeImage(data []byte) (string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%dx%d %s", cfg.Width, cfg.Height, strings.ToUpper(format)), nil
+}
+
+// imagePreview is a tea.ExecCommand that draws an image with the terminal's
+// graphics protocol while the TUI has released the screen.
+type imagePreview struct {
+	data     []byte
+	protocol graphicsProtocol
+	caption  string
+	cols     int
+	rows     int
+
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (p *imagePreview) SetStdin(r io.Reader)  { p.stdin = r }
+func (p *imagePreview) SetStdout(w io.Writer) { p.stdout = w }
+func (p *imagePreview) SetStderr(io.Writer)   {}
+
+func (p *imagePreview) Run() error {
+	// Leave room for the caption below the image.
+	rows := max(p.rows-3, 1)
+	cols := max(p.cols, 1)
+
+	var out bytes.Buffer
+	out.WriteString("\x1b[2J\x1b[H")
+	if err := renderImage(&out, p.data, p.protocol, cols, rows); err != nil {
+		return err
+	}
+	fmt.Fprintf(&out, "\r\n%s\r\nPress enter to return", p.caption)
+	if _, err := p.stdout.Write(out.Bytes()); err != nil {
+		return err
+	}
+
+	_, err := bufio.NewReader(p.stdin).ReadString('\n')
+	if p.protocol == graphicsKitty {
+		io.WriteString(p.stdout, "\x1b_Ga=d\x1b\\")
+	}
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// renderImage writes the escape sequences that draw data within cols x rows cells.
+func renderImage(w io.Writer, data []byte, protocol graphicsProtocol, cols, rows int) error {
+	switch protocol {
+	case graphicsKitty:
+		pngData, err := asPNG(data)
+		if err != nil {
+			return err
+		}
+		return writeKittyImage(w, pngData, cols, rows)
+	case graphicsITerm2:
+		_, err := fmt.Fprintf(w, "\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+			len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+		return err
+	case graphicsSixel:
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		return writeSixel(w, scaleToFit(img, cols*cellWidthPx, rows*cellHeightPx))
+	}
+	return fmt.Errorf("terminal does not support inline images")
+}
+
+// asPNG re-encodes data as PNG, the only compressed format the kitty protocol accepts.
+func asPNG(data []byte) ([]byte, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format == "png" {
+		return data, nil
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeKittyImage transmits PNG data in the 4096-byte base64 chunks the kitty protocol requires.
+func writeKittyImage(w io.Writer, pngData []byte, cols, rows int) error {
+	payload := base64.StdEncoding.EncodeToString(pngData)
+	const chunkSize = 4096
+	for i := 0; i < len(payload) || i == 0; i += chunkSize {
+		end := min(i+chunkSize, len(payload))
+		more := 0
+		if end < len(payload) {
+			more = 1
+		}
+		var err error
+		if i == 0 {
+			_, err = fmt.Fprintf(w, "\x1b_Gf=100,a=T,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, payload[i:end])
+		} else {
+			_, err = fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, payload[i:end])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scaleToFit shrinks img with nearest-neighbour sampling so it fits in maxW x maxH pixels.
+func scaleToFit(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= maxW && b.Dy() <= maxH {
+		return img
+	}
+	scale := min(float64(maxW)/float64(b.Dx()), float64(maxH)/float64(b.Dy()))
+	w, h := max(int(float64(b.Dx())*scale), 1), max(int(float64(b.Dy())*scale), 1)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			dst.Set(x, y, img.At(b.Min.X+int(float64(x)/scale), b.Min.Y+int(float64(y)/scale)))
+		}
+	}
+	return dst
+}
+
+// writeSixel encodes img as a sixel image using the 256-colour Plan 9 palette.
+func writeSixel(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	p := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette.Plan9)
+	draw.FloydSteinberg.Draw(p, p.Bounds(), img, b.Min)
+	width, height := p.Bounds().Dx(), p.Bounds().Dy()
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "\x1bPq\"1;1;%d;%d", width, height)
+	for i, c := range p.Palette {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&buf, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	for y := 0; y < height; y += 6 {
+		var used [256]bool
+		for x := 0; x < width; x++ {
+			for k := 0; k < 6 && y+k < height; k++ {
+				used[p.ColorIndexAt(x, y+k)] = true
+			}
+		}
+		for c := range used {
+			if !used[c] {
+				continue
+			}
+			fmt.Fprintf(&buf, "#%d", c)
+			run, last := 0, byte(0)
+			flush := func() {
+				if run > 3 {
+					fmt.Fprintf(&buf, "!%d%c", run, last)
+				} else {
+					buf.Write(bytes.Repeat([]byte{last}, run))
+				}
+			}
+			for x := 0; x < width; x++ {
+				var bits byte
+				for k := 0; k < 6 && y+k < height; k++ {
+					if int(p.ColorIndexAt(x, y+k)) == c {
+						bits |= 1 << k
+					}
+				}
+				ch := 63 + bits
+				if run > 0 && ch != last {
+					flush()
+					run = 0
+				}
+				last = ch
+				run++
+			}
+			flush()
+			buf.WriteByte('$')
+		}
+		buf.WriteByte('-')
+	}
+	buf.WriteString("\x1b\\")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,97 @@
+// ABOUTME: Tests for inline image previews in image.go.
+// ABOUTME: Covers terminal capability detection and the graphics protocol encoders.
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectGraphics(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want graphicsProtocol
+	}{
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, graphicsKitty},
+		{"iterm2", map[string]string{"TERM_PROGRAM": "iTerm.app"}, graphicsITerm2},
+		{"sixel", map[string]string{"TERM": "foot"}, graphicsSixel},
+		{"plain", map[string]string{"TERM": "xterm-256color"}, graphicsNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []string{"TERM", "TERM_PROGRAM", "KITTY_WINDOW_ID", "LC_TERMINAL"} {
+				t.Setenv(k, "")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if got := detectGraphics(); got != tt.want {
+				t.Errorf("detectGraphics() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeImage(t *testing.T) {
+	desc, err := describeImage(testPNG(t, 4, 3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc != "4x3 PNG" {
+		t.Errorf("describeImage() = %q, want %q", desc, "4x3 PNG")
+	}
+	if _, err := describeImage([]byte("not an image")); err == nil {
+		t.Errorf("expected an error for non-image data")
+	}
+}
+
+func TestWriteKittyImageChunksPayload(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeKittyImage(&out, bytes.Repeat([]byte{1}, 5000), 80, 24); err != nil {
+		t.Fatal(err)
+	}
+	s := out.String()
+	if !strings.HasPrefix(s, "\x1b_Gf=100,a=T,c=80,r=24,m=1;") {
+		t.Errorf("expected first chunk to carry the image header, got %q", s[:40])
+	}
+	if !strings.Contains(s, "\x1b_Gm=0;") {
+		t.Errorf("expected a final chunk with m=0")
+	}
+}
+
+func TestRenderSixel(t *testing.T) {
+	var out bytes.Buffer
+	if err := renderImage(&out, testPNG(t, 8, 8), graphicsSixel, 80, 24); err != nil {
+		t.Fatal(err)
+	}
+	s := out.String()
+	if !strings.HasPrefix(s, "\x1bPq\"1;1;8;8") || !strings.HasSuffix(s, "\x1b\\") {
+		t.Errorf("expected a framed sixel image, got %q", s)
+	}
+}
+
+func TestScaleToFit(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	got := scaleToFit(img, 100, 100).Bounds()
+	if got.Dx() != 100 || got.Dy() != 50 {
+		t.Errorf("expected 100x50 after scaling, got %dx%d", got.Dx(), got.Dy())
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// objectInfoMsg carries what the inspector shows about one object. Tags are optional:
+// tagsErr is set when they could not be read, e.g. without s3:GetObjectTagging.
+type objectInfoMsg struct {
+	bucket  string
+	key     string
+	head    *s3.HeadObjectOutput
+	tags    []types.Tag
+	tagsErr error
+}
+
+// loadObjectInfo fetches the metadata and tags of key for the inspector.
+func loadObjectInfo(ctx context.Context, client S3API, bucket, key string) tea.Cmd {
+	return func() tea.Msg {
+		head, err := client.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to read %s: %w", key, err), retry: loadObjectInfo(ctx, client, bucket, key)}
+		}
+		msg := objectInfoMsg{bucket: bucket, key: key, head: head}
+		tagging, err := client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			msg.tagsErr = err
+		} else {
+			msg.tags = tagging.TagSet
+		}
+		return msg
+	}
+}
+
+// formatObjectInfo renders an objectInfoMsg as the inspector's text, one field per line.
+func formatObjectInfo(msg objectInfoMsg) string {
+	h := msg.head
+	var b strings.Builder
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%-23s %s\n", name+":", value)
+		}
+	}
+	field("Content-Type", aws.StringValue(h.ContentType))
+	if h.ContentLength != nil {
+		field("Content-Length", fmt.Sprintf("%d (%s)", *h.ContentLength, humanize.Bytes(uint64(*h.ContentLength))))
+	}
+	field("ETag", aws.StringValue(h.ETag))
+	if h.LastModified != nil {
+		field("Last-Modified", h.LastModified.Format("2006-01-02 15:04:05 MST"))
+	}
+	storage := string(h.StorageClass)
+	if storage == "" {
+		// HeadObject leaves the header out for STANDARD objects.
+		storage = string(types.StorageClassStandard)
+	}
+	field("Storage-Class", storage)
+	field("Restore", aws.StringValue(h.Restore))
+	field("Server-Side-Encryption", string(h.ServerSideEncryption))
+	field("SSE-KMS-Key-Id", aws.StringValue(h.SSEKMSKeyId))
+	field("Version-Id", aws.StringValue(h.VersionId))
+	field("Cache-Control", aws.StringValue(h.CacheControl))
+	field("Content-Encoding", aws.StringValue(h.ContentEncoding))
+
+	b.WriteString("\nMetadata:\n")
+	writePairs(&b, h.Metadata)
+
+	b.WriteString("\nTags:\n")
+	if msg.tagsErr != nil {
+		fmt.Fprintf(&b, "  (unavailable: %v)\n", msg.tagsErr)
+	} else {
+		tags := make(map[string]string, len(msg.tags))
+		for _, t := range msg.tags {
+			tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+		}
+		writePairs(&b, tags)
+	}
+	return b.String()
+}
+
+// writePairs lists pairs sorted by key, or "(none)".
+func writePairs(b *strings.Builder, pairs map[string]string) {
+	if len(pairs) == 0 {
+		b.WriteString("  (none)\n")
+		return
+	}
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(b, "  %s = %s\n", k, pairs[k])
+	}
+}
+
+// openObjectInfo shows the inspector in the built-in viewer.
+func (m *Model) openObjectInfo(msg objectInfoMsg) {
+	viewer := NewViewModel("Info: "+s3URI(msg.bucket, msg.key), "", formatObjectInfo(msg), m.lastWindowSize.Width, m.lastWindowSize.Height)
+	m.viewer = &viewer
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Tests for the object inspector in info.go.
+// ABOUTME: Covers formatting HeadObject and tagging responses and opening the inspector.
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestFormatObjectInfo(t *testing.T) {
+	msg := objectInfoMsg{
+		bucket: "b",
+		key:    "report.csv",
+		head: &s3.HeadObjectOutput{
+			ContentType:          aws.String("text/csv"),
+			ContentLength:        aws.Int64(2048),
+			ETag:                 aws.String(`"abc"`),
+			LastModified:         aws.Time(time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)),
+			ServerSideEncryption: types.ServerSideEncryptionAwsKms,
+			SSEKMSKeyId:          aws.String("arn:aws:kms:key/1"),
+			Metadata:             map[string]string{"owner": "ops", "build": "42"},
+		},
+		tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("data")}},
+	}
+
+	want := `Content-Type:           text/csv
+Content-Length:         2048 (2.0 kB)
+ETag:                   "abc"
+Last-Modified:          2024-06-01 12:30:00 UTC
+Storage-Class:          STANDARD
+Server-Side-Encryption: aws:kms
+SSE-KMS-Key-Id:         arn:aws:kms:key/1
+
+Metadata:
+  build = 42
+  owner = ops
+
+Tags:
+  team = data
+`
+	if got := formatObjectInfo(msg); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatObjectInfoWithoutTags(t *testing.T) {
+	got := formatObjectInfo(objectInfoMsg{head: &s3.HeadObjectOutput{}, tagsErr: errors.New("AccessDenied")})
+	if !strings.Contains(got, "Metadata:\n  (none)") || !strings.Contains(got, "Tags:\n  (unavailable: AccessDenied)") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+}
+
+func TestObjectInfoOpensInspector(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["tagging"]; ok {
+			w.Write([]byte(`<Tagging><TagSet><Tag><Key>env</Key><Value>prod</Value></Tag></TagSet></Tagging>`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("x-amz-storage-class", "GLACIER")
+	})
+	msg := loadObjectInfo(context.Background(), client, "test-bucket", "a.txt")()
+
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if m.viewer == nil {
+		t.Fatalf("expected the inspector to open, got %#v", msg)
+	}
+	for _, want := range []string{"Storage-Class:          GLACIER", "env = prod"} {
+		if !strings.Contains(m.viewer.body, want) {
+			t.Errorf("inspector lacks %q:\n%s", want, m.viewer.body)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// itemFilter narrows the loaded items to names matching a glob and/or a minimum size.
+type itemFilter struct {
+	pattern string
+	minSize uint64
+	// minSizeText is the minimum size as typed, which String gives back so the filter
+	// prompt shows it the way it was entered.
+	minSizeText string
+}
+
+// parseItemFilter reads filters like "*.log", ">10MB" or "*.log >10MB"; "" clears the filter.
+func parseItemFilter(s string) (itemFilter, error) {
+	var f itemFilter
+	for _, field := range strings.Fields(s) {
+		if size, ok := strings.CutPrefix(field, ">"); ok {
+			n, err := humanize.ParseBytes(size)
+			if err != nil {
+				return itemFilter{}, fmt.Errorf("invalid minimum size %q: %w", size, err)
+			}
+			f.minSize, f.minSizeText = n, size
+			continue
+		}
+		if f.pattern != "" {
+			return itemFilter{}, fmt.Errorf("only one glob pattern is supported, got %q and %q", f.pattern, field)
+		}
+		if _, err := filepath.Match(field, ""); err != nil {
+			return itemFilter{}, fmt.Errorf("invalid glob pattern %q: %w", field, err)
+		}
+		f.pattern = field
+	}
+	return f, nil
+}
+
+func (f itemFilter) active() bool {
+	return f.pattern != "" || f.minSize > 0
+}
+
+// match reports whether i passes the filter. Directories have no size, so a
+// minimum size hides them.
+func (f itemFilter) match(i item) bool {
+	if f.minSize > 0 && (i.isDir || uint64(i.size) < f.minSize) {
+		return false
+	}
+	if f.pattern != "" {
+		ok, _ := filepath.Match(f.pattern, i.displayKey)
+		return ok
+	}
+	return true
+}
+
+// String gives the filter back as text parseItemFilter reads.
+func (f itemFilter) String() string {
+	var parts []string
+	if f.pattern != "" {
+		parts = append(parts, f.pattern)
+	}
+	if f.minSize > 0 {
+		size := f.minSizeText
+		if size == "" {
+			size = strconv.FormatUint(f.minSize, 10)
+		}
+		parts = append(parts, ">"+size)
+	}
+	return strings.Join(parts, " ")
+}
+
+// itemKind limits the loaded items to directories or to files.
+type itemKind int
+
+const (
+	showAll itemKind = iota
+	showDirs
+	showFiles
+)
+
+func (k itemKind) String() string {
+	switch k {
+	case showDirs:
+		return "directories"
+	case showFiles:
+		return "files"
+	}
+	return "all"
+}
+
+func (k itemKind) next() itemKind {
+	return (k + 1) % 3
+}
+
+func (k itemKind) match(i item) bool {
+	switch k {
+	case showDirs:
+		return i.isDir
+	case showFiles:
+		return !i.isDir
+	}
+	return true
+}
+
+// itemNames is what the list's status bar counts in this mode, singular and plural.
+func (k itemKind) itemNames() (string, string) {
+	switch k {
+	case showDirs:
+		return "directory", "directories"
+	case showFiles:
+		return "file", "files"
+	}
+	return "object", "objects"
+}
+
+// visibleItems returns the loaded items that are of the shown kind and pass the item
+// filter. The list's own fuzzy filter narrows these further.
+func (m Model) visibleItems() []list.Item {
+	if !m.itemFilter.active() && m.itemKind == showAll {
+		return m.currentItems
+	}
+	var items []list.Item
+	for _, it := range m.currentItems {
+		if i, ok := it.(item); ok && m.itemKind.match(i) && m.itemFilter.match(i) {
+			items = append(items, it)
+		}
+	}
+	return items
+}
+
+// applyItemFilter parses and applies a filter typed in the prompt.
+func (m *Model) applyItemFilter(value string) tea.Cmd {
+	f, err := parseItemFilter(value)
+	if err != nil {
+		m.setErrorStatus(err.Error())
+		return nil
+	}
+	m.itemFilter = f
+	m.updateTitle()
+	// With a fuzzy filter applied, the list filters the new items again in the background.
+	filterCmd := m.list.SetItems(m.visibleItems())
+	status := fmt.Sprintf("Showing %d of %d loaded items", len(m.list.Items()), len(m.currentItems))
+	if !f.active() {
+		status = fmt.Sprintf("Filter cleared, showing %d items", len(m.currentItems))
+	}
+	return tea.Batch(filterCmd, m.setStatus(status))
+}
+
+// cycleItemKind switches between showing all items, only directories and only files.
+func (m *Model) cycleItemKind() tea.Cmd {
+	m.itemKind = m.itemKind.next()
+	m.list.SetStatusBarItemName(m.itemKind.itemNames())
+	m.updateTitle()
+	// With a fuzzy filter applied, the list filters the new items again in the background.
+	filterCmd := m.list.SetItems(m.visibleItems())
+	status := fmt.Sprintf("Showing %s only, %d of %d loaded items", m.itemKind, len(m.list.Items()), len(m.currentItems))
+	if m.itemKind == showAll {
+		status = fmt.Sprintf("Showing all %d loaded items", len(m.currentItems))
+	}
+	return tea.Batch(filterCmd, m.setStatus(status))
+}
@@ -0,0 +1,164 @@
+// ABOUTME: Tests for the glob, minimum-size and item-kind filters in itemfilter.go.
+// ABOUTME: Covers parsing filter text, matching items, clearing the filter and showing only directories or files.
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseItemFilter(t *testing.T) {
+	f, err := parseItemFilter("*.log >10MB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.pattern != "*.log" || f.minSize != 10_000_000 {
+		t.Errorf("unexpected filter %+v", f)
+	}
+
+	for _, bad := range []string{">lots", "[", "*.log *.txt"} {
+		if _, err := parseItemFilter(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+	if f, _ := parseItemFilter("  "); f.active() {
+		t.Errorf("expected blank input to clear the filter")
+	}
+}
+
+func TestItemFilterStringParsesBack(t *testing.T) {
+	for _, text := range []string{"*.log", ">10MB", "*.log >10MB", "*.gz >1.5GiB", ">1234567"} {
+		f, err := parseItemFilter(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := parseItemFilter(f.String())
+		if err != nil {
+			t.Errorf("parseItemFilter(%q) = %v, want the String of %q to parse", f.String(), err, text)
+			continue
+		}
+		if again != f {
+			t.Errorf("%q parsed back as %+v, want %+v", f.String(), again, f)
+		}
+	}
+	if got := (itemFilter{pattern: "*.log", minSize: 100}).String(); got != "*.log >100" {
+		t.Errorf("String() = %q, want the size in bytes", got)
+	}
+}
+
+func TestItemFilterMatch(t *testing.T) {
+	f := itemFilter{pattern: "*.log", minSize: 100}
+	tests := []struct {
+		item item
+		want bool
+	}{
+		{item{displayKey: "app.log", size: 200}, true},
+		{item{displayKey: "app.log", size: 50}, false},
+		{item{displayKey: "app.txt", size: 200}, false},
+		{item{displayKey: "logs.log", isDir: true}, false},
+	}
+	for _, tt := range tests {
+		if got := f.match(tt.item); got != tt.want {
+			t.Errorf("match(%+v) = %v, want %v", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestApplyingAndClearingItemFilter(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.currentItems = []list.Item{
+		item{key: "a.log", displayKey: "a.log"},
+		item{key: "b.txt", displayKey: "b.txt"},
+	}
+	m.list.SetItems(m.currentItems)
+
+	m.applyItemFilter("*.log")
+	if n := len(m.list.Items()); n != 1 {
+		t.Fatalf("expected 1 item after filtering, got %d", n)
+	}
+	if m.list.Title != "test-bucket [filter: *.log]" {
+		t.Errorf("title = %q, want the filter shown", m.list.Title)
+	}
+
+	m.applyItemFilter("")
+	if n := len(m.list.Items()); n != 2 {
+		t.Errorf("expected the full list after clearing, got %d items", n)
+	}
+}
+
+func TestItemFilterRefiltersAFuzzyFilter(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "a.log", displayKey: "a.log"},
+		item{key: "b.log", displayKey: "b.log"},
+		item{key: "b.txt", displayKey: "b.txt"},
+	}})
+	m = updated.(Model)
+	m.list, _ = m.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b")})
+	m.list, _ = m.list.Update(filterMatches(t, cmd))
+	m.list, _ = m.list.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m.list, _ = m.list.Update(filterMatches(t, m.applyItemFilter("*.log")))
+	if n := len(m.list.VisibleItems()); n != 1 {
+		t.Errorf("expected only b.log to match both filters, got %d items", n)
+	}
+}
+
+func TestItemKindShowsDirectoriesOrFiles(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, _ := m.Update(itemsLoadedMsg{items: mixedItems()})
+	m = updated.(Model)
+
+	tests := []struct {
+		kind       itemKind
+		want       []string
+		wantTitle  string
+		wantStatus string
+	}{
+		{showDirs, []string{"m/", "z/"}, "test-bucket [directories only]", "Showing directories only, 2 of 5 loaded items"},
+		{showFiles, []string{"a.txt", "b.txt", "c.txt"}, "test-bucket [files only]", "Showing files only, 3 of 5 loaded items"},
+		{showAll, []string{"m/", "z/", "a.txt", "b.txt", "c.txt"}, "test-bucket", "Showing all 5 loaded items"},
+	}
+	for _, tt := range tests {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'z'}})
+		m = updated.(Model)
+		if m.itemKind != tt.kind {
+			t.Fatalf("kind = %s, want %s", m.itemKind, tt.kind)
+		}
+		if got := keysOf(m.list.Items()); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: visible items = %v, want %v", tt.kind, got, tt.want)
+		}
+		if m.list.Title != tt.wantTitle || m.statusMsg != tt.wantStatus {
+			t.Errorf("%s: title %q, status %q", tt.kind, m.list.Title, m.statusMsg)
+		}
+	}
+}
+
+func TestItemKindComposesWithItemFilter(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.currentItems = []list.Item{
+		item{key: "logs/", displayKey: "logs", isDir: true},
+		item{key: "logs.txt", displayKey: "logs.txt", size: 200},
+		item{key: "data/", displayKey: "data", isDir: true},
+		item{key: "app.log", displayKey: "app.log", size: 50},
+	}
+	m.itemKind = showFiles
+	m.applyItemFilter(">100B")
+	if got := keysOf(m.list.Items()); !reflect.DeepEqual(got, []string{"logs.txt"}) {
+		t.Errorf("expected files over 100 B, got %v", got)
+	}
+
+	m.itemKind = showDirs
+	m.applyItemFilter("lo*")
+	if got := keysOf(m.list.Items()); !reflect.DeepEqual(got, []string{"logs/"}) {
+		t.Errorf("expected directories matching lo*, got %v", got)
+	}
+}
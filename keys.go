@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// s3URI renders bucket/key as an s3:// URI for display. The key is shown as stored, spaces,
+// "+", "%" and non-ASCII included; only control characters are escaped so a key cannot
+// break the terminal layout. It is not escaped for requests: use copySource for that.
+func s3URI(bucket, key string) string {
+	var b strings.Builder
+	b.WriteString("s3://" + bucket + "/")
+	for _, r := range key {
+		if strconv.IsPrint(r) || r == ' ' {
+			b.WriteRune(r)
+			continue
+		}
+		quoted := strconv.QuoteRune(r)
+		b.WriteString(quoted[1 : len(quoted)-1])
+	}
+	return b.String()
+}
+
+// parseLocation reads the bucket argument, either a bare bucket name or an s3:// URI such
+// as s3://my-bucket/path/to/dir/. The prefix is returned as written, without adding a
+// trailing slash. The URI is not decoded, since keys may hold "%", "?" and "#" as is.
+func parseLocation(arg string) (bucket, prefix string, err error) {
+	scheme, rest, ok := strings.Cut(arg, "://")
+	if !ok {
+		if strings.Contains(arg, "/") {
+			return "", "", fmt.Errorf("invalid bucket name %q: to start in a prefix, use s3://bucket/prefix", arg)
+		}
+		return arg, "", nil
+	}
+	if !strings.EqualFold(scheme, "s3") {
+		return "", "", fmt.Errorf("invalid location %q: only s3:// URIs are supported", arg)
+	}
+	bucket, prefix, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("invalid location %q: no bucket name, expected s3://bucket/prefix", arg)
+	}
+	return bucket, prefix, nil
+}
+
+// copySource is the URL-encoded "bucket/key" CopyObject expects, keeping the slashes.
+// S3 decodes "+" in the header as a space, so it is escaped too.
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(s), "+", "%2B")
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
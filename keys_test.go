@@ -0,0 +1,78 @@
+// ABOUTME: Tests for rendering, escaping and parsing object keys and locations in keys.go.
+// ABOUTME: Covers s3:// URIs for display and as the bucket argument, and CopySource escaping of spaces, %, + and non-ASCII keys.
+package main
+
+import "testing"
+
+func TestS3URI(t *testing.T) {
+	tests := []struct {
+		key, want string
+	}{
+		{"dir/a file.txt", "s3://b/dir/a file.txt"},
+		{"dir/a+b 100%.txt", "s3://b/dir/a+b 100%.txt"},
+		{"dir/résumé ü.pdf", "s3://b/dir/résumé ü.pdf"},
+		{"dir/", "s3://b/dir/"},
+		{"", "s3://b/"},
+		{"bad\nname\t.txt", `s3://b/bad\nname\t.txt`},
+	}
+	for _, tt := range tests {
+		if got := s3URI("b", tt.key); got != tt.want {
+			t.Errorf("s3URI(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCopySource(t *testing.T) {
+	tests := []struct {
+		key, want string
+	}{
+		{"dir/a file+1.txt", "b/dir/a%20file%2B1.txt"},
+		{"dir/100%.txt", "b/dir/100%25.txt"},
+		{"dir/résumé.pdf", "b/dir/r%C3%A9sum%C3%A9.pdf"},
+		{"a/b/c", "b/a/b/c"},
+		{"q?x=1&y#z", "b/q%3Fx=1&y%23z"},
+	}
+	for _, tt := range tests {
+		if got := copySource("b", tt.key); got != tt.want {
+			t.Errorf("copySource(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		arg, bucket, prefix string
+	}{
+		{"my-bucket", "my-bucket", ""},
+		{"s3://my-bucket", "my-bucket", ""},
+		{"s3://my-bucket/", "my-bucket", ""},
+		{"s3://my-bucket/logs", "my-bucket", "logs"},
+		{"s3://my-bucket/path/to/dir/", "my-bucket", "path/to/dir/"},
+		{"S3://my-bucket/a b/100%/#1?/", "my-bucket", "a b/100%/#1?/"},
+	}
+	for _, tt := range tests {
+		bucket, prefix, err := parseLocation(tt.arg)
+		if err != nil || bucket != tt.bucket || prefix != tt.prefix {
+			t.Errorf("parseLocation(%q) = %q, %q, %v, want %q, %q", tt.arg, bucket, prefix, err, tt.bucket, tt.prefix)
+		}
+	}
+
+	for _, bad := range []string{"s3://", "s3:///logs/", "https://my-bucket/logs/", "gs://my-bucket", "my-bucket/logs/"} {
+		if _, _, err := parseLocation(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestStartInPrefix(t *testing.T) {
+	for prefix, want := range map[string]string{"": "", "path/to/dir/": "path/to/dir/", "logs": "logs/", "/logs//": "logs/"} {
+		m := initialModel("my-bucket", options{})
+		m.startIn(prefix)
+		if m.currentPrefix != want {
+			t.Errorf("startIn(%q) lists %q, want %q", prefix, m.currentPrefix, want)
+		}
+		if wantTitle := "my-bucket/" + want; want != "" && m.list.Title != wantTitle {
+			t.Errorf("title = %q, want %q", m.list.Title, wantTitle)
+		}
+	}
+}
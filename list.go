@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Exit codes of --list.
+const (
+	listExitOK    = 0
+	listExitError = 1
+)
+
+// runList prints every key under prefix, at any depth, one per line to stdout without
+// starting the TUI; with long set each line also has the size in bytes and the
+// modification time. It returns the process exit code.
+func runList(ctx context.Context, client S3API, bucket, prefix string, long bool, stdout, stderr io.Writer) int {
+	w := bufio.NewWriter(stdout)
+	err := walkObjects(ctx, client, bucket, prefix, 0, func(obj types.Object) error {
+		_, err := fmt.Fprintln(w, formatListLine(obj, long))
+		return err
+	})
+	if flushErr := w.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return listExitError
+	}
+	return listExitOK
+}
+
+// formatListLine renders obj for --list: the key alone, or in long mode the size
+// right-aligned, the UTC modification time and the key, in columns.
+func formatListLine(obj types.Object, long bool) string {
+	key := aws.StringValue(obj.Key)
+	if !long {
+		return key
+	}
+	modified := aws.TimeValue(obj.LastModified).UTC().Format("2006-01-02 15:04:05")
+	return fmt.Sprintf("%12d  %s  %s", aws.Int64Value(obj.Size), modified, key)
+}
@@ -0,0 +1,53 @@
+// ABOUTME: Tests for the non-interactive --list mode in list.go.
+// ABOUTME: Covers short and long output formatting and listing every page.
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestFormatListLine(t *testing.T) {
+	obj := types.Object{
+		Key:          aws.String("logs/2024/app.log"),
+		Size:         aws.Int64(1536),
+		LastModified: aws.Time(time.Date(2024, 3, 5, 14, 7, 9, 0, time.FixedZone("CET", 3600))),
+	}
+	if got := formatListLine(obj, false); got != "logs/2024/app.log" {
+		t.Errorf("short = %q", got)
+	}
+	if got, want := formatListLine(obj, true), "        1536  2024-03-05 13:07:09  logs/2024/app.log"; got != want {
+		t.Errorf("long = %q, want %q", got, want)
+	}
+}
+
+func TestRunListPrintsAllPages(t *testing.T) {
+	client := newTestS3Client(t, listingServer(t, 3))
+	var stdout, stderr bytes.Buffer
+	if code := runList(context.Background(), client, "b", "a/", false, &stdout, &stderr); code != listExitOK {
+		t.Fatalf("exit code %d, stderr %q", code, stderr.String())
+	}
+	if got := stdout.String(); got != "a/0\na/1\na/2\n" {
+		t.Errorf("stdout = %q", got)
+	}
+}
+
+func TestRunListFailure(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+	})
+	if code := runList(context.Background(), client, "b", "", true, &stdout, &stderr); code != listExitError {
+		t.Errorf("exit code %d, want %d", code, listExitError)
+	}
+	if stdout.Len() != 0 || !bytes.Contains(stderr.Bytes(), []byte("AccessDenied")) {
+		t.Errorf("stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+}
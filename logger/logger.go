@@ -1,32 +1,85 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"log"
-	"os"
+	"strings"
 )
 
+// Level is the severity of a log message; messages below the configured level are dropped.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel accepts debug, info, warn or error in any case.
+func ParseLevel(s string) (Level, error) {
+	for l, name := range levelNames {
+		if strings.EqualFold(s, name) {
+			return l, nil
+		}
+	}
+	if strings.EqualFold(s, "warning") {
+		return LevelWarn, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q, use debug, info, warn or error", s)
+}
+
 type logger struct {
 	enabled bool
+	level   Level
 	log     *log.Logger
 }
 
 var (
-	defaultLogger = &logger{enabled: false, log: log.Default()}
+	// A private logger keeps debug output away from the standard logger, whose default
+	// stderr destination would corrupt the TUI.
+	defaultLogger = &logger{enabled: false, level: LevelInfo, log: log.New(io.Discard, "", log.LstdFlags)}
 )
 
-func Initialize(file *os.File) {
-	defaultLogger.log.SetOutput(file)
-
+// Initialize starts writing messages at or above level to w.
+func Initialize(w io.Writer, level Level) {
+	defaultLogger.log.SetOutput(w)
+	defaultLogger.level = level
 	defaultLogger.enabled = true
 }
+
+// Enabled reports whether Initialize has been called.
+func Enabled() bool {
+	return defaultLogger.enabled
+}
+
+// Println logs at debug level, like Debugf with the operands formatted as by fmt.Sprintln.
 func Println(msg ...interface{}) {
-	if defaultLogger.enabled {
-		defaultLogger.log.Println(msg...)
-	}
+	logf(LevelDebug, "%s", strings.TrimSuffix(fmt.Sprintln(msg...), "\n"))
 }
 
-func Printf(format string, msg ...interface{}) {
-	if defaultLogger.enabled {
-		defaultLogger.log.Printf(format, msg...)
+// Printf logs at debug level; it is Debugf under its older name.
+func Printf(format string, msg ...interface{}) { logf(LevelDebug, format, msg...) }
+
+func Debugf(format string, msg ...interface{}) { logf(LevelDebug, format, msg...) }
+func Infof(format string, msg ...interface{})  { logf(LevelInfo, format, msg...) }
+func Warnf(format string, msg ...interface{})  { logf(LevelWarn, format, msg...) }
+func Errorf(format string, msg ...interface{}) { logf(LevelError, format, msg...) }
+
+func logf(level Level, format string, msg ...interface{}) {
+	if defaultLogger.enabled && level >= defaultLogger.level {
+		defaultLogger.log.Printf(level.String()+" "+format, msg...)
 	}
 }
@@ -0,0 +1,83 @@
+// ABOUTME: Tests for the leveled logger.
+// ABOUTME: Covers dropping messages below the threshold, level names and the debug-level Println/Printf.
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// capture initializes the logger at level for the rest of the test and returns what it writes.
+func capture(t *testing.T, level Level) *bytes.Buffer {
+	t.Helper()
+	saved := *defaultLogger
+	t.Cleanup(func() {
+		defaultLogger.log.SetOutput(saved.log.Writer())
+		defaultLogger.level, defaultLogger.enabled = saved.level, saved.enabled
+	})
+	var buf bytes.Buffer
+	Initialize(&buf, level)
+	return &buf
+}
+
+func TestMessagesBelowLevelAreSuppressed(t *testing.T) {
+	buf := capture(t, LevelWarn)
+
+	Debugf("debug %d", 1)
+	Infof("info %d", 2)
+	Warnf("warn %d", 3)
+	Errorf("error %d", 4)
+	Println("println", 5)
+	Printf("printf %d", 6)
+
+	out := buf.String()
+	for _, dropped := range []string{"debug 1", "info 2", "println 5", "printf 6"} {
+		if strings.Contains(out, dropped) {
+			t.Errorf("expected %q to be suppressed at warn, got:\n%s", dropped, out)
+		}
+	}
+	for _, kept := range []string{"WARN warn 3", "ERROR error 4"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("expected %q to be logged at warn, got:\n%s", kept, out)
+		}
+	}
+}
+
+func TestPrintlnLogsAtDebug(t *testing.T) {
+	buf := capture(t, LevelDebug)
+
+	Println("msg", 1)
+	Printf("value %s", "x")
+
+	out := buf.String()
+	if !strings.Contains(out, "DEBUG msg 1\n") || !strings.Contains(out, "DEBUG value x\n") {
+		t.Errorf("expected both at debug level, got:\n%s", out)
+	}
+}
+
+func TestNothingIsWrittenBeforeInitialize(t *testing.T) {
+	if Enabled() {
+		t.Skip("logger already initialized")
+	}
+	var buf bytes.Buffer
+	saved := defaultLogger.log.Writer()
+	defaultLogger.log.SetOutput(&buf)
+	t.Cleanup(func() { defaultLogger.log.SetOutput(saved) })
+
+	Errorf("boom")
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing before Initialize, got %q", buf.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "Warning": LevelWarn, "error": LevelError} {
+		if got, err := ParseLevel(s); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Errorf("expected an unknown level to be rejected")
+	}
+}
@@ -2,19 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -22,18 +28,35 @@ import (
 	"github.com/mtyurt/s3n/logger"
 )
 
-const PAGE_SIZE = 100
+const (
+	// defaultPageSize is how many keys a listing page asks for unless --page-size is given.
+	defaultPageSize = 100
+	// maxPageSize is the most keys S3 returns from one ListObjectsV2 call.
+	maxPageSize = 1000
+)
 
 var (
 	helpStyleKey = lipgloss.NewStyle().Foreground(lipgloss.Color("#9B9BCC")).Bold(true)
 	helpStyleVal = lipgloss.NewStyle().Foreground(lipgloss.Color("#9B9B9B"))
+
+	errorPanelStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("196")).
+			Padding(0, 1)
+	errorTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
 )
 
 type Model struct {
-	list            list.Model
-	help            help.Model
-	keys            keyMap
-	client          *s3.Client
+	list    list.Model
+	help    help.Model
+	keys    keyMap
+	spinner spinner.Model
+	// progressBar draws the progress of uploads and downloads.
+	progressBar progress.Model
+	client      S3API
+	// ctx lives as long as the program; quitting cancels it and every request made with it.
+	ctx             context.Context
+	cancel          context.CancelFunc
 	bucketName      string
 	currentPrefix   string
 	editFileStatus  string
@@ -49,10 +72,63 @@ type Model struct {
 	newFileName     string
 	newFileInput    *textinput.Model
 	confirmDelete   bool
-	deleteKey       string
-	searchTerm      string
-	loadingMore     bool
-	errMsg          string
+	editConflict    *editConflictMsg
+	confirmUpload   *EditFinishedMsg // a changed edit waiting to be confirmed
+	binaryPrompt    *binaryPrompt
+	overCap         *overCapMsg // an operation stopped at --max-items, waiting to go on
+	tagForm         *tagForm
+	breadcrumbs     *breadcrumbs
+	// restoreKey is the object the restore prompt is for.
+	restoreKey string
+	// copyItem is the object the copy or move prompt is for.
+	copyItem            item
+	restoreStorageClass string
+	deleteKey           string
+	deleteDir           bool
+	deleteSelected      bool // the delete prompt is for the selected objects
+	startupProblem      *startupProblem
+	listed              bool // a listing of a bucket has been shown
+	searchTerm          string
+	loadingMore         bool
+	errMsg              string
+	errHint             string
+	errRetry            tea.Cmd
+	shownBucket         string
+	shownPrefix         string
+	shownSearchTerm     string
+	statusIsError       bool
+	statusID            int
+	opts                options
+	viewer              *ViewModel
+	prompt              promptKind
+	promptInput         textinput.Model
+	picker              list.Model
+	pickerKind          pickerKind
+	history             navHistory
+	historyPending      bool
+	historyTarget       int
+	selectKey           string
+	selectIndex         int // fallback cursor position when selectKey is gone after a reload
+	relativeTime        bool
+	itemFilter          itemFilter
+	itemKind            itemKind
+	sortMode            sortMode
+	split               *splitView
+	cache               *listingCache
+	contentTypes        *contentTypeCache
+	prefetch            *prefetchedPage
+	// changes marks what the last reload of the shown listing added or modified, until changesFadedMsg.
+	changes     map[string]change
+	changesID   int
+	streaming   bool // with --all-pages, the next page is being listed to append
+	operation   *operation
+	operationID int
+	region      string
+	pageSize    int // keys asked for per listing page
+	// recentsFile is where visited locations are remembered; "" remembers nothing.
+	recentsFile string
+	// selected holds the keys of the objects marked for a batch delete or download.
+	selected map[string]bool
 }
 
 type item struct {
@@ -62,15 +138,44 @@ type item struct {
 	size        int64
 	modified    time.Time
 	isDir       bool
+	// storageClass is empty when the listing did not report one; restoring and
+	// restoredUntil describe a restore of an archived object.
+	storageClass  string
+	restoring     bool
+	restoredUntil time.Time
+	// relativeTime shows modified as "3 hours ago" instead of a timestamp.
+	relativeTime bool
+	// versionID picks a version other than the latest when viewing the object.
+	versionID string
+	// etag is the listed ETag, used to tell when a cached content type is stale.
+	etag string
+	// marked is set for objects selected for a batch delete or download.
+	marked bool
+	// count is the peeked number of children of a directory, nil until peeked.
+	count *dirCount
+	// change marks an item added or modified since the listing shown before a reload.
+	change change
 }
 
 func (i item) Title() string {
 	if i.displayKey == "" {
 		return "" // Don't show empty items
 	}
+	if i.marked {
+		return "✅ " + i.displayKey
+	}
+	switch i.change {
+	case added:
+		return "🟢 " + i.displayKey
+	case modified:
+		return "🟡 " + i.displayKey
+	}
 	if i.isDir {
 		return "📁 " + i.displayKey
 	}
+	if i.archived() {
+		return "🧊 " + i.displayKey
+	}
 	return "📄 " + i.displayKey
 }
 
@@ -79,37 +184,127 @@ func (i item) Description() string {
 		return "" // Don't show description for empty items
 	}
 	if i.isDir {
+		if i.count != nil {
+			return fmt.Sprintf("Directory (%s)", i.count)
+		}
 		return "Directory"
 	}
-	d := fmt.Sprintf("%s, Modified: %s", humanize.Bytes(uint64(i.size)), i.modified.Format("2006-01-02 15:04:05"))
+	d := fmt.Sprintf("%s, Modified: %s", humanize.Bytes(uint64(i.size)), formatTime(i.modified, i.relativeTime))
 	if i.contentType != "" {
 		d += fmt.Sprintf(", Content-Type: %s", i.contentType)
 	}
+	if s := i.storageDescription(); s != "" {
+		d += ", " + s
+	}
 	return d
 }
 
 func (i item) FilterValue() string {
-	return i.key
+	return i.displayKey
+}
+
+// listingSummary counts the directories and objects in items and totals the object sizes,
+// e.g. "2 directories, 42 objects, 1.3 GB". The total of a partial listing is a lower bound.
+func listingSummary(items []list.Item, partial bool) string {
+	var dirs, objects int
+	var total uint64
+	for _, it := range items {
+		i := it.(item)
+		if i.isDir {
+			dirs++
+			continue
+		}
+		objects++
+		total += uint64(i.size)
+	}
+	var parts []string
+	if dirs > 0 {
+		parts = append(parts, plural(dirs, "directory", "directories"))
+	}
+	if objects > 0 {
+		size := humanize.Bytes(total)
+		if partial {
+			size = "≥" + size
+		}
+		parts = append(parts, plural(objects, "object", "objects"), size)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
+
+// formatTime renders t as a timestamp, or relative to now ("3 hours ago") when relative is set.
+func formatTime(t time.Time, relative bool) string {
+	if relative {
+		return humanize.Time(t)
+	}
+	return t.Format("2006-01-02 15:04:05")
 }
 
 type keyMap struct {
-	Enter    key.Binding
-	Back     key.Binding
-	Edit     key.Binding
-	Quit     key.Binding
-	Reload   key.Binding
-	Add      key.Binding
-	Delete   key.Binding
-	Search   key.Binding
-	NextPage key.Binding
-}
-
-func newKeyMap() keyMap {
+	Enter       key.Binding
+	ViewBuiltin key.Binding
+	Back        key.Binding
+	Edit        key.Binding
+	Quit        key.Binding
+	Reload      key.Binding
+	Add         key.Binding
+	Delete      key.Binding
+	Search      key.Binding
+	NextPage    key.Binding
+	Dismiss     key.Binding
+	Retry       key.Binding
+	AddBookmark key.Binding
+	Bookmarks   key.Binding
+	HistoryBack key.Binding
+	HistoryFwd  key.Binding
+	CopyContent key.Binding
+	Reveal      key.Binding
+	Buckets     key.Binding
+	ToggleTime  key.Binding
+	ContentType key.Binding
+	Sort        key.Binding
+	GoTo        key.Binding
+	Restore     key.Binding
+	Info        key.Binding
+	EditTags    key.Binding
+	Find        key.Binding
+	NewFolder   key.Binding
+	Copy        key.Binding
+	Move        key.Binding
+	Versions    key.Binding
+	ItemFilter  key.Binding
+	ItemKind    key.Binding
+	SplitView   key.Binding
+	Upload      key.Binding
+	Breadcrumbs key.Binding
+	Recents     key.Binding
+	Select      key.Binding
+	Download    key.Binding
+	CopyKey     key.Binding
+	CopyURI     key.Binding
+	Version     key.Binding
+	OpenSystem  key.Binding
+	Console     key.Binding
+	Count       key.Binding
+	Summary     key.Binding
+}
+
+func defaultKeyMap() keyMap {
 	return keyMap{
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("enter", "enter dir/view file"),
 		),
+		ViewBuiltin: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "view file in built-in viewer"),
+		),
 		Back: key.NewBinding(
 			key.WithKeys("backspace", "h"),
 			key.WithHelp("backspace/h", "go back"),
@@ -142,6 +337,150 @@ func newKeyMap() keyMap {
 			key.WithKeys("n"),
 			key.WithHelp("n", "load next page"),
 		),
+		Dismiss: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "dismiss error"),
+		),
+		Retry: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "retry"),
+		),
+		AddBookmark: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "bookmark prefix"),
+		),
+		Bookmarks: key.NewBinding(
+			key.WithKeys("'"),
+			key.WithHelp("'", "bookmarks"),
+		),
+		HistoryBack: key.NewBinding(
+			key.WithKeys("alt+left", "["),
+			key.WithHelp("alt+←/[", "history back"),
+		),
+		HistoryFwd: key.NewBinding(
+			key.WithKeys("alt+right", "]"),
+			key.WithHelp("alt+→/]", "history forward"),
+		),
+		CopyContent: key.NewBinding(
+			key.WithKeys("ctrl+y"),
+			key.WithHelp("ctrl+y", "copy file content"),
+		),
+		Reveal: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open containing folder"),
+		),
+		Buckets: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "switch bucket"),
+		),
+		ToggleTime: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "toggle relative times"),
+		),
+		ContentType: key.NewBinding(
+			key.WithKeys("ctrl+t"),
+			key.WithHelp("ctrl+t", "toggle content types"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "cycle sort order"),
+		),
+		ItemKind: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "show all/dirs/files"),
+		),
+		GoTo: key.NewBinding(
+			key.WithKeys("ctrl+g"),
+			key.WithHelp("ctrl+g", "go to prefix"),
+		),
+		Restore: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "restore from archive"),
+		),
+		Info: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "object info and tags"),
+		),
+		EditTags: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "edit tags"),
+		),
+		Find: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "find under prefix"),
+		),
+		NewFolder: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "new folder"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy object"),
+		),
+		Move: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "move/rename object"),
+		),
+		Versions: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "object versions"),
+		),
+		ItemFilter: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "filter by glob/min size"),
+		),
+		SplitView: key.NewBinding(
+			key.WithKeys("|"),
+			key.WithHelp("|", "split view"),
+		),
+		Upload: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "upload a local file"),
+		),
+		Breadcrumbs: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "jump to a parent level"),
+		),
+		Recents: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "recent locations"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select"),
+		),
+		Download: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "download selected"),
+		),
+		CopyKey: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "copy key"),
+		),
+		CopyURI: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", "copy s3:// URI"),
+		),
+		Version: key.NewBinding(
+			key.WithKeys("ctrl+o"),
+			key.WithHelp("ctrl+o", "show version"),
+		),
+		OpenSystem: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "open in default app"),
+		),
+		Console: key.NewBinding(
+			key.WithKeys("W"),
+			key.WithHelp("W", "open in AWS Console"),
+		),
+		Count: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "count items in dir"),
+		),
+		Summary: key.NewBinding(
+			key.WithKeys("$"),
+			key.WithHelp("$", "size summary"),
+		),
 	}
 }
 
@@ -149,6 +488,7 @@ func shortHelpKeys(keys keyMap) func() []key.Binding {
 	return func() []key.Binding {
 		return []key.Binding{
 			keys.Enter,
+			keys.ViewBuiltin,
 			keys.Back,
 			keys.Edit,
 			keys.Reload,
@@ -156,14 +496,54 @@ func shortHelpKeys(keys keyMap) func() []key.Binding {
 			keys.Delete,
 			keys.Search,
 			keys.NextPage,
+			keys.AddBookmark,
+			keys.Bookmarks,
+			keys.HistoryBack,
+			keys.HistoryFwd,
+			keys.CopyContent,
+			keys.Reveal,
+			keys.Buckets,
+			keys.ToggleTime,
+			keys.ContentType,
+			keys.Sort,
+			keys.GoTo,
+			keys.Restore,
+			keys.Info,
+			keys.EditTags,
+			keys.Find,
+			keys.NewFolder,
+			keys.Copy,
+			keys.Move,
+			keys.Versions,
+			keys.ItemFilter,
+			keys.ItemKind,
+			keys.SplitView,
+			keys.Upload,
+			keys.Breadcrumbs,
+			keys.Recents,
+			keys.Select,
+			keys.Download,
+			keys.CopyKey,
+			keys.CopyURI,
+			keys.OpenSystem,
+			keys.Console,
+			keys.Count,
+			keys.Summary,
+			keys.Version,
 			keys.Quit,
 		}
 
 	}
 }
 
-func initialModel(bucketName string) Model {
-	keys := newKeyMap()
+func initialModel(bucketName string, opts options) Model {
+	// main refuses to start with a broken key config; report it here too in case it did not.
+	keys, keyErr := newKeyMap(opts.keyOverrides)
+	// LocalStack and other S3-compatible endpoints have no AWS Console to open.
+	keys.Console.SetEnabled(opts.endpoint == "")
+	if opts.readOnly {
+		disableMutations(&keys)
+	}
 
 	delegate := list.NewDefaultDelegate()
 	delegate.ShowDescription = true
@@ -177,8 +557,9 @@ func initialModel(bucketName string) Model {
 	l.Title = fmt.Sprintf("%s", bucketName)
 	l.SetShowHelp(true)
 	l.AdditionalFullHelpKeys = shortHelpKeys(keys)
+	l.Filter = fuzzyFilter
 
-	// Only ctrl+c exits (handled in Update); escape stays free to cancel filtering.
+	// Only the quit key exits (handled in Update); escape stays free to cancel filtering.
 	l.DisableQuitKeybindings()
 
 	// Optionally customize the list styles
@@ -193,36 +574,97 @@ func initialModel(bucketName string) Model {
 	l.Styles.FilterCursor = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("205"))
 
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+
+	client, err := newS3Client(opts)
 	if err != nil {
 		panic(err)
 	}
 
-	var client *s3.Client
-	if os.Getenv("LOCAL_AWS") != "" {
-		client = s3.NewFromConfig(cfg, func(o *s3.Options) {
-			o.BaseEndpoint = aws.String("http://localhost:4566/")
-			o.UsePathStyle = true
-		})
-	} else {
-		client = s3.NewFromConfig(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	m := Model{
+		ctx:          ctx,
+		cancel:       cancel,
+		list:         l,
+		help:         help.New(),
+		keys:         keys,
+		spinner:      s,
+		progressBar:  progress.New(progress.WithDefaultGradient(), progress.WithWidth(30)),
+		loading:      true,
+		client:       withReadOnly(withDownloadLimit(withEncryption(withRequestPayer(client, opts), opts), opts), opts),
+		bucketName:   bucketName,
+		shownBucket:  bucketName,
+		opts:         opts,
+		cache:        newListingCache(),
+		contentTypes: newContentTypeCache(contentTypeCacheSize),
+		region:       client.Options().Region,
+		pageSize:     opts.pageSize,
 	}
-
-	return Model{
-		list:       l,
-		help:       help.New(),
-		keys:       keys,
-		loading:    true,
-		client:     client,
-		bucketName: bucketName,
+	if m.pageSize == 0 {
+		m.pageSize = defaultPageSize
 	}
+	m.updateTitle()
+	if keyErr != nil {
+		m.setErrorStatus("Key config: " + keyErr.Error())
+	}
+	return m
 }
 
-type statusMessageTimeoutMsg struct{}
+// statusMessageTimeout is how long informational status messages stay visible.
+const statusMessageTimeout = 3 * time.Second
+
+// statusMessageTimeoutMsg clears the status message it was scheduled for;
+// a newer message bumps statusID so stale ticks are ignored.
+type statusMessageTimeoutMsg struct {
+	id int
+}
 type viewExit struct{}
 
+// setStatus shows an informational message that clears itself after statusMessageTimeout.
+func (m *Model) setStatus(msg string) tea.Cmd {
+	m.statusMsg = msg
+	m.showStatusMsg = true
+	m.statusIsError = false
+	m.statusID++
+	id := m.statusID
+	return tea.Tick(statusMessageTimeout, func(time.Time) tea.Msg {
+		return statusMessageTimeoutMsg{id: id}
+	})
+}
+
+// setErrorStatus shows a message that stays until the next key press.
+func (m *Model) setErrorStatus(msg string) {
+	m.statusMsg = msg
+	m.showStatusMsg = true
+	m.statusIsError = true
+	m.statusID++
+}
+
+func (m *Model) clearStatus() {
+	m.statusMsg = ""
+	m.showStatusMsg = false
+	m.statusIsError = false
+}
+
 var docStyle = lipgloss.NewStyle().Margin(1, 2)
 
+// errorMsg reports a failed operation; retry, when set, re-runs it from the error panel.
+type errorMsg struct {
+	err   error
+	retry tea.Cmd
+}
+
+func (e errorMsg) Error() string { return e.err.Error() }
+func (e errorMsg) Unwrap() error { return e.err }
+
+// reloadMsg asks Update to list prefix (with searchTerm) again from scratch.
+type reloadMsg struct {
+	prefix     string
+	searchTerm string
+}
+
 // Add this message type at the top level
 type itemsLoadedMsg struct {
 	items     []list.Item
@@ -231,26 +673,69 @@ type itemsLoadedMsg struct {
 }
 
 func (m Model) loadItems() tea.Msg {
+	return m.listItems(m.ctx)
+}
+
+// listItems fetches the page of the current location that starts at nextPageToken.
+func (m Model) listItems(ctx context.Context) tea.Msg {
+	cacheKey := m.currentListing()
+	if m.nextPageToken == nil {
+		if msg, ok := m.cache.get(cacheKey); ok {
+			logger.Debugf("ListObjectsV2 %s served from cache", s3URI(m.bucketName, m.currentPrefix+m.searchTerm))
+			return msg
+		}
+	}
+
 	queryPrefix := m.currentPrefix + m.searchTerm
 	input := &s3.ListObjectsV2Input{
 		Bucket:            &m.bucketName,
 		Prefix:            &queryPrefix,
-		MaxKeys:           aws.Int32(PAGE_SIZE),
+		MaxKeys:           aws.Int32(int32(m.pageSize)),
 		ContinuationToken: m.nextPageToken,
 		Delimiter:         aws.String("/"),
+		// Restore status is only listed when asked for.
+		OptionalObjectAttributes: []types.OptionalObjectAttributes{types.OptionalObjectAttributesRestoreStatus},
 	}
 
-	output, err := m.client.ListObjectsV2(context.TODO(), input)
+	logger.Debugf("ListObjectsV2 %s (continuation: %v)", s3URI(m.bucketName, queryPrefix), m.nextPageToken != nil)
+	output, err := m.client.ListObjectsV2(ctx, input)
 	if err != nil {
-		return err
+		prefix, searchTerm := m.currentPrefix, m.searchTerm
+		return errorMsg{err: err, retry: func() tea.Msg {
+			return reloadMsg{prefix: prefix, searchTerm: searchTerm}
+		}}
+	}
+
+	items := itemsFromListing(output, m.currentPrefix, queryPrefix)
+	if m.showContentType {
+		fetchContentTypes(ctx, m.client, m.bucketName, items, m.contentTypes)
+	}
+
+	listItems := make([]list.Item, 0, len(items))
+	for _, i := range items {
+		listItems = append(listItems, i)
 	}
 
-	var items []list.Item
+	msg := itemsLoadedMsg{
+		items:     listItems,
+		hasMore:   aws.BoolValue(output.IsTruncated),
+		nextToken: output.NextContinuationToken,
+	}
+	if m.nextPageToken == nil {
+		m.cache.put(cacheKey, msg)
+	}
+	return msg
+}
+
+// itemsFromListing converts one ListObjectsV2 page into items relative to currentPrefix.
+// Fields some S3-compatible servers omit (size, last modified, truncation) default to zero values.
+func itemsFromListing(output *s3.ListObjectsV2Output, currentPrefix, queryPrefix string) []item {
+	var items []item
 
 	// Process common prefixes (directories)
 	for _, prefix := range output.CommonPrefixes {
-		if prefix.Prefix != nil && *prefix.Prefix != "" && *prefix.Prefix != m.currentPrefix {
-			relativePath := strings.TrimPrefix(*prefix.Prefix, m.currentPrefix)
+		if prefix.Prefix != nil && *prefix.Prefix != "" && *prefix.Prefix != currentPrefix {
+			relativePath := strings.TrimPrefix(*prefix.Prefix, currentPrefix)
 			// Remove trailing slash from display
 			relativePath = strings.TrimSuffix(relativePath, "/")
 
@@ -264,7 +749,7 @@ func (m Model) loadItems() tea.Msg {
 
 	// Process files
 	for _, obj := range output.Contents {
-		if obj.Key == nil || *obj.Key == "" || *obj.Key == m.currentPrefix {
+		if obj.Key == nil || *obj.Key == "" || *obj.Key == currentPrefix {
 			continue
 		}
 
@@ -272,34 +757,16 @@ func (m Model) loadItems() tea.Msg {
 		if strings.Contains(strings.TrimPrefix(*obj.Key, queryPrefix), "/") {
 			continue
 		}
-		relativePath := strings.TrimPrefix(*obj.Key, m.currentPrefix)
-		// Get content type using HeadObject
-		headInput := &s3.HeadObjectInput{
-			Bucket: &m.bucketName,
-			Key:    obj.Key,
-		}
-
-		contentType := ""
-		if m.showContentType {
-			if headOutput, err := m.client.HeadObject(context.TODO(), headInput); err == nil && headOutput.ContentType != nil {
-				contentType = *headOutput.ContentType
-			}
-		}
 		items = append(items, item{
-			key:         *obj.Key, // Keep the full path for consistency
-			size:        *obj.Size,
-			contentType: contentType,
-			displayKey:  relativePath,
-			modified:    *obj.LastModified,
-			isDir:       false,
-		})
-	}
-
-	return itemsLoadedMsg{
-		items:     items,
-		hasMore:   *output.IsTruncated,
-		nextToken: output.NextContinuationToken,
+			key:        *obj.Key, // Keep the full path for consistency
+			size:       aws.Int64Value(obj.Size),
+			displayKey: strings.TrimPrefix(*obj.Key, currentPrefix),
+			modified:   aws.TimeValue(obj.LastModified),
+			etag:       aws.StringValue(obj.ETag),
+			isDir:      false,
+		}.withStorage(obj))
 	}
+	return items
 }
 
 func (m *Model) updateTitle() {
@@ -310,11 +777,135 @@ func (m *Model) updateTitle() {
 	if m.searchTerm != "" {
 		title += fmt.Sprintf(" [search: %s]", m.searchTerm)
 	}
+	if m.itemFilter.active() {
+		title += fmt.Sprintf(" [filter: %s]", m.itemFilter)
+	}
+	if m.itemKind != showAll {
+		title += fmt.Sprintf(" [%s only]", m.itemKind)
+	}
+	if m.sortMode != sortByName {
+		title += fmt.Sprintf(" [sort: %s]", m.sortMode)
+	}
+	if m.opts.profile != "" || m.opts.region != "" {
+		var where []string
+		if m.opts.profile != "" {
+			where = append(where, "profile: "+m.opts.profile)
+		}
+		where = append(where, "region: "+m.region)
+		title += fmt.Sprintf(" (%s)", strings.Join(where, ", "))
+	}
+	if m.opts.dryRun {
+		title += " [DRY RUN]"
+	}
+	if m.opts.readOnly {
+		title += " [READ-ONLY]"
+	}
 	m.list.Title = title
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.loadItems
+	if m.bucketName == "" {
+		return tea.Batch(listBuckets(m.ctx, m.client), m.spinner.Tick)
+	}
+	return tea.Batch(m.loadItems, m.spinner.Tick)
+}
+
+// quit cancels the requests still in flight and exits.
+func (m Model) quit() tea.Cmd {
+	m.cancel()
+	return tea.Quit
+}
+
+// jumpTo switches to bucket and prefix and lists it from the first page.
+func (m *Model) jumpTo(bucket, prefix string) tea.Cmd {
+	return m.jumpToSearch(bucket, prefix, "")
+}
+
+// jumpToSearch switches to bucket and lists the keys under prefix that start with
+// searchTerm from the first page.
+func (m *Model) jumpToSearch(bucket, prefix, searchTerm string) tea.Cmd {
+	m.bucketName = bucket
+	m.currentPrefix = prefix
+	m.searchTerm = searchTerm
+	m.list.ResetFilter()
+	m.updateTitle()
+	return m.reload()
+}
+
+// startIn makes the first listing show prefix, given as part of an s3:// URI, instead of
+// the bucket root.
+func (m *Model) startIn(prefix string) {
+	m.currentPrefix = normalizePrefix(prefix)
+	m.updateTitle()
+}
+
+// withTimeFormat sets the current time format on items, in place.
+func (m *Model) withTimeFormat(items []list.Item) []list.Item {
+	for idx, it := range items {
+		if i, ok := it.(item); ok {
+			i.relativeTime = m.relativeTime
+			items[idx] = i
+		}
+	}
+	return items
+}
+
+// reveal leaves the search and lists the folder containing key, selecting key once it has loaded.
+func (m *Model) reveal(key string) tea.Cmd {
+	cmd := m.jumpTo(m.bucketName, parentPrefix(key))
+	m.selectKey = key
+	return cmd
+}
+
+// selectionIndex finds key in items, falling back to the index nearest to fallback when
+// it is gone, e.g. after a delete.
+func selectionIndex(items []list.Item, key string, fallback int) int {
+	for idx, it := range items {
+		if it.(item).key == key {
+			return idx
+		}
+	}
+	return max(min(fallback, len(items)-1), 0)
+}
+
+// parentPrefix returns the folder prefix that contains key, which may itself be a folder.
+func parentPrefix(key string) string {
+	i := strings.LastIndex(strings.TrimSuffix(key, "/"), "/")
+	if i < 0 {
+		return ""
+	}
+	return key[:i+1]
+}
+
+// goHistory moves delta steps through the navigation history; the move is
+// committed once the target location has loaded.
+func (m *Model) goHistory(delta int) tea.Cmd {
+	target := m.history.pos + delta
+	loc, ok := m.history.at(target)
+	if !ok {
+		return nil
+	}
+	m.historyPending = true
+	m.historyTarget = target
+	return m.jumpToSearch(loc.bucket, loc.prefix, loc.searchTerm)
+}
+
+// reload lists the current prefix again from the first page.
+func (m *Model) reload() tea.Cmd {
+	m.cancelPrefetch()
+	m.selectIndex = 0
+	if m.bucketName == m.shownBucket && m.currentPrefix == m.shownPrefix && m.searchTerm == m.shownSearchTerm {
+		// Reloading the shown listing: keep the cursor where it was.
+		m.selectIndex = m.list.Index()
+		if i, ok := m.list.SelectedItem().(item); ok && m.selectKey == "" {
+			m.selectKey = i.key
+		}
+	}
+	m.loading = true
+	m.nextPageToken = nil
+	m.loadingMore = false
+	m.streaming = false
+	return m.startOperation("Listing "+s3URI(m.bucketName, m.currentPrefix+m.searchTerm), m.listItems)
 }
 
 type ViewFinishedMsg struct {
@@ -326,6 +917,13 @@ type EditFinishedMsg struct {
 	filename    string
 	err         error
 	contentType string
+	// metadata is the downloaded object's user metadata, stored again with the edit.
+	metadata map[string]string
+	// originalHash is the hash of the downloaded object; nil for new files, which are always uploaded.
+	originalHash []byte
+	// etag is the downloaded object's ETag, sent as If-Match with the upload; overwrite skips the check.
+	etag      string
+	overwrite bool
 }
 
 type NewFileMsg struct {
@@ -334,7 +932,7 @@ type NewFileMsg struct {
 type EditFileTickMsg time.Time
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	// log.Println(reflect.TypeOf(msg).Name()+" msg: ", msg)
+	logger.Debugf("%T msg: %v", msg, msg)
 	if m.newFile && m.newFileInput != nil {
 		newFileInput, cmd := m.newFileInput.Update(msg)
 		m.newFileInput = &newFileInput
@@ -353,127 +951,240 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, cmd
 	}
+	if m.viewer != nil {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if key.Matches(msg, m.keys.Quit) {
+				return m, m.quit()
+			}
+		case tea.WindowSizeMsg:
+			m.lastWindowSize = msg
+			m.updateListSize(msg.Width, msg.Height)
+			m.viewer.SetSize(msg.Width, msg.Height)
+			return m, nil
+		case viewExit:
+			m.viewer = nil
+			return m, nil
+		}
+		viewer, cmd := m.viewer.Update(msg)
+		m.viewer = &viewer
+		return m, cmd
+	}
+	if m.split != nil {
+		if updated, cmd, handled := m.updateSplit(msg); handled {
+			return updated, cmd
+		}
+	}
+	var cmds []tea.Cmd
+	if m.prompt != promptNone {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			return m.updatePrompt(msg)
+		}
+		var cmd tea.Cmd
+		m.promptInput, cmd = m.promptInput.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.pickerKind != pickerNone {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if key.Matches(msg, m.keys.Quit) {
+				return m, m.quit()
+			}
+			return m.updatePicker(msg)
+		}
+	}
+	if m.breadcrumbs != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if key.Matches(msg, m.keys.Quit) {
+				return m, m.quit()
+			}
+			return m.updateBreadcrumbs(msg)
+		}
+	}
+	if m.tagForm != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if key.Matches(msg, m.keys.Quit) {
+				return m, m.quit()
+			}
+			return m.updateTagForm(msg)
+		}
+	}
+	if m.confirmUpload != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			cmd := m.resolveConfirmUpload(msg)
+			return m, cmd
+		}
+	}
+	if m.editConflict != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			cmd := m.resolveEditConflict(msg)
+			return m, cmd
+		}
+	}
+	if m.binaryPrompt != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			cmd := m.resolveBinaryPrompt(msg)
+			return m, cmd
+		}
+	}
+	if m.overCap != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			cmd := m.resolveOverCap(msg)
+			return m, cmd
+		}
+	}
 	if m.confirmDelete {
 		if msg, ok := msg.(tea.KeyMsg); ok {
 			m.confirmDelete = false
 			if msg.String() == "y" || msg.String() == "Y" {
+				if m.deleteSelected {
+					cmd := m.startDeleteSelected()
+					return m, cmd
+				}
 				key := m.deleteKey
-				_, err := m.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+				if m.deleteDir {
+					cmd := m.startDeletePrefix(key, m.opts.maxItems)
+					return m, cmd
+				}
+				if m.opts.dryRun {
+					cmd := m.setStatus(skipped("DeleteObject", m.bucketName, key).String())
+					return m, cmd
+				}
+				_, err := m.client.DeleteObject(m.ctx, &s3.DeleteObjectInput{
 					Bucket: aws.String(m.bucketName),
 					Key:    aws.String(key),
 				})
 				if err != nil {
-					return m, func() tea.Msg { return err }
+					ctx, client, bucket := m.ctx, m.client, m.bucketName
+					return m, func() tea.Msg { return explainDeleteError(ctx, client, bucket, key, err) }
 				}
-				m.loading = true
-				m.statusMsg = fmt.Sprintf("Deleted %s", key)
-				m.showStatusMsg = true
-				return m, m.loadItems
+				m.cache.invalidateKey(m.bucketName, key)
+				cmd := tea.Batch(m.setStatus(fmt.Sprintf("Deleted %s", key)), m.reload())
+				return m, cmd
 			}
 		}
 		return m, nil
 	}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" {
-			return m, tea.Quit
+		if key.Matches(msg, m.keys.Quit) {
+			return m, m.quit()
+		}
+
+		// Error statuses stick around until the user does something else.
+		if m.statusIsError {
+			m.clearStatus()
+		}
+
+		if m.operation != nil && key.Matches(msg, m.keys.Dismiss) && m.list.FilterState() != list.Filtering {
+			cmd := m.abortOperation()
+			return m, cmd
+		}
+
+		if m.errMsg != "" && m.list.FilterState() != list.Filtering {
+			if key.Matches(msg, m.keys.Dismiss) {
+				m.errMsg = ""
+				m.errRetry = nil
+				return m, nil
+			}
+			if key.Matches(msg, m.keys.Retry) && m.errRetry != nil {
+				if refusal := m.busyRefusal(msg); refusal != "" {
+					cmd := m.setStatus(refusal)
+					return m, cmd
+				}
+				retry := m.errRetry
+				m.errMsg = ""
+				m.errRetry = nil
+				return m, retry
+			}
+		}
+
+		if m.streaming && key.Matches(msg, m.keys.Dismiss) && m.list.FilterState() == list.Unfiltered {
+			cmd := m.stopStreaming()
+			return m, cmd
+		}
+
+		if len(m.selected) > 0 && key.Matches(msg, m.keys.Dismiss) && m.list.FilterState() == list.Unfiltered {
+			cmd := tea.Batch(m.clearSelection(), m.setStatus("Selection cleared"))
+			return m, cmd
 		}
 
 		// While typing a filter, let the list handle all keys (including backspace),
 		// except the shortcut that re-runs the listing server-side with the typed prefix.
 		if m.list.FilterState() == list.Filtering {
 			if key.Matches(msg, m.keys.Search) {
+				if refusal := m.busyRefusal(msg); refusal != "" {
+					cmd := m.setStatus(refusal)
+					return m, cmd
+				}
 				m.searchTerm = m.list.FilterInput.Value()
 				m.list.ResetFilter()
-				m.loading = true
-				m.nextPageToken = nil
-				m.loadingMore = false
 				m.updateTitle()
-				return m, m.loadItems
+				cmd := m.reload()
+				return m, cmd
 			}
 			break
 		}
 
+		if m.opts.readOnly {
+			if refusal := readOnlyRefusal(msg, &m.keys); refusal != "" {
+				cmd := m.setStatus(refusal)
+				return m, cmd
+			}
+		}
+		if refusal := m.busyRefusal(msg); refusal != "" {
+			cmd := m.setStatus(refusal)
+			return m, cmd
+		}
+
 		if key.Matches(msg, m.keys.Enter) {
 			if i, ok := m.list.SelectedItem().(item); ok && i.isDir {
-				m.loading = true
 				m.currentPrefix = i.key
 				m.searchTerm = ""
-				m.nextPageToken = nil
-				m.loadingMore = false
 				m.list.ResetFilter()
 				m.updateTitle()
-				return m, tea.Batch(
-					m.loadItems,
-				)
+				cmd := m.reload()
+				return m, cmd
 			} else if !i.isDir {
-
-				obj, err := m.client.GetObject(context.TODO(), &s3.GetObjectInput{
-					Bucket: aws.String(m.bucketName),
-					Key:    aws.String(i.key),
-				})
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-
-				defer obj.Body.Close()
-
-				metadata := fmt.Sprintf("s3://%s/%s\nContentType: %s\nMetadata: %v\nSize: %s\nLast-Modified: %s\n%s\n\n", m.bucketName, i.key, i.contentType, obj.Metadata, humanize.Bytes(uint64(i.size)), i.modified.Format("2006-01-02 15:04:05"), strings.Repeat("-", m.lastWindowSize.Width-10))
-
-				tmpFile, err := writeToTmpFile(metadata, obj.Body, fmt.Sprintf("%s-%s", m.bucketName, strings.ReplaceAll(i.key, "/", "_")))
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-
-				cmd := tea.ExecProcess(exec.Command("less", tmpFile), func(err error) tea.Msg {
-					return ViewFinishedMsg{err: err, filename: tmpFile}
-				})
+				cmd := m.viewObject(i, false)
 
 				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.ViewBuiltin) {
+			if i, ok := m.list.SelectedItem().(item); ok && !i.isDir {
+				cmd := m.viewObject(i, true)
 
+				return m, cmd
 			}
 		} else if key.Matches(msg, m.keys.Back) {
 			if m.searchTerm != "" {
-				m.loading = true
 				m.searchTerm = ""
-				m.nextPageToken = nil
-				m.loadingMore = false
 				m.list.ResetFilter()
 				m.updateTitle()
-				return m, m.loadItems
+				cmd := m.reload()
+				return m, cmd
 			}
 			if m.currentPrefix != "" {
-				m.loading = true
-				m.nextPageToken = nil
-				m.loadingMore = false
 				m.list.ResetFilter()
-				// Remove the last directory from the prefix
-				parts := strings.Split(strings.TrimSuffix(m.currentPrefix, "/"), "/")
-				if len(parts) > 0 {
-					parts = parts[:len(parts)-1]
-					m.currentPrefix = strings.Join(parts, "/")
-					if m.currentPrefix != "" {
-						m.currentPrefix += "/"
-					}
-				} else {
-					m.currentPrefix = ""
-				}
+				m.currentPrefix = parentPrefix(m.currentPrefix)
 				m.updateTitle()
-				return m, tea.Batch(
-					m.loadItems,
-				)
+				cmd := m.reload()
+				return m, cmd
+			}
+			if m.list.FilterState() == list.Unfiltered {
+				// Back from the root of a bucket goes up to the bucket list.
+				cmd := m.setStatus("Loading buckets...")
+				return m, tea.Batch(cmd, listBuckets(m.ctx, m.client))
 			}
 		} else if key.Matches(msg, m.keys.Reload) {
-			m.loading = true
-			m.nextPageToken = nil
-			m.loadingMore = false
-			return m, tea.Batch(
-				m.loadItems,
-			)
+			m.cache.invalidate(m.currentListing())
+			cmd := m.reload()
+
+			return m, cmd
 		} else if key.Matches(msg, m.keys.NextPage) {
 			if m.hasMoreItems && !m.loading && !m.loadingMore {
 				m.loadingMore = true
-				return m, m.loadItems
+				cmd := m.nextPage()
+				return m, cmd
 			}
 		} else if key.Matches(msg, m.keys.Edit) {
 
@@ -484,25 +1195,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 
-				obj, err := m.client.GetObject(context.TODO(), &s3.GetObjectInput{
-					Bucket: aws.String(m.bucketName),
-					Key:    aws.String(i.key),
-				})
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-
-				defer obj.Body.Close()
-
-				tmpFile, err := writeToTmpFile("", obj.Body, fmt.Sprintf("%s-%s", m.bucketName, strings.ReplaceAll(i.key, "/", "_")))
-				if err != nil {
-					return m, func() tea.Msg { return err }
-				}
-
-				cmd := tea.ExecProcess(exec.Command(os.Getenv("EDITOR"), tmpFile), func(err error) tea.Msg {
-					return EditFinishedMsg{err: err, filename: tmpFile, key: i.key, contentType: i.contentType}
-				})
-
+				cmd := m.editObject(i)
 				return m, cmd
 
 			}
@@ -516,122 +1209,606 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			textInput.Focus()
 			m.newFileInput = &textInput
 			return m, textinput.Blink
-		} else if key.Matches(msg, m.keys.Delete) {
+		} else if key.Matches(msg, m.keys.AddBookmark) {
+			cmd := m.openPrompt(promptBookmark, "Bookmark label: ", fmt.Sprintf("%s/%s", m.bucketName, m.currentPrefix))
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Bookmarks) {
+			cmd := m.openBookmarks()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Recents) {
+			cmd := m.openRecents()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Summary) && m.bucketName != "" {
+			cmd := m.startSummary(m.bucketName, m.currentPrefix, m.opts.maxItems)
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Count) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.peekDir(i)
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.OpenSystem) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.openWithSystem(i)
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.Console) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.openInConsole(i)
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.Version) {
+			cmd := m.setStatus(buildVersion())
+			return m, cmd
+		} else if key.Matches(msg, m.keys.CopyContent) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.copyContent(i)
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.Reveal) {
+			if i, ok := m.list.SelectedItem().(item); ok && m.searchTerm != "" {
+				cmd := m.reveal(i.key)
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.Buckets) {
+			cmd := m.setStatus("Loading buckets...")
+			return m, tea.Batch(cmd, listBuckets(m.ctx, m.client))
+		} else if key.Matches(msg, m.keys.ToggleTime) {
+			m.relativeTime = !m.relativeTime
+			m.withTimeFormat(m.currentItems)
+			status := "Showing absolute modification times"
+			if m.relativeTime {
+				status = "Showing relative modification times"
+			}
+			// With a fuzzy filter applied, the list filters the new items again in the background.
+			cmd := tea.Batch(m.list.SetItems(m.visibleItems()), m.setStatus(status))
+			return m, cmd
+		} else if key.Matches(msg, m.keys.ContentType) {
+			// Fetching content types costs a HeadObject per object, so it is opt-in per session.
+			m.showContentType = !m.showContentType
+			cmd := m.reload()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Sort) {
+			cmd := m.cycleSort()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Breadcrumbs) {
+			m.openBreadcrumbs()
+			return m, nil
+		} else if key.Matches(msg, m.keys.GoTo) {
+			cmd := m.promptGoTo()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Restore) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.promptRestore(i)
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.Info) {
+			if i, ok := m.list.SelectedItem().(item); ok && !i.isDir {
+				cmd := m.setStatus("Loading object info...")
+				return m, tea.Batch(cmd, loadObjectInfo(m.ctx, m.client, m.bucketName, i.key))
+			}
+		} else if key.Matches(msg, m.keys.Copy) || key.Matches(msg, m.keys.Move) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.promptCopy(i, key.Matches(msg, m.keys.Move))
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.Versions) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.startListVersions(i)
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.NewFolder) {
+			cmd := m.promptNewFolder()
+
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Find) {
+			cmd := m.promptFind()
+
+			return m, cmd
+		} else if key.Matches(msg, m.keys.EditTags) {
 			if i, ok := m.list.SelectedItem().(item); ok && !i.isDir {
+				cmd := m.setStatus("Loading tags...")
+				return m, tea.Batch(cmd, loadTags(m.ctx, m.client, m.bucketName, i.key, false))
+			}
+		} else if key.Matches(msg, m.keys.ItemFilter) {
+			cmd := m.openPrompt(promptItemFilter, "Filter (glob and/or >size, empty clears): ", m.itemFilter.String())
+			return m, cmd
+		} else if key.Matches(msg, m.keys.ItemKind) {
+			cmd := m.cycleItemKind()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.SplitView) {
+			cmd := m.openSplit()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Upload) {
+			cmd := m.promptUpload()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.HistoryBack) {
+			cmd := m.goHistory(-1)
+			return m, cmd
+		} else if key.Matches(msg, m.keys.HistoryFwd) {
+			cmd := m.goHistory(1)
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Delete) {
+			if len(m.selected) > 0 {
+				m.confirmDelete = true
+				m.deleteSelected = true
+				return m, nil
+			}
+			if i, ok := m.list.SelectedItem().(item); ok {
 				m.confirmDelete = true
+				m.deleteSelected = false
 				m.deleteKey = i.key
+				m.deleteDir = i.isDir
 				return m, nil
 			}
+		} else if key.Matches(msg, m.keys.CopyKey, m.keys.CopyURI) {
+			if i, ok := m.list.SelectedItem().(item); ok {
+				cmd := m.copyReference(i, key.Matches(msg, m.keys.CopyURI))
+				return m, cmd
+			}
+		} else if key.Matches(msg, m.keys.Select) {
+			cmd := m.toggleSelected()
+			return m, cmd
+		} else if key.Matches(msg, m.keys.Download) {
+			cmd := m.promptDownload()
+			return m, cmd
 		}
 	case NewFileMsg:
 		m.newFile = false
 		fileKey := m.currentPrefix + m.newFileInput.Value()
-		tmpFile, err := writeToTmpFile("", nil, fmt.Sprintf("%s-%s", m.bucketName, strings.ReplaceAll(fileKey, "/", "_")))
+		editor, err := editorCommand()
+		if err != nil {
+			m.setErrorStatus(err.Error())
+			return m, nil
+		}
+		tmpFile, err := writeToTmpFile(m.opts.tmpDir, "", nil, m.bucketName+"-"+fileKey)
 		if err != nil {
 			return m, func() tea.Msg { return err }
 		}
 
-		cmd := tea.ExecProcess(exec.Command(os.Getenv("EDITOR"), tmpFile), func(err error) tea.Msg {
+		cmd := tea.ExecProcess(exec.Command(editor[0], append(editor[1:], tmpFile)...), func(err error) tea.Msg {
 			return EditFinishedMsg{err: err, filename: tmpFile, key: fileKey, contentType: "text/plain"}
 		})
 		return m, cmd
 
 	case ViewFinishedMsg:
-		os.Remove(msg.filename)
+		removeTmpFile(msg.filename)
 	case EditFinishedMsg:
 		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Edit cancelled, not uploaded: %v", msg.err)
-			m.showStatusMsg = true
-			os.Remove(msg.filename)
+			m.setErrorStatus(fmt.Sprintf("Edit cancelled, not uploaded: %v", msg.err))
+			removeTmpFile(msg.filename)
 			return m, nil
 		}
-		tmp, err := os.Open(msg.filename)
-		if err != nil {
-			return m, func() tea.Msg { return err }
-		}
-		_, err = m.client.PutObject(context.TODO(), &s3.PutObjectInput{
-			Bucket:      aws.String(m.bucketName),
-			Key:         aws.String(msg.key),
-			Body:        tmp,
-			ContentType: aws.String(msg.contentType),
-		})
-		if err != nil {
-			return m, func() tea.Msg { return err }
+		// Uploading identical bytes would only add a version on versioned buckets.
+		if !editChanged(msg) {
+			removeTmpFile(msg.filename)
+			cmd := m.setStatus("No changes, skipped upload")
+			return m, cmd
 		}
+		m.confirmUpload = &msg
+		return m, nil
+	case editUploadedMsg:
+		m.cache.invalidateKey(m.bucketName, msg.key)
 		m.editFileStatus = fmt.Sprintf(" → Uploaded %s %s to %s/%s!", msg.filename, msg.contentType, m.bucketName, msg.key)
-		err = os.Remove(msg.filename)
-		if err != nil {
+		// Only a confirmed upload makes the working copy disposable.
+		if err := removeTmpFile(msg.filename); err != nil {
 			return m, func() tea.Msg { return err }
 		}
-
-		return m, tea.Batch(m.loadItems, tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
+		cmd := tea.Batch(m.reload(), tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
 			return EditFileTickMsg(t)
 		}))
+		return m, cmd
 	case EditFileTickMsg:
 		m.editFileStatus = ""
 
+	case prefetchedMsg:
+		if page, ok := m.streamedPage(msg); ok {
+			return m.Update(page)
+		}
+		cmd := m.receivePrefetch(msg)
+		return m, cmd
+
+	case operationDoneMsg:
+		if !m.finishOperation(msg) {
+			return m, nil
+		}
+		return m.Update(msg.msg)
+
+	case spinner.TickMsg:
+		// The spinner stops once nothing is loading; startOperation starts it again.
+		if !m.loading && m.operation == nil {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case operationTickMsg:
+		if m.operation != nil && m.operation.id == msg.id {
+			cmds = append(cmds, operationTick(msg.id))
+		}
+
+	case editConflictMsg:
+		m.editConflict = &msg
+
+	case overCapMsg:
+		m.overCap = &msg
+
+	case dryRunMsg:
+		cmds = append(cmds, m.setStatus(msg.String()))
+
+	case bucketsLoadedMsg:
+		cmds = append(cmds, m.openBuckets(msg.buckets))
+
+	case dirCountedMsg:
+		m.cache.putCount(msg.bucket, msg.prefix, msg.count)
+		if msg.bucket == m.bucketName {
+			m.currentItems = m.withDirCounts(m.currentItems)
+			cmds = append(cmds, m.list.SetItems(m.visibleItems()))
+		}
+
+	case objectOpenedMsg:
+		cmds = append(cmds, m.setStatus("Opened "+msg.key+" in its default application"))
+
+	case consoleOpenedMsg:
+		cmds = append(cmds, m.setStatus("Opened "+msg.url))
+
+	case textCopiedMsg:
+		cmds = append(cmds, m.setStatus("Copied "+msg.text))
+
+	case contentCopiedMsg:
+		cmds = append(cmds, m.setStatus(fmt.Sprintf("Copied %s of %s to the clipboard", humanize.Bytes(uint64(msg.bytes)), msg.key)))
+
+	case statusMessageTimeoutMsg:
+		if msg.id == m.statusID && !m.statusIsError {
+			m.clearStatus()
+		}
+
 	case tea.WindowSizeMsg:
 		m.lastWindowSize = msg
 		m.updateListSize(msg.Width, msg.Height)
+		if m.pickerKind != pickerNone {
+			h, v := docStyle.GetFrameSize()
+			m.picker.SetSize(msg.Width-h, msg.Height-v-1)
+		}
 
 	case itemsLoadedMsg:
 		if m.loadingMore {
-			m.currentItems = append(m.currentItems, msg.items...)
+			m.currentItems = append(m.currentItems, m.withTimeFormat(msg.items)...)
 		} else {
-			m.currentItems = msg.items
+			if m.bucketName != m.shownBucket || m.currentPrefix != m.shownPrefix {
+				m.selected = nil
+			}
+			m.changes = nil
+			if m.listed && m.bucketName == m.shownBucket && m.currentPrefix == m.shownPrefix && m.searchTerm == m.shownSearchTerm {
+				if changes := diffListings(m.currentItems, msg.items); len(changes) > 0 {
+					cmds = append(cmds, m.highlightChanges(changes))
+				}
+			}
+			m.currentItems = m.withTimeFormat(msg.items)
+			if m.searchTerm == "" {
+				m.recordRecent(m.bucketName, m.currentPrefix)
+			}
 		}
+		m.currentItems = m.withChanges(m.withDirCounts(m.withMarks(m.currentItems)))
+		sortItems(m.currentItems, m.sortMode)
 		m.loadingMore = false
 		m.errMsg = ""
+		m.listed = true
+		m.shownBucket = m.bucketName
+		m.shownPrefix = m.currentPrefix
+		m.shownSearchTerm = m.searchTerm
+		if m.historyPending {
+			m.history.pos = m.historyTarget
+			m.historyPending = false
+		} else {
+			m.history.record(location{bucket: m.bucketName, prefix: m.currentPrefix, searchTerm: m.searchTerm})
+		}
 		m.hasMoreItems = msg.hasMore
 		m.nextPageToken = msg.nextToken
 		m.loading = false
-		m.list.SetItems(m.currentItems)
+		m.list.SetItems(m.visibleItems())
 		if m.lastWindowSize.Width > 0 && m.lastWindowSize.Height > 0 {
 			m.updateListSize(m.lastWindowSize.Width, m.lastWindowSize.Height)
 		}
+		if m.selectKey != "" {
+			m.list.Select(selectionIndex(m.list.Items(), m.selectKey, m.selectIndex))
+			m.selectKey = ""
+		}
 
-		if len(m.currentItems) == 0 {
-			m.statusMsg = "Directory is empty"
+		m.streaming = m.continueStreaming(msg.hasMore)
+		if msg.hasMore {
+			// While streaming, the prefetched page is appended as soon as it arrives.
+			m.loadingMore = m.streaming
+			cmds = append(cmds, m.prefetchNext())
+		}
+
+		if m.statusIsError {
+			// Leave a sticky message up until the user has seen it and pressed a key.
+		} else if m.streaming {
+			cmds = append(cmds, m.setStatus(fmt.Sprintf("Showing %s, listing more (%s to stop)", listingSummary(m.currentItems, true), m.keys.Dismiss.Help().Key)))
+		} else if len(m.currentItems) == 0 {
+			cmds = append(cmds, m.setStatus("Directory is empty"))
 		} else if msg.hasMore {
-			m.statusMsg = fmt.Sprintf("Showing %d items (More available - press 'n' for next page)", len(m.currentItems))
+			cmds = append(cmds, m.setStatus(fmt.Sprintf("Showing %s (More available - press '%s' for next page)", listingSummary(m.currentItems, true), m.keys.NextPage.Help().Key)))
 		} else {
-			m.statusMsg = fmt.Sprintf("Showing %d items (End of list)", len(m.currentItems))
+			cmds = append(cmds, m.setStatus(fmt.Sprintf("Showing %s (End of list)", listingSummary(m.currentItems, false))))
+		}
+		if m.changes != nil && !m.statusIsError {
+			m.statusMsg += " — " + describeChanges(m.changes) + " since the last listing"
+		}
+
+	case changesFadedMsg:
+		m.fadeChanges(msg.id)
+
+	case fileUploadedMsg:
+		m.cache.invalidateKey(msg.bucket, msg.key)
+		m.selectKey = msg.key
+		cmd := tea.Batch(m.setStatus("Uploaded "+s3URI(msg.bucket, msg.key)), m.reload())
+		return m, cmd
+
+	case objectCopiedMsg:
+		m.cache.invalidateKey(msg.dstBucket, msg.dstKey)
+		verb := "Copied"
+		if msg.moved {
+			verb = "Moved"
+			m.cache.invalidateKey(msg.bucket, msg.key)
+		}
+		cmd := tea.Batch(m.setStatus(fmt.Sprintf("%s %s to %s", verb, s3URI(msg.bucket, msg.key), s3URI(msg.dstBucket, msg.dstKey))), m.reload())
+		return m, cmd
+
+	case folderCreatedMsg:
+		m.cache.invalidateKey(msg.bucket, msg.key)
+		m.selectKey = msg.shown
+		cmd := tea.Batch(m.setStatus("Created folder "+s3URI(msg.bucket, msg.key)), m.reload())
+		return m, cmd
+
+	case tagsLoadedMsg:
+		if msg.saved {
+			cmds = append(cmds, m.setStatus(fmt.Sprintf("Saved %d tags on %s", len(msg.tags), msg.key)))
+			break
+		}
+		m.clearStatus()
+		cmd := m.openTagForm(msg.key, msg.tags)
+		return m, cmd
+
+	case tagsSavedMsg:
+		return m, loadTags(m.ctx, m.client, m.bucketName, msg.key, true)
+
+	case versionsLoadedMsg:
+		m.clearStatus()
+		cmd := m.showVersions(msg)
+		return m, cmd
+
+	case findResultsMsg:
+		m.clearStatus()
+		cmd := m.showFindResults(msg)
+		return m, cmd
+
+	case summaryRequestMsg:
+		cmd := m.startSummary(msg.bucket, msg.prefix, msg.limit)
+		return m, cmd
+
+	case findRequestMsg:
+		cmd := m.startFindIn(msg.bucket, msg.prefix, msg.term)
+		return m, cmd
+
+	case versionsRequestMsg:
+		cmd := m.startListVersionsOf(msg.bucket, msg.key)
+		return m, cmd
+
+	case countRequestMsg:
+		cmd := m.startCount(msg.bucket, msg.prefix)
+		return m, cmd
+
+	case summaryMsg:
+		m.cache.putSummary(msg.summary)
+		m.openSummary(msg.summary)
+		return m, nil
+
+	case objectInfoMsg:
+		m.clearStatus()
+		m.openObjectInfo(msg)
+		return m, nil
+
+	case restoreRequestedMsg:
+		m.cache.invalidateKey(m.bucketName, msg.key)
+		cmds = append(cmds, m.setStatus(msg.String()))
+
+	case uploadMsg:
+		cmd := m.startUploadTransfer(msg.bucket, msg.key, msg.path)
+		return m, cmd
+
+	case deletePrefixMsg:
+		cmd := m.startDeletePrefix(msg.prefix, msg.limit)
+		return m, cmd
+
+	case selectionDeletedMsg:
+		for _, k := range msg.keys {
+			m.cache.invalidateKey(msg.bucket, k)
 		}
-		m.showStatusMsg = true
+		m.selected = nil
+		status := msg.summary("Deleted")
+		if msg.failed > 0 {
+			m.setErrorStatus(status)
+			cmd := m.reload()
+			return m, cmd
+		}
+		cmd := tea.Batch(m.setStatus(status), m.reload())
+		return m, cmd
+
+	case selectionDownloadedMsg:
+		status := msg.summary("Downloaded") + " to " + msg.dir
+		if msg.failed > 0 {
+			m.setErrorStatus(status)
+			return m, nil
+		}
+		cmd := m.setStatus(status)
+		return m, cmd
+
+	case prefixDeletedMsg:
+		m.cache.invalidatePrefix(msg.bucket, msg.prefix)
+		cmd := tea.Batch(m.setStatus(fmt.Sprintf("Deleted %d objects under %s", msg.count, msg.prefix)), m.reload())
+		return m, cmd
+
+	case reloadMsg:
+		m.currentPrefix = msg.prefix
+		m.searchTerm = msg.searchTerm
+		m.updateTitle()
+		cmd := m.reload()
+
+		return m, cmd
 
 	case error:
+		logger.Errorf("%v", msg)
+		failedBucket := m.bucketName
+		// A failed listing leaves the previous items on screen; point the title back at them
+		// so navigation continues from what is actually shown.
+		if m.loading && (m.bucketName != m.shownBucket || m.currentPrefix != m.shownPrefix || m.searchTerm != m.shownSearchTerm) {
+			m.bucketName = m.shownBucket
+			m.currentPrefix = m.shownPrefix
+			m.searchTerm = m.shownSearchTerm
+			m.updateTitle()
+		}
 		m.loading = false
 		m.loadingMore = false
-		m.errMsg = msg.Error()
+		m.streaming = false
+		m.historyPending = false
+		m.selectKey = ""
+		m.errMsg = describeError(msg)
+		m.errHint = friendlyError(msg)
+		m.errRetry = nil
+		var opErr errorMsg
+		if errors.As(msg, &opErr) {
+			m.errRetry = opErr.retry
+		}
+		m.startupProblem = nil
+		if !m.listed && failedBucket != "" {
+			m.startupProblem = classifyStartupError(failedBucket, msg)
+		}
 	}
 
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	cmds = append(cmds, cmd)
+	return m, tea.Batch(cmds...)
 }
 
-func writeToTmpFile(metadata string, reader io.Reader, fileName string) (string, error) {
-	tmpFilePath := fmt.Sprintf("/tmp/%s", fileName)
-	tmpFile, err := os.Create(tmpFilePath)
+// viewObject downloads an object and shows it in the pager, or in the built-in
+// viewer when builtin is set or the pager is not installed.
+func (m *Model) viewObject(i item, builtin bool) tea.Cmd {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(m.bucketName),
+		Key:    aws.String(i.key),
+	}
+	if i.versionID != "" {
+		input.VersionId = aws.String(i.versionID)
+	}
+	obj, err := m.client.GetObject(m.ctx, input)
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
+		return func() tea.Msg { return err }
 	}
-	defer tmpFile.Close()
 
-	// Copy the contents of the reader to the temporary file
-	if metadata != "" {
-		if _, err := io.WriteString(tmpFile, metadata); err != nil {
-			return "", fmt.Errorf("failed to write metadata to temp file: %w", err)
-		}
+	// Decide how to render from the inferred type; the metadata header shows what S3 stored.
+	contentType := renderContentType(i.key, aws.StringValue(obj.ContentType))
+	if !m.opts.noImages && strings.HasPrefix(contentType, "image/") {
+		defer obj.Body.Close()
+		return m.previewImage(i.key, obj.Body)
+	}
+
+	body, binary, err := sniffBody(obj.Body)
+	if err != nil {
+		obj.Body.Close()
+		return func() tea.Msg { return err }
+	}
+	if binary {
+		m.binaryPrompt = &binaryPrompt{item: i, builtin: builtin, obj: obj, body: body}
+		return nil
 	}
-	if reader != nil {
-		if _, err := io.Copy(tmpFile, reader); err != nil {
-			return "", fmt.Errorf("failed to write to temp file: %w", err)
+	return m.openViewer(i, builtin, obj, body)
+}
+
+// openViewer shows a downloaded object, read from body, in the pager or the built-in viewer.
+func (m *Model) openViewer(i item, builtin bool, obj *s3.GetObjectOutput, body io.Reader) tea.Cmd {
+	contentType := renderContentType(i.key, aws.StringValue(obj.ContentType))
+
+	metadata := fmt.Sprintf("%s\nContentType: %s\nMetadata: %v\nSize: %s\nLast-Modified: %s\n%s\n\n", s3URI(m.bucketName, i.key), i.contentType, obj.Metadata, humanize.Bytes(uint64(i.size)), formatTime(i.modified, m.relativeTime), strings.Repeat("-", max(m.lastWindowSize.Width-10, 0)))
+
+	pager, err := pagerCommand(m.opts.pager)
+	notice := ""
+	if err != nil {
+		builtin = true
+		notice = fmt.Sprintf("No pager found (%v), using the built-in viewer", err)
+	}
+	if builtin {
+		content, err := io.ReadAll(body)
+		obj.Body.Close()
+		if err != nil {
+			return func() tea.Msg { return err }
+		}
+		title := s3URI(m.bucketName, i.key)
+		if i.versionID != "" {
+			title += " (version " + i.versionID + ")"
 		}
+		viewer := NewViewModel(title, metadata, string(content), m.lastWindowSize.Width, m.lastWindowSize.Height)
+		viewer.notice = notice
+		viewer.markdown = isMarkdown(i.key, contentType)
+		viewer.setSyntax(i.key, contentType)
+		m.viewer = &viewer
+		return nil
 	}
 
-	// Return the path to the temporary file
-	return tmpFile.Name(), nil
+	if m.opts.stream {
+		// The body stays open until the pager exits; nothing is written to disk.
+		return tea.ExecProcess(streamCommand(pager, metadata, body), func(err error) tea.Msg {
+			obj.Body.Close()
+			return ViewFinishedMsg{err: err}
+		})
+	}
+
+	// The pager gets the content alone; the metadata is a keypress away in the inspector.
+	tmpFile, err := writeToTmpFile(m.opts.tmpDir, "", body, m.bucketName+"-"+i.key)
+	obj.Body.Close()
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+
+	return tea.ExecProcess(exec.Command(pager[0], append(pager[1:], tmpFile)...), func(err error) tea.Msg {
+		return ViewFinishedMsg{err: err, filename: tmpFile}
+	})
+}
+
+// previewImage draws an image object inline when the terminal supports a graphics
+// protocol, and otherwise reports its dimensions in the status bar.
+func (m *Model) previewImage(key string, body io.Reader) tea.Cmd {
+	data, err := io.ReadAll(io.LimitReader(body, maxImagePreviewBytes+1))
+	if err != nil {
+		return func() tea.Msg { return err }
+	}
+	if len(data) > maxImagePreviewBytes {
+		return m.setStatus(fmt.Sprintf("%s is larger than %s, too big to preview", key, humanize.Bytes(maxImagePreviewBytes)))
+	}
+	desc, err := describeImage(data)
+	if err != nil {
+		return m.setStatus(fmt.Sprintf("Cannot preview %s: unsupported image format", key))
+	}
+
+	protocol := detectGraphics()
+	if protocol == graphicsNone {
+		return m.setStatus(fmt.Sprintf("Image %s (terminal has no inline graphics support)", desc))
+	}
+	preview := &imagePreview{
+		data:     data,
+		protocol: protocol,
+		caption:  fmt.Sprintf("%s (%s)", s3URI(m.bucketName, key), desc),
+		cols:     m.lastWindowSize.Width,
+		rows:     m.lastWindowSize.Height,
+	}
+	return tea.Exec(preview, func(err error) tea.Msg {
+		if err != nil {
+			return err
+		}
+		return nil
+	})
 }
 
 func (m *Model) updateListSize(width, height int) {
@@ -641,11 +1818,37 @@ func (m *Model) updateListSize(width, height int) {
 }
 
 func (m Model) footer() string {
+	if m.prompt != promptNone {
+		return docStyle.Render(m.promptInput.View())
+	}
+	if m.breadcrumbs != nil {
+		return docStyle.Render(m.breadcrumbsView())
+	}
+	if m.confirmUpload != nil {
+		return docStyle.Render(m.confirmUploadView())
+	}
+	if m.editConflict != nil {
+		return docStyle.Render(m.confirmOverwriteView())
+	}
+	if m.binaryPrompt != nil {
+		return docStyle.Render(m.binaryPromptView())
+	}
+	if m.overCap != nil {
+		return docStyle.Render(m.overCap.question + " (y/N)")
+	}
 	if m.confirmDelete {
+		if m.deleteSelected {
+			return docStyle.Render(fmt.Sprintf("Delete %s? (y/N)", plural(len(m.selected), "selected object", "selected objects")))
+		}
+		if m.deleteDir {
+			return docStyle.Render(fmt.Sprintf("Delete everything under %s? (y/N)", m.deleteKey))
+		}
 		return docStyle.Render(fmt.Sprintf("Delete %s? (y/N)", m.deleteKey))
 	} else if m.newFile && m.newFileInput != nil {
 		return m.newFileInput.View()
 
+	} else if m.operation != nil {
+		return docStyle.Render(m.operationView())
 	} else if m.showStatusMsg {
 		statusMsg := m.statusMsg
 		if m.editFileStatus != "" {
@@ -657,35 +1860,207 @@ func (m Model) footer() string {
 }
 
 func (m Model) View() string {
+	if m.viewer != nil {
+		return m.viewer.View()
+	}
+	if m.split != nil {
+		return m.splitViewRender()
+	}
+	if m.pickerKind != pickerNone {
+		return m.pickerView()
+	}
+	if m.tagForm != nil {
+		return m.tagFormView()
+	}
 	if m.loading {
-		return "Loading..."
+		progress := m.operationView()
+		if progress == "" {
+			progress = m.spinner.View() + " Loading..."
+		}
+		title := m.list.Styles.Title.Render(m.list.Title)
+		return docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, title, progress))
 	}
 
-	if m.errMsg != "" {
-		return docStyle.Render(fmt.Sprintf("Error: %s\n\nPress ctrl+r to retry, q to quit.", m.errMsg))
+	if m.errMsg != "" && m.startupProblem != nil {
+		return m.startupView()
+	}
+	if m.errMsg == "" && m.isEmptyBucket() {
+		footer := m.footer()
+		return lipgloss.JoinVertical(lipgloss.Top, m.emptyBucketView(m.lastWindowSize.Height-lipgloss.Height(footer)), footer)
 	}
 
 	// return m.list.View()
-	return lipgloss.JoinVertical(lipgloss.Top, m.list.View(), m.footer())
+	panel := m.errorPanel()
+	if panel == "" {
+		return lipgloss.JoinVertical(lipgloss.Top, m.list.View(), m.footer())
+	}
+
+	// Shrink the list so the panel fits below it without pushing the title off screen.
+	l := m.list
+	l.SetHeight(max(l.Height()-lipgloss.Height(panel), 0))
+	return lipgloss.JoinVertical(lipgloss.Top, l.View(), m.footer(), panel)
+}
+
+// errorPanel renders the current error in a bordered box, or "" when there is none.
+func (m Model) errorPanel() string {
+	if m.errMsg == "" {
+		return ""
+	}
+	hint := fmt.Sprintf("%s %s", helpStyleKey.Render(m.keys.Dismiss.Help().Key), helpStyleVal.Render(m.keys.Dismiss.Help().Desc))
+	if m.errRetry != nil {
+		hint += fmt.Sprintf(" • %s %s", helpStyleKey.Render(m.keys.Retry.Help().Key), helpStyleVal.Render(m.keys.Retry.Help().Desc))
+	}
+	hint += fmt.Sprintf(" • %s %s", helpStyleKey.Render(m.keys.Quit.Help().Key), helpStyleVal.Render(m.keys.Quit.Help().Desc))
+
+	style := errorPanelStyle
+	if w := m.lastWindowSize.Width - docStyle.GetHorizontalFrameSize(); w > 0 {
+		style = style.Width(w - style.GetHorizontalBorderSize())
+	}
+	lines := []string{errorTitleStyle.Render("Error")}
+	if m.errHint != "" {
+		lines = append(lines, lipgloss.NewStyle().Bold(true).Render(m.errHint))
+	}
+	lines = append(lines, m.errMsg, "", hint)
+	return docStyle.Render(style.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)))
 }
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Please provide a bucket name")
+	opts, args, err := parseFlags(os.Args[1:])
+	if err != nil {
+		os.Exit(2)
+	}
+	if opts.version {
+		fmt.Println(buildVersion())
+		return
+	}
+	closeLog, err := setupLogging(opts)
+	if err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
-	if os.Getenv("DEBUG") == "true" {
-		f, _ := tea.LogToFile("log.txt", "debug")
-		logger.Initialize(f)
-		defer f.Close()
+	defer closeLog()
+
+	cfg, err := loadUserConfig()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if _, err := newKeyMap(cfg.Keys); err != nil {
+		path, _ := configFile()
+		fmt.Fprintf(os.Stderr, "Invalid key bindings in %s: %v\n", path, err)
+		closeLog()
+		os.Exit(1)
 	}
+	opts.keyOverrides = cfg.Keys
 
-	bucketName := os.Args[1]
-	m := initialModel(bucketName)
+	if err := checkCredentials(opts); err != nil {
+		logger.Errorf("credentials check failed: %v", err)
+		if opts.roleARN != "" {
+			// The error names the role; the help on setting up credentials is beside the point.
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s\n\nError: %v\n", credentialsHelp, err)
+		}
+		closeLog()
+		if opts.head != "" {
+			// --head exits 1 only when the object is not found.
+			os.Exit(headExitError)
+		}
+		os.Exit(1)
+	}
+
+	// Without a bucket the bucket list is the first screen.
+	var bucketName, prefix string
+	if len(args) > 0 {
+		if bucketName, prefix, err = parseLocation(args[0]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			closeLog()
+			os.Exit(2)
+		}
+	}
+	if opts.head != "" {
+		if bucketName == "" {
+			fmt.Fprintln(os.Stderr, "--head needs a bucket name")
+			closeLog()
+			os.Exit(headExitError)
+		}
+		client, err := newS3Client(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			closeLog()
+			os.Exit(headExitError)
+		}
+		code := runHead(context.Background(), withRequestPayer(client, opts), bucketName, opts.head, os.Stdout, os.Stderr)
+		closeLog()
+		os.Exit(code)
+	}
+	if opts.list {
+		if bucketName == "" {
+			fmt.Fprintln(os.Stderr, "--list needs a bucket name")
+			closeLog()
+			os.Exit(listExitError)
+		}
+		if len(args) > 1 {
+			prefix = args[1]
+		}
+		client, err := newS3Client(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			closeLog()
+			os.Exit(listExitError)
+		}
+		code := runList(context.Background(), withRequestPayer(client, opts), bucketName, prefix, opts.long, os.Stdout, os.Stderr)
+		closeLog()
+		os.Exit(code)
+	}
+	m := initialModel(bucketName, opts)
+	m.startIn(prefix)
+	if m.recentsFile, err = recentsFile(); err != nil {
+		logger.Warnf("recent locations are not remembered: %v", err)
+	}
+	if n, err := sweepStaleTmpFiles(opts.tmpDir, staleTmpFileAge); err != nil {
+		logger.Warnf("failed to look for stale temp files: %v", err)
+	} else if n > 0 {
+		logger.Infof("removed %d stale temp files", n)
+	}
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP)
+	go cleanupOnSignal(signals, p.Kill)
+
+	_, err = p.Run()
+	signal.Stop(signals)
+	m.cancel()
+	cleanupTmpFiles()
+	if err != nil {
+		closeLog()
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
 }
+
+// setupLogging sends logs at or above --log-level to opts.logFile when --debug, --log-level or
+// DEBUG=true is set. Anything else written through the standard logger is discarded, since
+// stray output corrupts the TUI.
+func setupLogging(opts options) (func(), error) {
+	if !opts.debug && opts.logLevel == "" && os.Getenv("DEBUG") != "true" {
+		log.SetOutput(io.Discard)
+		return func() {}, nil
+	}
+	level := logger.LevelInfo
+	if opts.logLevel != "" {
+		var err error
+		if level, err = logger.ParseLevel(opts.logLevel); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.OpenFile(opts.logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	log.SetOutput(f)
+	logger.Initialize(f, level)
+	logger.Infof("s3n starting, args: %v", os.Args[1:])
+	return func() { f.Close() }, nil
+}
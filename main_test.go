@@ -1,13 +1,18 @@
 // ABOUTME: Tests for the TUI Update logic in main.go.
-// ABOUTME: Covers edit-cancel handling and filter-mode key behavior.
+// ABOUTME: Covers edit-cancel handling, filter-mode key behavior and status messages.
 package main
 
 import (
 	"errors"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -21,7 +26,7 @@ func isQuit(cmd tea.Cmd) bool {
 }
 
 func TestEscapeDoesNotQuit(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.list.SetItems([]list.Item{item{key: "a.txt", displayKey: "a.txt"}})
 
@@ -32,7 +37,7 @@ func TestEscapeDoesNotQuit(t *testing.T) {
 }
 
 func TestCtrlCQuits(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 
 	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
@@ -67,8 +72,86 @@ func TestEditFinishedWithErrorDoesNotUpload(t *testing.T) {
 	}
 }
 
+func TestEditFinishedUnchangedSkipsUpload(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("content"), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := fileHash(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nil client: any attempt to upload would panic, proving the upload was skipped.
+	m := Model{}
+	updated, _ := m.Update(EditFinishedMsg{filename: path, key: "foo", contentType: "text/plain", originalHash: hash})
+	um := updated.(Model)
+
+	if um.statusMsg != "No changes, skipped upload" {
+		t.Errorf("status = %q, want the skipped-upload message", um.statusMsg)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected temp file %q to be removed", path)
+	}
+}
+
+// tmpFileWith writes content to a working copy like the editor flow does.
+func tmpFileWith(t *testing.T, content string) string {
+	t.Helper()
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader(content), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEditChanged(t *testing.T) {
+	path := tmpFileWith(t, "content")
+	same, _ := fileHash(path)
+	other, _ := fileHash(tmpFileWith(t, "other content"))
+
+	if editChanged(EditFinishedMsg{filename: path, originalHash: same}) {
+		t.Error("identical content reported as changed")
+	}
+	if !editChanged(EditFinishedMsg{filename: path, originalHash: other}) {
+		t.Error("different content reported as unchanged")
+	}
+	if !editChanged(EditFinishedMsg{filename: path}) {
+		t.Error("a new object must always be uploaded")
+	}
+}
+
+func TestEditFinishedChangedAsksBeforeUpload(t *testing.T) {
+	path := tmpFileWith(t, "edited")
+	original, _ := fileHash(tmpFileWith(t, "original"))
+
+	// nil client: an upload before confirming would panic.
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, cmd := m.Update(EditFinishedMsg{filename: path, key: "foo", contentType: "text/plain", originalHash: original})
+	m = updated.(Model)
+	if cmd != nil || m.confirmUpload == nil {
+		t.Fatal("expected a confirmation before uploading")
+	}
+	if got := m.footer(); !strings.Contains(got, "Upload your changes to s3://test-bucket/foo? (y/N)") {
+		t.Errorf("footer = %q", got)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if cmd != nil || m.confirmUpload != nil {
+		t.Error("declining must not upload")
+	}
+	if want := "Not uploaded, your edits are kept in " + path; m.statusMsg != want {
+		t.Errorf("status = %q, want %q", m.statusMsg, want)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("the working copy should be kept: %v", err)
+	}
+}
+
 func TestBackspaceWhileFilteringDoesNotNavigate(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.currentPrefix = "a/b/"
 	m.list.SetItems([]list.Item{item{key: "a/b/x", displayKey: "x"}})
 
@@ -87,7 +170,7 @@ func TestBackspaceWhileFilteringDoesNotNavigate(t *testing.T) {
 }
 
 func TestEnterDirectoryResetsAppliedFilter(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.list.SetItems([]list.Item{item{key: "sub/", displayKey: "sub", isDir: true}})
 
@@ -115,7 +198,7 @@ func TestEnterDirectoryResetsAppliedFilter(t *testing.T) {
 }
 
 func TestCtrlDOnFilePromptsConfirmation(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.list.SetItems([]list.Item{item{key: "a/b/file.txt", displayKey: "file.txt"}})
 
@@ -130,16 +213,19 @@ func TestCtrlDOnFilePromptsConfirmation(t *testing.T) {
 	}
 }
 
-func TestCtrlDOnDirectoryDoesNothing(t *testing.T) {
-	m := initialModel("test-bucket")
+func TestCtrlDOnDirectoryAsksToDeleteEverything(t *testing.T) {
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.list.SetItems([]list.Item{item{key: "sub/", displayKey: "sub", isDir: true}})
 
 	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
 	m = updated.(Model)
 
-	if m.confirmDelete {
-		t.Errorf("expected ctrl+d on a directory to be ignored")
+	if !m.confirmDelete || !m.deleteDir || m.deleteKey != "sub/" {
+		t.Fatalf("expected a recursive delete of sub/ to be confirmed, got confirm=%v dir=%v key=%q", m.confirmDelete, m.deleteDir, m.deleteKey)
+	}
+	if !strings.Contains(m.footer(), "Delete everything under sub/?") {
+		t.Errorf("footer = %q", m.footer())
 	}
 }
 
@@ -156,7 +242,7 @@ func TestDeleteCancelledDoesNotDelete(t *testing.T) {
 }
 
 func TestSearchShortcutWhileFilteringSetsSearchTerm(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.currentPrefix = "logs/"
 	m.list.SetItems([]list.Item{item{key: "logs/a.txt", displayKey: "a.txt"}})
@@ -180,7 +266,7 @@ func TestSearchShortcutWhileFilteringSetsSearchTerm(t *testing.T) {
 }
 
 func TestBackExitsSearchWithoutNavigatingUp(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.currentPrefix = "logs/"
 	m.searchTerm = "foo"
@@ -198,7 +284,7 @@ func TestBackExitsSearchWithoutNavigatingUp(t *testing.T) {
 }
 
 func TestNextPageRequestsMoreWhenAvailable(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.hasMoreItems = true
 	m.list.SetItems([]list.Item{item{key: "a.txt", displayKey: "a.txt"}})
@@ -212,7 +298,7 @@ func TestNextPageRequestsMoreWhenAvailable(t *testing.T) {
 }
 
 func TestNextPageIgnoredWhenNoMore(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.loading = false
 	m.hasMoreItems = false
 	m.list.SetItems([]list.Item{item{key: "a.txt", displayKey: "a.txt"}})
@@ -226,7 +312,7 @@ func TestNextPageIgnoredWhenNoMore(t *testing.T) {
 }
 
 func TestItemsLoadedAppendsWhenLoadingMore(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.currentItems = []list.Item{item{key: "a.txt", displayKey: "a.txt"}}
 	m.loadingMore = true
 
@@ -244,14 +330,16 @@ func TestItemsLoadedAppendsWhenLoadingMore(t *testing.T) {
 	}
 }
 
-func TestErrorReplacesScreen(t *testing.T) {
-	m := initialModel("test-bucket")
+func TestErrorShowsPanelBelowList(t *testing.T) {
+	m := initialModel("test-bucket", options{})
 	// Init sets loading = true; an initial load failure must surface, not hang on "Loading...".
 	if !m.loading {
 		t.Fatalf("expected model to start in loading state")
 	}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m = updated.(Model)
 
-	updated, _ := m.Update(errors.New("AccessDenied: token expired"))
+	updated, _ = m.Update(errors.New("AccessDenied: token expired"))
 	m = updated.(Model)
 
 	if m.loading {
@@ -263,12 +351,53 @@ func TestErrorReplacesScreen(t *testing.T) {
 
 	view := m.View()
 	if !strings.Contains(view, "AccessDenied: token expired") {
-		t.Errorf("expected the error to take over the screen, got %q", view)
+		t.Errorf("expected the error panel to show the full error, got %q", view)
+	}
+	if !strings.Contains(view, "test-bucket") {
+		t.Errorf("expected the list to stay visible behind the error panel, got %q", view)
+	}
+}
+
+func TestEscDismissesErrorPanel(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, _ := m.Update(errors.New("boom"))
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+
+	if m.errMsg != "" {
+		t.Errorf("expected esc to dismiss the error, got %q", m.errMsg)
+	}
+}
+
+func TestRetryRerunsFailedCommand(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	retry := func() tea.Msg { return reloadMsg{prefix: "logs/"} }
+	updated, _ := m.Update(errorMsg{err: errors.New("boom"), retry: retry})
+	m = updated.(Model)
+	if !strings.Contains(m.View(), "retry") {
+		t.Errorf("expected the error panel to offer a retry")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+
+	if m.errMsg != "" {
+		t.Errorf("expected retry to clear the error panel")
+	}
+	if cmd == nil {
+		t.Fatalf("expected retry to return the failed command")
+	}
+	if _, ok := cmd().(reloadMsg); !ok {
+		t.Errorf("expected retry to re-run the failed command")
 	}
 }
 
 func TestSuccessfulLoadClearsError(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.errMsg = "AccessDenied: token expired"
 
 	updated, _ := m.Update(itemsLoadedMsg{
@@ -283,7 +412,7 @@ func TestSuccessfulLoadClearsError(t *testing.T) {
 }
 
 func TestItemsLoadedReplacesWhenNotLoadingMore(t *testing.T) {
-	m := initialModel("test-bucket")
+	m := initialModel("test-bucket", options{})
 	m.currentItems = []list.Item{
 		item{key: "a.txt", displayKey: "a.txt"},
 		item{key: "b.txt", displayKey: "b.txt"},
@@ -300,3 +429,457 @@ func TestItemsLoadedReplacesWhenNotLoadingMore(t *testing.T) {
 		t.Errorf("expected items to be replaced (1 item), got %d", len(m.currentItems))
 	}
 }
+
+func TestStatusMessageClearedAfterTimeout(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+
+	updated, _ := m.Update(itemsLoadedMsg{
+		items: []list.Item{item{key: "a.txt", displayKey: "a.txt"}},
+	})
+	m = updated.(Model)
+	if !m.showStatusMsg {
+		t.Fatalf("expected a status message after loading items")
+	}
+
+	updated, _ = m.Update(statusMessageTimeoutMsg{id: m.statusID})
+	m = updated.(Model)
+
+	if m.showStatusMsg || m.statusMsg != "" {
+		t.Errorf("expected status message to be cleared after the timeout, got %q", m.statusMsg)
+	}
+}
+
+func TestStaleStatusTimeoutKeepsNewerMessage(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.setStatus("first")
+	stale := m.statusID
+	m.setStatus("second")
+
+	updated, _ := m.Update(statusMessageTimeoutMsg{id: stale})
+	m = updated.(Model)
+
+	if m.statusMsg != "second" {
+		t.Errorf("expected newer status to survive a stale timeout, got %q", m.statusMsg)
+	}
+}
+
+func TestErrorStatusIsStickyUntilNextKey(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.setErrorStatus("Edit cancelled")
+
+	updated, _ := m.Update(statusMessageTimeoutMsg{id: m.statusID})
+	m = updated.(Model)
+	if !m.showStatusMsg {
+		t.Fatalf("expected error status to survive the timeout")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if m.showStatusMsg {
+		t.Errorf("expected error status to be cleared by the next key press")
+	}
+}
+
+func TestFailedNavigationKeepsPreviousItems(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{
+		items: []list.Item{
+			item{key: "sub/", displayKey: "sub", isDir: true},
+			item{key: "a.txt", displayKey: "a.txt"},
+		},
+	})
+	m = updated.(Model)
+
+	// Entering the directory issues a second load, which fails.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.currentPrefix != "sub/" {
+		t.Fatalf("expected navigation into sub/, got %q", m.currentPrefix)
+	}
+	updated, _ = m.Update(errorMsg{err: errors.New("AccessDenied")})
+	m = updated.(Model)
+
+	if len(m.currentItems) != 2 || len(m.list.Items()) != 2 {
+		t.Errorf("expected the previous items to remain, got %d current / %d listed", len(m.currentItems), len(m.list.Items()))
+	}
+	if m.currentPrefix != "" {
+		t.Errorf("expected prefix to point back at the items shown, got %q", m.currentPrefix)
+	}
+	if m.errMsg == "" {
+		t.Errorf("expected the error to be shown over the list")
+	}
+}
+
+func TestItemsFromListingToleratesNilFields(t *testing.T) {
+	output := &s3.ListObjectsV2Output{
+		CommonPrefixes: []types.CommonPrefix{
+			{Prefix: nil},
+			{Prefix: aws.String("logs/sub/")},
+		},
+		Contents: []types.Object{
+			{Key: nil},
+			{Key: aws.String("logs/a.txt")},
+			{Key: aws.String("logs/b.txt"), Size: aws.Int64(42)},
+		},
+	}
+
+	items := itemsFromListing(output, "logs/", "logs/")
+
+	if len(items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(items))
+	}
+	if !items[0].isDir || items[0].displayKey != "sub" {
+		t.Errorf("expected directory sub first, got %+v", items[0])
+	}
+	if items[1].size != 0 || !items[1].modified.IsZero() {
+		t.Errorf("expected zero size and time for missing fields, got %+v", items[1])
+	}
+	if items[2].size != 42 {
+		t.Errorf("expected size 42, got %d", items[2].size)
+	}
+}
+
+func TestViewerExitReturnsToList(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	v := NewViewModel("s3://test-bucket/a.txt", "", "hello", 80, 24)
+	m.viewer = &v
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+
+	if m.viewer != nil {
+		t.Errorf("expected q to close the built-in viewer")
+	}
+}
+
+func TestParentPrefix(t *testing.T) {
+	tests := map[string]string{
+		"a.txt":          "",
+		"logs/a.txt":     "logs/",
+		"logs/2024/01/a": "logs/2024/01/",
+		"logs/2024/":     "logs/",
+		"logs/":          "",
+	}
+	for key, want := range tests {
+		if got := parentPrefix(key); got != want {
+			t.Errorf("parentPrefix(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestRevealOpensContainingFolderAndSelectsKey(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.searchTerm = "logs/2024"
+	m.list.SetItems([]list.Item{item{key: "logs/2024/01/app.log", displayKey: "logs/2024/01/app.log"}})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+	m = updated.(Model)
+	if m.currentPrefix != "logs/2024/01/" || m.searchTerm != "" || !m.loading {
+		t.Fatalf("prefix=%q search=%q loading=%v, want logs/2024/01/ with search cleared and loading", m.currentPrefix, m.searchTerm, m.loading)
+	}
+
+	updated, _ = m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "logs/2024/01/a.log", displayKey: "a.log"},
+		item{key: "logs/2024/01/app.log", displayKey: "app.log"},
+	}})
+	m = updated.(Model)
+	if i, _ := m.list.SelectedItem().(item); i.key != "logs/2024/01/app.log" {
+		t.Errorf("selected %q, want the revealed key", i.key)
+	}
+}
+
+func TestToggleContentTypeReloadsWithTypes(t *testing.T) {
+	var heads int
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads++
+			w.Header().Set("Content-Type", "text/csv")
+			return
+		}
+		w.Write([]byte(`<ListBucketResult><Name>test-bucket</Name><Contents><Key>a.csv</Key><Size>1</Size></Contents></ListBucketResult>`))
+	})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	updated, _ := m.Update(m.loadItems())
+	m = updated.(Model)
+	if heads != 0 || strings.Contains(m.list.Items()[0].(item).Description(), "Content-Type") {
+		t.Fatalf("expected no content types by default")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m = updated.(Model)
+	if !m.showContentType || !m.loading {
+		t.Fatalf("expected ctrl+t to turn content types on and reload")
+	}
+	updated, _ = m.Update(operationResult(t, cmd))
+	m = updated.(Model)
+	if d := m.list.Items()[0].(item).Description(); heads != 1 || !strings.Contains(d, "Content-Type: text/csv") {
+		t.Errorf("expected the reload to fetch content types, got %q after %d HeadObject calls", d, heads)
+	}
+
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	m = updated.(Model)
+	updated, _ = m.Update(operationResult(t, cmd))
+	m = updated.(Model)
+	if d := m.list.Items()[0].(item).Description(); strings.Contains(d, "Content-Type") {
+		t.Errorf("expected content types hidden after toggling off, got %q", d)
+	}
+}
+
+func TestListingAsksForPageSize(t *testing.T) {
+	for _, tt := range []struct {
+		opts options
+		want string
+	}{
+		{options{}, "100"},
+		{options{pageSize: 500}, "500"},
+	} {
+		var maxKeys string
+		client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+			maxKeys = r.URL.Query().Get("max-keys")
+			w.Write([]byte(`<ListBucketResult><Name>test-bucket</Name></ListBucketResult>`))
+		})
+		m := initialModel("test-bucket", tt.opts)
+		m.client = client
+		m.loadItems()
+		if maxKeys != tt.want {
+			t.Errorf("page size %d: max-keys = %q, want %s", tt.opts.pageSize, maxKeys, tt.want)
+		}
+	}
+}
+
+func TestReloadReusesCachedContentTypes(t *testing.T) {
+	var heads int
+	etag := `"v1"`
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads++
+			w.Header().Set("Content-Type", "text/csv")
+			return
+		}
+		w.Write([]byte(`<ListBucketResult><Name>test-bucket</Name><Contents><Key>a.csv</Key><ETag>` + etag + `</ETag><Size>1</Size></Contents><Contents><Key>b.csv</Key><ETag>"b"</ETag><Size>1</Size></Contents></ListBucketResult>`))
+	})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	m.showContentType = true
+	m.cache = nil // list again each time instead of reusing the listing
+	updated, _ := m.Update(m.loadItems())
+	m = updated.(Model)
+	if heads != 2 {
+		t.Fatalf("expected the first load to fetch 2 content types, got %d HeadObject calls", heads)
+	}
+
+	heads = 0
+	updated, _ = m.Update(m.loadItems())
+	m = updated.(Model)
+	if d := m.list.Items()[0].(item).Description(); heads != 0 || !strings.Contains(d, "Content-Type: text/csv") {
+		t.Errorf("expected the reload to reuse cached content types, got %q after %d HeadObject calls", d, heads)
+	}
+
+	etag = `"v2"`
+	m.loadItems()
+	if heads != 1 {
+		t.Errorf("expected only the changed object to be fetched again, got %d HeadObject calls", heads)
+	}
+}
+
+func TestToggleTimeShowsRelativeModified(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "a.txt", displayKey: "a.txt", modified: time.Now().Add(-3 * time.Hour)},
+	}})
+	m = updated.(Model)
+	if d := m.list.Items()[0].(item).Description(); strings.Contains(d, "ago") {
+		t.Fatalf("expected an absolute time by default, got %q", d)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	m = updated.(Model)
+	if d := m.list.Items()[0].(item).Description(); !strings.Contains(d, "3 hours ago") {
+		t.Errorf("expected a relative time after toggling, got %q", d)
+	}
+	if m.statusMsg != "Showing relative modification times" {
+		t.Errorf("status = %q, want the toggle confirmed", m.statusMsg)
+	}
+
+	updated, _ = m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "b.txt", displayKey: "b.txt", modified: time.Now().Add(-2 * time.Hour)},
+	}})
+	m = updated.(Model)
+	if d := m.list.Items()[0].(item).Description(); !strings.Contains(d, "2 hours ago") {
+		t.Errorf("expected newly loaded items to keep the relative format, got %q", d)
+	}
+}
+
+func TestDryRunDeleteSkipsCall(t *testing.T) {
+	m := initialModel("test-bucket", options{dryRun: true})
+	m.loading = false
+	if !strings.HasSuffix(m.list.Title, "[DRY RUN]") {
+		t.Errorf("title %q does not show dry-run mode", m.list.Title)
+	}
+	m.confirmDelete = true
+	m.deleteKey = "a.txt"
+
+	// nil client: a real delete would panic.
+	m.client = nil
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	m = updated.(Model)
+	if m.statusMsg != "[dry-run] would DeleteObject s3://test-bucket/a.txt" {
+		t.Errorf("status = %q, want the dry-run report", m.statusMsg)
+	}
+}
+
+func TestTitleShowsProfileAndRegion(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.opts.profile = "prod"
+	m.region = "eu-west-1"
+	m.updateTitle()
+	if m.list.Title != "test-bucket (profile: prod, region: eu-west-1)" {
+		t.Errorf("title = %q", m.list.Title)
+	}
+}
+
+func TestNextPageAppendsSecondTruncatedListing(t *testing.T) {
+	var tokens []string
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("continuation-token")
+		tokens = append(tokens, token)
+		switch token {
+		case "":
+			w.Write([]byte(`<ListBucketResult><Name>test-bucket</Name><IsTruncated>true</IsTruncated><NextContinuationToken>page-2</NextContinuationToken><Contents><Key>a.txt</Key><Size>1</Size></Contents></ListBucketResult>`))
+		case "page-2":
+			w.Write([]byte(`<ListBucketResult><Name>test-bucket</Name><IsTruncated>true</IsTruncated><NextContinuationToken>page-3</NextContinuationToken><Contents><Key>b.txt</Key><Size>1</Size></Contents></ListBucketResult>`))
+		default:
+			t.Errorf("unexpected continuation token %q", token)
+		}
+	})
+
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	updated, _ := m.Update(m.loadItems())
+	m = updated.(Model)
+	if !strings.Contains(m.statusMsg, "press 'n' for next page") {
+		t.Errorf("status = %q, want the next page hint", m.statusMsg)
+	}
+	// Go to the network rather than waiting on the background prefetch.
+	m.cancelPrefetch()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	updated, _ = m.Update(operationResult(t, cmd))
+	m = updated.(Model)
+
+	var keys []string
+	for _, it := range m.list.Items() {
+		keys = append(keys, it.(item).key)
+	}
+	if strings.Join(keys, ",") != "a.txt,b.txt" {
+		t.Errorf("items = %v, want both pages", keys)
+	}
+	if strings.Join(tokens, ",") != ",page-2" {
+		t.Errorf("requests used tokens %q, want the first page then page-2", tokens)
+	}
+	if !m.hasMoreItems || m.nextPageToken == nil || *m.nextPageToken != "page-3" {
+		t.Errorf("expected the token to advance to page-3")
+	}
+}
+
+func TestSpinnerRunsOnlyWhileLoading(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.currentPrefix = "logs/"
+	m.updateTitle()
+	view := m.View()
+	if !strings.Contains(view, "test-bucket/logs/") || !strings.Contains(view, "Loading...") {
+		t.Errorf("expected the title above the loading indicator, got %q", view)
+	}
+
+	tick := m.spinner.Tick()
+	updated, cmd := m.Update(tick)
+	m = updated.(Model)
+	if cmd == nil {
+		t.Errorf("expected the spinner to keep ticking while loading")
+	}
+
+	updated, _ = m.Update(itemsLoadedMsg{})
+	m = updated.(Model)
+	if _, cmd = m.Update(m.spinner.Tick()); cmd != nil {
+		t.Errorf("expected the spinner to stop once the items loaded")
+	}
+}
+
+func TestListingSummary(t *testing.T) {
+	items := []list.Item{
+		item{key: "logs/", displayKey: "logs/", isDir: true},
+		item{key: "a.txt", displayKey: "a.txt", size: 1_000_000_000},
+		item{key: "tmp/", displayKey: "tmp/", isDir: true},
+		item{key: "b.txt", displayKey: "b.txt", size: 300_000_000},
+	}
+	if got, want := listingSummary(items, false), "2 directories, 2 objects, 1.3 GB"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	if got, want := listingSummary(items, true), "2 directories, 2 objects, ≥1.3 GB"; got != want {
+		t.Errorf("partial summary = %q, want %q", got, want)
+	}
+	if got, want := listingSummary(items[1:2], false), "1 object, 1.0 GB"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+	if got, want := listingSummary(items[:1], false), "1 directory"; got != want {
+		t.Errorf("summary = %q, want %q", got, want)
+	}
+}
+
+func TestSelectionIndex(t *testing.T) {
+	items := []list.Item{item{key: "a"}, item{key: "b"}, item{key: "c"}}
+	tests := []struct {
+		key      string
+		fallback int
+		want     int
+	}{
+		{"b", 0, 1},
+		{"gone", 1, 1},
+		{"gone", 5, 2},
+		{"gone", -1, 0},
+	}
+	for _, tt := range tests {
+		if got := selectionIndex(items, tt.key, tt.fallback); got != tt.want {
+			t.Errorf("selectionIndex(%q, %d) = %d, want %d", tt.key, tt.fallback, got, tt.want)
+		}
+	}
+	if got := selectionIndex(nil, "a", 3); got != 0 {
+		t.Errorf("empty list selection = %d, want 0", got)
+	}
+}
+
+func TestReloadKeepsCursor(t *testing.T) {
+	load := func(m Model, keys ...string) Model {
+		var items []list.Item
+		for _, k := range keys {
+			items = append(items, item{key: k, displayKey: k})
+		}
+		updated, _ := m.Update(itemsLoadedMsg{items: items})
+		return updated.(Model)
+	}
+	m := initialModel("test-bucket", options{})
+	m.updateListSize(80, 40)
+	m = load(m, "a", "b", "c", "d")
+	m.list.Select(2)
+
+	m.reload()
+	m = load(m, "new", "a", "b", "c", "d")
+	if i := m.list.SelectedItem().(item); i.key != "c" {
+		t.Errorf("selected %q after reload, want c", i.key)
+	}
+
+	m.reload()
+	m = load(m, "new", "a", "b", "d")
+	if i := m.list.SelectedItem().(item); i.key != "d" {
+		t.Errorf("selected %q after c was deleted, want the item now at its index", i.key)
+	}
+}
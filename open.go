@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// objectOpenedMsg reports an object handed to its default application.
+type objectOpenedMsg struct {
+	key string
+}
+
+// openWithSystem downloads i to a temp file and opens it with the platform's default
+// application for its type. The opener returns once the application has started, so the
+// file is kept when s3n exits; the application may still be reading it. Stale files are
+// swept on a later start.
+func (m *Model) openWithSystem(i item) tea.Cmd {
+	if i.isDir {
+		return m.setStatus("Only objects can be opened")
+	}
+	opener, err := systemOpener()
+	if err != nil {
+		m.setErrorStatus(err.Error())
+		return nil
+	}
+	client, bucket, dir := m.client, m.bucketName, m.opts.tmpDir
+	return m.startTransfer("Downloading "+i.displayKey, i.size, func(ctx context.Context, p *transferProgress) tea.Msg {
+		obj, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(i.key)})
+		if err != nil {
+			return err
+		}
+		tmpFile, err := writeToTmpFile(dir, "", withProgress(obj.Body, p), bucket+"-"+i.key)
+		obj.Body.Close()
+		if err != nil {
+			return err
+		}
+		keepTmpFile(tmpFile)
+		// Output from the opener would be drawn over the UI, so it is discarded.
+		if err := exec.Command(opener[0], append(opener[1:], tmpFile)...).Run(); err != nil {
+			return fmt.Errorf("failed to open %s with %s: %w", i.key, opener[0], err)
+		}
+		return objectOpenedMsg{key: i.key}
+	})
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// operation is a running request that can take long enough to want cancelling with esc.
+// Only one runs at a time.
+type operation struct {
+	id      int
+	label   string
+	started time.Time
+	cancel  context.CancelFunc
+	// progress is set for transfers, which show a progress bar.
+	progress *transferProgress
+	// scanned is set for walks that count the objects they have visited so far.
+	scanned *atomic.Int64
+	// deleted is set for deletes, counting the objects removed so far.
+	deleted *atomic.Int64
+	// protected is set for deletes and transfers, which stop partway when cancelled, so
+	// the keys that would start another operation wait for them instead.
+	protected bool
+}
+
+// operationDoneMsg carries the result of an operation; results of cancelled or
+// superseded operations are dropped.
+type operationDoneMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// operationTickMsg redraws the elapsed time of a running operation.
+type operationTickMsg struct {
+	id int
+}
+
+// startOperation runs run in the background as the current operation, cancelling any previous one.
+func (m *Model) startOperation(label string, run func(ctx context.Context) tea.Msg) tea.Cmd {
+	if m.operation != nil && m.operation.protected {
+		// Keys wait for a protected operation, so this is some other path; say what stopped.
+		m.setErrorStatus(m.operation.cancelledStatus())
+	}
+	m.cancelOperation()
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.operationID++
+	id := m.operationID
+	m.operation = &operation{id: id, label: label, started: time.Now(), cancel: cancel}
+	return tea.Batch(
+		func() tea.Msg { return operationDoneMsg{id: id, msg: run(ctx)} },
+		operationTick(id),
+		m.spinner.Tick,
+	)
+}
+
+func operationTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return operationTickMsg{id: id} })
+}
+
+// cancelOperation stops the running operation, if any, and forgets it.
+func (m *Model) cancelOperation() {
+	if m.operation != nil {
+		m.operation.cancel()
+		m.operation = nil
+	}
+}
+
+// cancelledStatus says an operation was cancelled and, for deletes and transfers, how far
+// it got.
+func (o *operation) cancelledStatus() string {
+	switch {
+	case o.progress != nil:
+		return fmt.Sprintf("Cancelled: %s after %s", o.label, o.progress)
+	case o.deleted != nil:
+		return fmt.Sprintf("Cancelled: %s after %s", o.label, plural(int(o.deleted.Load()), "object", "objects"))
+	}
+	return "Cancelled: " + o.label
+}
+
+// busyRefusal returns the status shown when msg is the key of an action while a
+// protected operation runs, or "" when it is not. Moving around and filtering the listing
+// stay available; esc cancels the operation and quitting still quits.
+func (m *Model) busyRefusal(msg tea.KeyMsg) string {
+	if m.operation == nil || !m.operation.protected {
+		return ""
+	}
+	for name, b := range m.keys.keyActions() {
+		if name != "quit" && key.Matches(msg, *b) {
+			return fmt.Sprintf("Wait for %s to finish, or press %s to cancel it", m.operation.label, m.keys.Dismiss.Help().Key)
+		}
+	}
+	return ""
+}
+
+// finishOperation reports whether msg is the result of the running operation, clearing it if so.
+func (m *Model) finishOperation(msg operationDoneMsg) bool {
+	if m.operation == nil || m.operation.id != msg.id {
+		return false
+	}
+	m.operation.cancel()
+	m.operation = nil
+	return true
+}
+
+// abortOperation cancels the running operation on the user's request and puts the
+// UI back on the listing that is still shown.
+func (m *Model) abortOperation() tea.Cmd {
+	status := m.operation.cancelledStatus()
+	m.cancelOperation()
+	if m.loading || m.loadingMore {
+		m.bucketName = m.shownBucket
+		m.currentPrefix = m.shownPrefix
+		m.searchTerm = m.shownSearchTerm
+		m.updateTitle()
+		m.loading = false
+		m.loadingMore = false
+		m.historyPending = false
+		m.selectKey = ""
+	}
+	return m.setStatus(status)
+}
+
+// operationView describes the running operation and how long it has been going. The
+// spinner's ticks redraw it, which keeps a transfer's progress bar current.
+func (m Model) operationView() string {
+	if m.operation == nil {
+		return ""
+	}
+	elapsed := time.Since(m.operation.started).Truncate(time.Second)
+	if p := m.operation.progress; p != nil {
+		return fmt.Sprintf("%s %s... %s %s %s (%s to cancel)", m.spinner.View(), m.operation.label, m.progressBar.ViewAs(p.fraction()), p, elapsed, m.keys.Dismiss.Help().Key)
+	}
+	if n := m.operation.scanned; n != nil {
+		return fmt.Sprintf("%s %s... %s objects %s (%s to cancel)", m.spinner.View(), m.operation.label, humanize.Comma(n.Load()), elapsed, m.keys.Dismiss.Help().Key)
+	}
+	return fmt.Sprintf("%s %s... %s (%s to cancel)", m.spinner.View(), m.operation.label, elapsed, m.keys.Dismiss.Help().Key)
+}
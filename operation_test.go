@@ -0,0 +1,224 @@
+// ABOUTME: Tests for cancellable operations in operation.go.
+// ABOUTME: Covers cancelling with esc or by navigating away, deletes and transfers waiting, late results and quitting.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// operationResult runs the operation started by cmd, without waiting for its ticks.
+func operationResult(t *testing.T, cmd tea.Cmd) operationDoneMsg {
+	t.Helper()
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatalf("expected an operation batch")
+	}
+	done, ok := batch[0]().(operationDoneMsg)
+	if !ok {
+		t.Fatalf("expected the operation to run first in the batch")
+	}
+	return done
+}
+
+func TestEscCancelsListingAndKeepsShownItems(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.list.SetItems([]list.Item{item{key: "logs/", displayKey: "logs", isDir: true}})
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.operation == nil || !m.loading {
+		t.Fatalf("expected entering a directory to start a cancellable listing")
+	}
+	if view := m.View(); !strings.Contains(view, "Listing s3://test-bucket/logs/") || !strings.Contains(view, "esc to cancel") {
+		t.Errorf("expected a progress indicator, got %q", view)
+	}
+	id := m.operation.id
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.operation != nil || m.loading || m.currentPrefix != "" {
+		t.Fatalf("operation=%v loading=%v prefix=%q, want the listing cancelled and the root restored", m.operation, m.loading, m.currentPrefix)
+	}
+	if !strings.HasPrefix(m.statusMsg, "Cancelled: Listing") {
+		t.Errorf("status = %q, want a cancellation message", m.statusMsg)
+	}
+
+	updated, _ = m.Update(operationDoneMsg{id: id, msg: itemsLoadedMsg{items: []list.Item{item{key: "logs/a"}}}})
+	if n := len(updated.(Model).list.Items()); n != 1 || updated.(Model).list.Items()[0].(item).key != "logs/" {
+		t.Errorf("expected the cancelled listing's result to be dropped")
+	}
+}
+
+func TestSupersededListingIsCancelledAndDiscarded(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		prefix := r.URL.Query().Get("prefix")
+		fmt.Fprintf(w, `<ListBucketResult><Name>test-bucket</Name><Contents><Key>%sfile.txt</Key><Size>1</Size></Contents></ListBucketResult>`, prefix)
+	})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	m.loading = false
+	m.list.SetItems([]list.Item{item{key: "logs/", displayKey: "logs", isDir: true}})
+
+	updated, stale := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	current := m.jumpTo("test-bucket", "data/")
+
+	staleResult := operationResult(t, stale)
+	if _, ok := staleResult.msg.(errorMsg); !ok {
+		t.Errorf("expected the superseded request to be cancelled, got %#v", staleResult.msg)
+	}
+	updated, _ = m.Update(staleResult)
+	m = updated.(Model)
+	if !m.loading || m.errMsg != "" {
+		t.Fatalf("expected the superseded result to be dropped, loading=%v err=%q", m.loading, m.errMsg)
+	}
+
+	updated, _ = m.Update(operationResult(t, current))
+	m = updated.(Model)
+	if got := keysOf(m.list.Items()); len(got) != 1 || got[0] != "data/file.txt" {
+		t.Errorf("expected the newer listing to be shown, got %v", got)
+	}
+}
+
+func TestQuitCancelsRequestsInFlight(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("expected ctrl+c to quit")
+	}
+	if m.ctx.Err() == nil {
+		t.Errorf("expected quitting to cancel the program context")
+	}
+}
+
+func TestNavigatingWaitsForADelete(t *testing.T) {
+	client := newFakeS3(map[string]string{"logs/a.log": "a", "logs/b.log": "b"})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	m.loading = false
+	m.list.SetItems([]list.Item{item{key: "logs/", displayKey: "logs", isDir: true}})
+	cmd := m.startDeletePrefix("logs/", 0)
+	id := m.operation.id
+
+	for _, k := range []tea.KeyMsg{{Type: tea.KeyCtrlR}, {Type: tea.KeyEnter}, {Type: tea.KeyRunes, Runes: []rune("n")}} {
+		updated, _ := m.Update(k)
+		m = updated.(Model)
+		if m.operation == nil || m.operation.id != id {
+			t.Fatalf("%s: expected the delete to keep running", k)
+		}
+		if !strings.HasPrefix(m.statusMsg, "Wait for Deleting s3://test-bucket/logs/ to finish") {
+			t.Errorf("%s: status = %q, want to be told to wait", k, m.statusMsg)
+		}
+	}
+	m.statusMsg = ""
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if m = updated.(Model); m.statusMsg != "" {
+		t.Errorf("expected moving the cursor to be allowed, got %q", m.statusMsg)
+	}
+
+	updated, _ = m.Update(operationResult(t, cmd))
+	if m = updated.(Model); m.operation != nil && m.operation.protected {
+		t.Errorf("expected the delete to finish, got %q running", m.operation.label)
+	}
+	if client.called("DeleteObjects") != 1 || len(client.objects) != 0 {
+		t.Errorf("expected both objects deleted in one request, got %d calls, %d left", client.called("DeleteObjects"), len(client.objects))
+	}
+}
+
+func TestRetryWaitsForADelete(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.client = newFakeS3(map[string]string{"logs/a.log": "a"})
+	m.loading = false
+	m.startDeletePrefix("logs/", 0)
+	id := m.operation.id
+	m.errMsg = "failed to load s3://test-bucket/"
+	m.errRetry = func() tea.Msg { return deletePrefixMsg{prefix: "other/"} }
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+	if m.operation == nil || m.operation.id != id {
+		t.Fatalf("expected the delete to keep running")
+	}
+	if m.errRetry == nil {
+		t.Errorf("expected the retry to wait, with the error panel kept")
+	}
+	if !strings.HasPrefix(m.statusMsg, "Wait for Deleting s3://test-bucket/logs/ to finish") {
+		t.Errorf("status = %q, want to be told to wait", m.statusMsg)
+	}
+}
+
+func TestCancelledDeleteSaysHowManyWereDeleted(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.client = newFakeS3(map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"})
+	m.loading = false
+	m.selected = map[string]bool{"a.txt": true, "b.txt": true}
+	cmd := m.startDeleteSelected()
+	// Run the delete, then cancel as if esc had come before its result.
+	operationResult(t, cmd)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if want := "Cancelled: Deleting 2 selected objects after 2 objects"; m.statusMsg != want {
+		t.Errorf("status = %q, want %q", m.statusMsg, want)
+	}
+}
+
+func TestCancelledTransferSaysHowFarItGot(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.startTransfer("Uploading report.csv", 2048, func(ctx context.Context, p *transferProgress) tea.Msg { return nil })
+	m.operation.progress.done.Store(1024)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if m = updated.(Model); m.operation == nil {
+		t.Fatalf("expected reloading to wait for the upload")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if want := "Cancelled: Uploading report.csv after 1.0 kB / 2.0 kB"; m.statusMsg != want {
+		t.Errorf("status = %q, want %q", m.statusMsg, want)
+	}
+}
+
+// failingOnce answers the first request with AccessDenied and every later one with body.
+func failingOnce(body string) http.HandlerFunc {
+	var calls atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+			return
+		}
+		w.Write([]byte(body))
+	}
+}
+
+// retryFailedOperation shows the error the operation started by cmd fails with, retries
+// it from the error panel and returns the operation the retry starts.
+func retryFailedOperation(t *testing.T, m Model, cmd tea.Cmd) (Model, tea.Cmd) {
+	t.Helper()
+	updated, _ := m.Update(operationResult(t, cmd))
+	m = updated.(Model)
+	if m.errRetry == nil {
+		t.Fatalf("expected the error panel to offer a retry, err %q", m.errMsg)
+	}
+	updated, retry := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	m = updated.(Model)
+	updated, cmd = m.Update(retry())
+	m = updated.(Model)
+	if m.operation == nil {
+		t.Fatalf("expected the retry to start a new operation")
+	}
+	return m, cmd
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// options holds the settings that can be changed from the command line or the config file.
+type options struct {
+	pager    string
+	tmpDir   string
+	noImages bool
+	debug    bool
+	logFile  string
+	logLevel string
+	head     string
+	list     bool
+	long     bool
+	dryRun   bool
+	// readOnly disables every action that changes S3.
+	readOnly bool
+	// stream pipes objects into the pager's stdin instead of a temp file.
+	stream   bool
+	maxItems int
+	// maxAttempts is how many times a failing S3 request is sent before giving up.
+	maxAttempts int
+	// pageSize is how many keys a listing page asks for.
+	pageSize int
+	// allPages keeps listing pages in the background and appends each as it arrives.
+	allPages bool
+	// noSignRequest sends requests anonymously, for public buckets.
+	noSignRequest bool
+	// endpoint overrides the S3 endpoint; pathStyle addresses buckets as endpoint/bucket.
+	endpoint  string
+	pathStyle bool
+	region    string
+	profile   string
+	// roleARN is a role assumed with the configured credentials, e.g. in another account;
+	// roleSessionName and externalID are passed along when assuming it.
+	roleARN         string
+	roleSessionName string
+	externalID      string
+	// requestPayer accepts the request charges of requester pays buckets.
+	requestPayer bool
+	// maxDownloadRate caps how many bytes per second objects are downloaded at; 0 is unlimited.
+	maxDownloadRate uint64
+	// sse and sseKMSKeyID encrypt the objects s3n puts; empty leaves it to the bucket.
+	sse         string
+	sseKMSKeyID string
+
+	// version prints the build version and exits.
+	version bool
+
+	// keyOverrides remaps key bindings, from the config file.
+	keyOverrides map[string][]string
+}
+
+// parseFlags parses the command-line flags and returns the remaining positional arguments.
+func parseFlags(args []string) (options, []string, error) {
+	var opts options
+	fs := flag.NewFlagSet("s3n", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: s3n [flags] [bucket-name | s3://bucket/prefix/]\n       s3n --list [--long] <bucket-name> [prefix]\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.pager, "pager", "", "command used to view objects, may include arguments (default $PAGER, then less or more)")
+	fs.StringVar(&opts.tmpDir, "tmp-dir", "", "directory for downloaded objects (default the system temp directory)")
+	fs.BoolVar(&opts.stream, "stream", false, "pipe objects into the pager as they download instead of writing them to a temp file first")
+	fs.BoolVar(&opts.noImages, "no-images", false, "open images in the pager instead of previewing them inline")
+	fs.BoolVar(&opts.debug, "debug", false, "write debug logs to --log-file (also enabled by DEBUG=true)")
+	fs.StringVar(&opts.logFile, "log-file", "log.txt", "file debug logs are appended to")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "log deletes, uploads, copies, moves, restores and tag changes instead of performing them")
+	fs.BoolVar(&opts.readOnly, "read-only", false, "hide and refuse every action that changes S3: edits, uploads, deletes, copies, moves, restores, folders and tag changes")
+	fs.IntVar(&opts.maxItems, "max-items", defaultMaxItems, "most objects a recursive operation may visit before it stops and asks (0 for no limit)")
+	fs.IntVar(&opts.maxAttempts, "max-attempts", defaultMaxAttempts, "times a request is sent when S3 throttles, times out or fails with a server error, before the error is shown")
+	fs.BoolVar(&opts.allPages, "all-pages", false, "keep listing the pages of a directory in the background, appending each as it arrives, up to --max-items (esc stops)")
+	fs.IntVar(&opts.pageSize, "page-size", defaultPageSize, fmt.Sprintf("keys listed per page, from 1 to %d", maxPageSize))
+	fs.BoolVar(&opts.noSignRequest, "no-sign-request", false, "access public buckets without credentials")
+	fs.StringVar(&opts.endpoint, "endpoint", "", "S3 endpoint URL, e.g. http://localhost:4566 for LocalStack (default $S3N_ENDPOINT, $AWS_ENDPOINT_URL_S3, $AWS_ENDPOINT_URL, then AWS)")
+	fs.BoolVar(&opts.pathStyle, "path-style", false, "use path-style bucket addressing, needed by most S3-compatible servers (default $S3N_PATH_STYLE)")
+	fs.StringVar(&opts.region, "region", "", "AWS region (default from AWS_REGION or the profile)")
+	fs.StringVar(&opts.profile, "profile", "", "named profile from the shared AWS config and credentials files (default $AWS_PROFILE)")
+	fs.StringVar(&opts.roleARN, "role-arn", "", "IAM role to assume with the configured credentials, e.g. to browse a bucket in another account")
+	fs.StringVar(&opts.roleSessionName, "role-session-name", defaultRoleSessionName, "session name recorded when assuming --role-arn")
+	fs.StringVar(&opts.externalID, "external-id", "", "external ID the role given with --role-arn requires, if any")
+	fs.BoolVar(&opts.requestPayer, "request-payer", false, "accept the charges of requester pays buckets, which refuse requests without it")
+	fs.Func("max-download-rate", "most bytes per second to download objects at, e.g. 5MB or 512KiB (default unlimited)", func(s string) error {
+		n, err := parseRate(s)
+		opts.maxDownloadRate = n
+		return err
+	})
+	fs.StringVar(&opts.sse, "sse", "", "encrypt uploaded and edited objects server-side with AES256, aws:kms or aws:kms:dsse (default the bucket's encryption)")
+	fs.StringVar(&opts.sseKMSKeyID, "sse-kms-key-id", "", "KMS key to encrypt with when --sse is aws:kms or aws:kms:dsse (default the AWS managed key)")
+	fs.StringVar(&opts.head, "head", "", "print the metadata of `key` as JSON and exit: 0 if it exists, 1 if not, 2 on other errors")
+	fs.BoolVar(&opts.list, "list", false, "print every key under the optional prefix argument, one per line, and exit")
+	fs.BoolVar(&opts.list, "l", false, "shorthand for --list")
+	fs.BoolVar(&opts.long, "long", false, "with --list, also print each object's size in bytes and modification time (UTC)")
+	fs.BoolVar(&opts.version, "version", false, "print the version, commit and Go version, and exit")
+	fs.BoolVar(&opts.version, "v", false, "shorthand for --version")
+	fs.StringVar(&opts.logLevel, "log-level", "", "minimum level logged: debug, info, warn or error (default $S3N_LOG_LEVEL, then info; setting it enables logging)")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, nil, err
+	}
+	if err := envDefaults(fs, &opts); err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		return opts, nil, err
+	}
+	if opts.maxItems < 0 {
+		err := fmt.Errorf("--max-items must not be negative")
+		fmt.Fprintln(fs.Output(), err)
+		return opts, nil, err
+	}
+	if opts.maxAttempts < 1 {
+		err := fmt.Errorf("--max-attempts must be at least 1")
+		fmt.Fprintln(fs.Output(), err)
+		return opts, nil, err
+	}
+	if err := validatePageSize(opts.pageSize); err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		return opts, nil, err
+	}
+	if err := validateSSE(opts.sse, opts.sseKMSKeyID); err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		return opts, nil, err
+	}
+	if err := validateRole(fs, opts); err != nil {
+		fmt.Fprintln(fs.Output(), err)
+		return opts, nil, err
+	}
+	return opts, fs.Args(), nil
+}
+
+// validatePageSize rejects page sizes S3 would not honor.
+func validatePageSize(n int) error {
+	if n < 1 || n > maxPageSize {
+		return fmt.Errorf("--page-size must be between 1 and %d, got %d", maxPageSize, n)
+	}
+	return nil
+}
+
+// defaultRoleSessionName identifies s3n's sessions in CloudTrail when --role-arn is used.
+const defaultRoleSessionName = "s3n"
+
+// validateRole rejects the assume-role flags without a role to assume, and a role with
+// anonymous access, which has no credentials to assume it with.
+func validateRole(fs *flag.FlagSet, opts options) error {
+	if opts.roleARN != "" {
+		if opts.noSignRequest {
+			return fmt.Errorf("--role-arn cannot be used with --no-sign-request")
+		}
+		return nil
+	}
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err == nil && (f.Name == "role-session-name" || f.Name == "external-id") {
+			err = fmt.Errorf("--%s needs --role-arn", f.Name)
+		}
+	})
+	return err
+}
+
+// endpointVars are the environment variables an endpoint is taken from, most specific first.
+var endpointVars = []string{"S3N_ENDPOINT", "AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"}
+
+// endpointFromEnv returns the first endpoint set in endpointVars, or "" to let the SDK
+// resolve the AWS endpoint. AWS_IGNORE_CONFIGURED_ENDPOINT_URLS=true skips the AWS_
+// variables, as it does for the AWS CLI.
+func endpointFromEnv() string {
+	ignoreAWS, _ := strconv.ParseBool(os.Getenv("AWS_IGNORE_CONFIGURED_ENDPOINT_URLS"))
+	for _, name := range endpointVars {
+		if ignoreAWS && strings.HasPrefix(name, "AWS_") {
+			continue
+		}
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// envDefaults fills in settings that were not given as flags from S3N_* environment variables.
+func envDefaults(fs *flag.FlagSet, opts *options) error {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if !set["endpoint"] {
+		opts.endpoint = endpointFromEnv()
+	}
+	if v := os.Getenv("S3N_LOG_LEVEL"); !set["log-level"] && v != "" {
+		opts.logLevel = v
+	}
+	if v := os.Getenv("S3N_PATH_STYLE"); !set["path-style"] && v != "" {
+		pathStyle, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid S3N_PATH_STYLE %q: %w", v, err)
+		}
+		opts.pathStyle = pathStyle
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// ABOUTME: Tests for command-line flag parsing in options.go.
+// ABOUTME: Covers flag values, positional arguments and environment defaults.
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlagsKeepsBucketArgument(t *testing.T) {
+	opts, args, err := parseFlags([]string{"--pager", "bat --style=plain", "my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.pager != "bat --style=plain" {
+		t.Errorf("expected pager flag to be parsed, got %q", opts.pager)
+	}
+	if !reflect.DeepEqual(args, []string{"my-bucket"}) {
+		t.Errorf("expected bucket argument to remain, got %v", args)
+	}
+}
+
+func TestParseFlagsDebugLogging(t *testing.T) {
+	opts, _, err := parseFlags([]string{"my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.debug || opts.logFile != "log.txt" {
+		t.Errorf("expected debug off with log.txt as default destination, got %+v", opts)
+	}
+
+	opts, _, err = parseFlags([]string{"--debug", "--log-file", "/tmp/s3n.log", "my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.debug || opts.logFile != "/tmp/s3n.log" {
+		t.Errorf("expected --debug and --log-file to be honored, got %+v", opts)
+	}
+}
+
+func TestParseFlagsLogLevel(t *testing.T) {
+	opts, _, err := parseFlags([]string{"--log-level", "debug", "bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.logLevel != "debug" {
+		t.Errorf("logLevel = %q, want debug", opts.logLevel)
+	}
+
+	t.Setenv("S3N_LOG_LEVEL", "warn")
+	if opts, _, _ := parseFlags([]string{"bucket"}); opts.logLevel != "warn" {
+		t.Errorf("logLevel = %q, want warn from S3N_LOG_LEVEL", opts.logLevel)
+	}
+	if opts, _, _ := parseFlags([]string{"--log-level", "error", "bucket"}); opts.logLevel != "error" {
+		t.Errorf("logLevel = %q, want the flag to win over S3N_LOG_LEVEL", opts.logLevel)
+	}
+}
+
+func TestParseFlagsMaxItems(t *testing.T) {
+	opts, _, err := parseFlags([]string{"my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.maxItems != defaultMaxItems {
+		t.Errorf("maxItems = %d, want the default %d", opts.maxItems, defaultMaxItems)
+	}
+	if _, _, err := parseFlags([]string{"--max-items", "-1", "my-bucket"}); err == nil {
+		t.Errorf("expected a negative cap to be rejected")
+	}
+}
+
+func TestParseFlagsPageSize(t *testing.T) {
+	opts, _, err := parseFlags([]string{"my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.pageSize != defaultPageSize {
+		t.Errorf("pageSize = %d, want the default %d", opts.pageSize, defaultPageSize)
+	}
+	if opts, _, err := parseFlags([]string{"--page-size", "1000", "my-bucket"}); err != nil || opts.pageSize != 1000 {
+		t.Errorf("expected the S3 maximum to be accepted, got %d, %v", opts.pageSize, err)
+	}
+	for _, size := range []string{"0", "-5", "1001"} {
+		if _, _, err := parseFlags([]string{"--page-size", size, "my-bucket"}); err == nil {
+			t.Errorf("expected --page-size %s to be rejected", size)
+		}
+	}
+}
+
+func TestParseFlagsMaxDownloadRate(t *testing.T) {
+	if opts, _, err := parseFlags([]string{"my-bucket"}); err != nil || opts.maxDownloadRate != 0 {
+		t.Errorf("expected downloads to be unlimited by default, got %d, %v", opts.maxDownloadRate, err)
+	}
+	if opts, _, err := parseFlags([]string{"--max-download-rate", "5MB", "my-bucket"}); err != nil || opts.maxDownloadRate != 5_000_000 {
+		t.Errorf("expected 5MB to be 5000000 bytes per second, got %d, %v", opts.maxDownloadRate, err)
+	}
+	if _, _, err := parseFlags([]string{"--max-download-rate", "fast", "my-bucket"}); err == nil {
+		t.Error("expected an invalid rate to be rejected")
+	}
+}
+
+func TestParseFlagsRole(t *testing.T) {
+	opts, _, err := parseFlags([]string{"--role-arn", "arn:aws:iam::123456789012:role/reader", "--external-id", "xyz", "my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.roleARN != "arn:aws:iam::123456789012:role/reader" || opts.externalID != "xyz" || opts.roleSessionName != defaultRoleSessionName {
+		t.Errorf("got role %q, external ID %q, session %q", opts.roleARN, opts.externalID, opts.roleSessionName)
+	}
+	for _, args := range [][]string{
+		{"--external-id", "xyz", "my-bucket"},
+		{"--role-session-name", "me", "my-bucket"},
+		{"--role-arn", "arn:aws:iam::123456789012:role/reader", "--no-sign-request", "my-bucket"},
+	} {
+		if _, _, err := parseFlags(args); err == nil {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseFlagsRequestPayer(t *testing.T) {
+	if opts, _, err := parseFlags([]string{"my-bucket"}); err != nil || opts.requestPayer {
+		t.Errorf("expected the bucket owner to pay by default, got %v, %v", opts.requestPayer, err)
+	}
+	if opts, _, err := parseFlags([]string{"--request-payer", "my-bucket"}); err != nil || !opts.requestPayer {
+		t.Errorf("expected --request-payer to be set, got %v, %v", opts.requestPayer, err)
+	}
+}
+
+func TestParseFlagsVersion(t *testing.T) {
+	for _, flag := range []string{"--version", "-v"} {
+		opts, _, err := parseFlags([]string{flag})
+		if err != nil || !opts.version {
+			t.Errorf("expected %s to ask for the version, got %v, %v", flag, opts.version, err)
+		}
+	}
+}
+
+func TestParseFlagsEndpointFromEnvironment(t *testing.T) {
+	t.Setenv("S3N_ENDPOINT", "http://localhost:4566")
+	t.Setenv("S3N_PATH_STYLE", "true")
+	opts, _, err := parseFlags([]string{"my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.endpoint != "http://localhost:4566" || !opts.pathStyle {
+		t.Errorf("endpoint=%q pathStyle=%v, want the environment values", opts.endpoint, opts.pathStyle)
+	}
+
+	opts, _, err = parseFlags([]string{"--endpoint", "http://minio:9000", "--path-style=false", "my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.endpoint != "http://minio:9000" || opts.pathStyle {
+		t.Errorf("endpoint=%q pathStyle=%v, want the flags to win", opts.endpoint, opts.pathStyle)
+	}
+}
+
+func TestEndpointPrecedence(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want string
+	}{
+		{"flag", []string{"--endpoint", "http://flag"}, map[string]string{"S3N_ENDPOINT": "http://s3n", "AWS_ENDPOINT_URL_S3": "http://aws-s3", "AWS_ENDPOINT_URL": "http://aws"}, "http://flag"},
+		{"S3N_ENDPOINT", nil, map[string]string{"S3N_ENDPOINT": "http://s3n", "AWS_ENDPOINT_URL_S3": "http://aws-s3", "AWS_ENDPOINT_URL": "http://aws"}, "http://s3n"},
+		{"AWS_ENDPOINT_URL_S3", nil, map[string]string{"AWS_ENDPOINT_URL_S3": "http://aws-s3", "AWS_ENDPOINT_URL": "http://aws"}, "http://aws-s3"},
+		{"AWS_ENDPOINT_URL", nil, map[string]string{"AWS_ENDPOINT_URL": "http://aws"}, "http://aws"},
+		{"SDK default", nil, nil, ""},
+		{"ignored AWS variables", nil, map[string]string{"AWS_ENDPOINT_URL": "http://aws", "AWS_IGNORE_CONFIGURED_ENDPOINT_URLS": "true"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range append(endpointVars, "AWS_IGNORE_CONFIGURED_ENDPOINT_URLS") {
+				t.Setenv(name, tt.env[name])
+			}
+			opts, _, err := parseFlags(append(tt.args, "my-bucket"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.endpoint != tt.want {
+				t.Errorf("endpoint = %q, want %q", opts.endpoint, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlagsWithoutEndpointUsesAWS(t *testing.T) {
+	t.Setenv("S3N_ENDPOINT", "")
+	t.Setenv("AWS_ENDPOINT_URL_S3", "")
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	t.Setenv("S3N_PATH_STYLE", "")
+	opts, _, err := parseFlags([]string{"my-bucket"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.endpoint != "" || opts.pathStyle {
+		t.Errorf("endpoint=%q pathStyle=%v, want no override", opts.endpoint, opts.pathStyle)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pickerKind identifies what the picker list overlaying the object list is choosing.
+type pickerKind int
+
+const (
+	pickerNone pickerKind = iota
+	pickerBookmarks
+	pickerBuckets
+	pickerFind
+	pickerVersions
+	pickerRecents
+)
+
+// openPicker replaces the object list with a list of choices until one is picked or esc is pressed.
+func (m *Model) openPicker(kind pickerKind, title string, items []list.Item) {
+	delegate := list.NewDefaultDelegate()
+	delegate.SetSpacing(1)
+
+	l := list.New(items, delegate, 0, 0)
+	l.Title = title
+	l.Styles.Title = m.list.Styles.Title
+	l.SetStatusBarItemName("entry", "entries")
+	l.DisableQuitKeybindings()
+	if kind == pickerBookmarks {
+		l.AdditionalShortHelpKeys = func() []key.Binding { return []key.Binding{m.keys.Delete} }
+	}
+	if m.lastWindowSize.Width > 0 {
+		h, v := docStyle.GetFrameSize()
+		l.SetSize(m.lastWindowSize.Width-h, m.lastWindowSize.Height-v-1)
+	}
+	m.picker = l
+	m.pickerKind = kind
+}
+
+func (m *Model) closePicker() {
+	m.pickerKind = pickerNone
+}
+
+// updatePicker handles a key press while the picker is open.
+func (m Model) updatePicker(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.picker.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, m.keys.Dismiss) && m.picker.FilterState() == list.Unfiltered:
+			if m.bucketName == "" {
+				// Nothing to go back to before a bucket is picked.
+				return m, nil
+			}
+			m.closePicker()
+			return m, nil
+		case key.Matches(msg, m.keys.Enter):
+			selected := m.picker.SelectedItem()
+			if selected == nil {
+				return m, nil
+			}
+			kind := m.pickerKind
+			m.closePicker()
+			return m.pickItem(kind, selected)
+		case key.Matches(msg, m.keys.Delete):
+			if selected := m.picker.SelectedItem(); selected != nil {
+				return m.removePickerItem(m.pickerKind, selected)
+			}
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.picker, cmd = m.picker.Update(msg)
+	return m, cmd
+}
+
+func (m Model) pickItem(kind pickerKind, selected list.Item) (Model, tea.Cmd) {
+	switch kind {
+	case pickerBookmarks:
+		b := selected.(bookmark)
+		cmd := m.jumpTo(b.Bucket, b.Prefix)
+
+		return m, cmd
+	case pickerBuckets:
+		if r, ok := selected.(recent); ok {
+			cmd := m.jumpTo(r.Bucket, r.Prefix)
+
+			return m, cmd
+		}
+		cmd := m.jumpTo(selected.(bucketEntry).name, "")
+
+		return m, cmd
+	case pickerFind:
+		cmd := m.viewObject(selected.(item), false)
+
+		return m, cmd
+	case pickerVersions:
+		cmd := m.openVersion(selected.(objectVersion))
+
+		return m, cmd
+	case pickerRecents:
+		r := selected.(recent)
+		cmd := m.jumpTo(r.Bucket, r.Prefix)
+
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) removePickerItem(kind pickerKind, selected list.Item) (Model, tea.Cmd) {
+	switch kind {
+	case pickerBookmarks:
+		cmd := m.removeBookmark(selected.(bookmark))
+
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) pickerView() string {
+	return lipgloss.JoinVertical(lipgloss.Top, docStyle.Render(m.picker.View()), m.footer())
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prefetchedPage is the next page of the shown listing, requested in the background
+// as soon as the current page arrives so that loading it with 'n' is instant.
+type prefetchedPage struct {
+	key    listingKey
+	token  string
+	cancel context.CancelFunc
+	msg    tea.Msg // the page's itemsLoadedMsg; nil while the request is in flight
+}
+
+type prefetchedMsg struct {
+	key   listingKey
+	token string
+	msg   tea.Msg
+}
+
+func (m Model) currentListing() listingKey {
+	return listingKey{bucket: m.bucketName, prefix: m.currentPrefix, searchTerm: m.searchTerm, contentTypes: m.showContentType}
+}
+
+// prefetchNext starts fetching the page after the one just loaded.
+func (m *Model) prefetchNext() tea.Cmd {
+	m.cancelPrefetch()
+	if m.nextPageToken == nil {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(m.ctx)
+	key, token := m.currentListing(), *m.nextPageToken
+	m.prefetch = &prefetchedPage{key: key, token: token, cancel: cancel}
+	page := *m
+	return func() tea.Msg {
+		return prefetchedMsg{key: key, token: token, msg: page.listItems(ctx)}
+	}
+}
+
+// cancelPrefetch abandons the prefetched page, e.g. because the user navigated away.
+func (m *Model) cancelPrefetch() {
+	if m.prefetch != nil {
+		m.prefetch.cancel()
+		m.prefetch = nil
+	}
+}
+
+// receivePrefetch keeps a prefetched page for later, or delivers it right away when
+// the user already asked for it.
+func (m *Model) receivePrefetch(msg prefetchedMsg) tea.Cmd {
+	p := m.prefetch
+	if p == nil || p.key != msg.key || p.token != msg.token {
+		return nil
+	}
+	if m.loadingMore {
+		m.cancelPrefetch()
+		return func() tea.Msg { return msg.msg }
+	}
+	if _, failed := msg.msg.(error); failed {
+		// Not worth an error panel yet; 'n' requests the page again.
+		m.cancelPrefetch()
+		return nil
+	}
+	p.msg = msg.msg
+	return nil
+}
+
+// nextPage loads the page after the shown one, from the prefetch when it matches.
+func (m *Model) nextPage() tea.Cmd {
+	p := m.prefetch
+	if p != nil && m.nextPageToken != nil && p.key == m.currentListing() && p.token == *m.nextPageToken {
+		if p.msg == nil {
+			// Still in flight; receivePrefetch delivers it since loadingMore is set.
+			return nil
+		}
+		msg := p.msg
+		m.cancelPrefetch()
+		return func() tea.Msg { return msg }
+	}
+	m.cancelPrefetch()
+	return m.startOperation("Loading the next page", m.listItems)
+}
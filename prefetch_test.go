@@ -0,0 +1,76 @@
+// ABOUTME: Tests for next-page prefetching in prefetch.go.
+// ABOUTME: Covers using a prefetched page, waiting for one in flight and dropping it on navigation.
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func loadedWithMore(t *testing.T) Model {
+	t.Helper()
+	m := initialModel("test-bucket", options{})
+	token := "page-2"
+	updated, cmd := m.Update(itemsLoadedMsg{items: []list.Item{item{key: "a", displayKey: "a"}}, hasMore: true, nextToken: &token})
+	m = updated.(Model)
+	if m.prefetch == nil || m.prefetch.token != "page-2" || cmd == nil {
+		t.Fatalf("expected the next page to be prefetched")
+	}
+	return m
+}
+
+func secondPage() itemsLoadedMsg {
+	return itemsLoadedMsg{items: []list.Item{item{key: "b", displayKey: "b"}}}
+}
+
+func TestNextPageUsesPrefetchedPage(t *testing.T) {
+	m := loadedWithMore(t)
+	updated, _ := m.Update(prefetchedMsg{key: m.currentListing(), token: "page-2", msg: secondPage()})
+	m = updated.(Model)
+
+	// nil client: a real listing request would panic.
+	m.client = nil
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if !m.loadingMore || cmd == nil {
+		t.Fatalf("expected n to load more")
+	}
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if n := len(m.list.Items()); n != 2 {
+		t.Errorf("expected the prefetched page to be appended, got %d items", n)
+	}
+}
+
+func TestNextPageWaitsForPrefetchInFlight(t *testing.T) {
+	m := loadedWithMore(t)
+	m.client = nil
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Fatalf("expected no second request while the prefetch is in flight")
+	}
+
+	updated, cmd = m.Update(prefetchedMsg{key: m.currentListing(), token: "page-2", msg: secondPage()})
+	m = updated.(Model)
+	updated, _ = m.Update(cmd())
+	if n := len(updated.(Model).list.Items()); n != 2 {
+		t.Errorf("expected the prefetched page to be appended on arrival, got %d items", n)
+	}
+}
+
+func TestNavigatingAwayDropsPrefetch(t *testing.T) {
+	m := loadedWithMore(t)
+	key := m.currentListing()
+	m.currentPrefix = "elsewhere/"
+	m.reload()
+	if m.prefetch != nil {
+		t.Fatalf("expected reload to cancel the prefetch")
+	}
+	updated, _ := m.Update(prefetchedMsg{key: key, token: "page-2", msg: secondPage()})
+	if updated.(Model).prefetch != nil {
+		t.Errorf("expected a late prefetch result to be ignored")
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind identifies what a single-line prompt in the footer is asking for.
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptBookmark
+	promptItemFilter
+	promptUpload
+	promptGoTo
+	promptRestore
+	promptFind
+	promptNewFolder
+	promptCopy
+	promptMove
+	promptDownload
+)
+
+// openPrompt shows a text input in the footer; submitPrompt receives its value on enter.
+func (m *Model) openPrompt(kind promptKind, prompt, value string) tea.Cmd {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.SetValue(value)
+	input.CursorEnd()
+	m.prompt = kind
+	m.promptInput = input
+	return m.promptInput.Focus()
+}
+
+// updatePrompt handles a key press while a prompt is open.
+func (m Model) updatePrompt(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		kind, value := m.prompt, m.promptInput.Value()
+		m.prompt = promptNone
+		return m.submitPrompt(kind, value)
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.prompt = promptNone
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+func (m Model) submitPrompt(kind promptKind, value string) (Model, tea.Cmd) {
+	switch kind {
+	case promptBookmark:
+		cmd := m.addBookmark(value)
+
+		return m, cmd
+	case promptItemFilter:
+		cmd := m.applyItemFilter(value)
+
+		return m, cmd
+	case promptUpload:
+		cmd := m.startUpload(value)
+
+		return m, cmd
+	case promptGoTo:
+		cmd := m.goTo(value)
+
+		return m, cmd
+	case promptRestore:
+		cmd := m.startRestore(value)
+
+		return m, cmd
+	case promptFind:
+		cmd := m.startFind(value)
+
+		return m, cmd
+	case promptNewFolder:
+		cmd := m.startNewFolder(value)
+
+		return m, cmd
+	case promptDownload:
+		cmd := m.startDownloadSelected(value)
+
+		return m, cmd
+	case promptCopy, promptMove:
+		cmd := m.startCopy(value, kind == promptMove)
+
+		return m, cmd
+	}
+	return m, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/dustin/go-humanize"
+	"golang.org/x/time/rate"
+)
+
+// parseRate reads a --max-download-rate such as "5MB" or "512KiB/s" as bytes per second.
+func parseRate(s string) (uint64, error) {
+	return humanize.ParseBytes(strings.TrimSuffix(s, "/s"))
+}
+
+// newRateLimiter returns a token bucket refilled at bytesPerSecond that holds a second's
+// worth of tokens, so a download can run at most one second ahead of the limit.
+func newRateLimiter(bytesPerSecond uint64) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(bytesPerSecond), int(bytesPerSecond))
+}
+
+// rateLimitedReader waits for a token per byte after each read, and reads no more than the
+// bucket holds at once so a single read can always be paid for.
+type rateLimitedReader struct {
+	ctx     context.Context
+	r       io.ReadCloser
+	limiter *rate.Limiter
+}
+
+func (r *rateLimitedReader) Read(b []byte) (int, error) {
+	if len(b) > r.limiter.Burst() {
+		b = b[:r.limiter.Burst()]
+	}
+	n, err := r.r.Read(b)
+	if n > 0 {
+		if werr := r.limiter.WaitN(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+func (r *rateLimitedReader) Close() error {
+	return r.r.Close()
+}
+
+// throttlingClient reads the bodies of the objects it gets no faster than limiter allows.
+// One limiter is shared by every download, so together they stay under the limit.
+type throttlingClient struct {
+	S3API
+	limiter *rate.Limiter
+}
+
+func (c throttlingClient) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	output, err := c.S3API.GetObject(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	output.Body = &rateLimitedReader{ctx: ctx, r: output.Body, limiter: c.limiter}
+	return output, nil
+}
+
+// withDownloadLimit wraps client to throttle downloads when --max-download-rate is set.
+// Without it the client is returned as it is and downloads run as fast as they can.
+func withDownloadLimit(client S3API, opts options) S3API {
+	if opts.maxDownloadRate == 0 {
+		return client
+	}
+	return throttlingClient{S3API: client, limiter: newRateLimiter(opts.maxDownloadRate)}
+}
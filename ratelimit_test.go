@@ -0,0 +1,72 @@
+// ABOUTME: Tests for throttling downloads to --max-download-rate in ratelimit.go.
+// ABOUTME: Covers parsing rates, the throughput of the rate-limited reader and which clients throttle.
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestParseRate(t *testing.T) {
+	for in, want := range map[string]uint64{"5MB": 5_000_000, "512KiB": 512 * 1024, "1MB/s": 1_000_000, "100": 100} {
+		if got, err := parseRate(in); err != nil || got != want {
+			t.Errorf("parseRate(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	if _, err := parseRate("fast"); err == nil {
+		t.Error("expected a rate that is not a size to be rejected")
+	}
+}
+
+func TestRateLimitedReaderDeliversConfiguredRate(t *testing.T) {
+	const perSecond = 20_000
+	body := io.NopCloser(bytes.NewReader(make([]byte, 3*perSecond)))
+	r := &rateLimitedReader{ctx: context.Background(), r: body, limiter: newRateLimiter(perSecond)}
+
+	start := time.Now()
+	n, err := io.Copy(io.Discard, r)
+	elapsed := time.Since(start)
+	if err != nil || n != 3*perSecond {
+		t.Fatalf("copied %d bytes, %v", n, err)
+	}
+	// The bucket starts full, so the first second's worth is read at once and the rest
+	// takes about two seconds.
+	if elapsed < 1800*time.Millisecond || elapsed > 2500*time.Millisecond {
+		t.Errorf("reading %d bytes at %d B/s took %s, want about 2s", n, perSecond, elapsed)
+	}
+}
+
+func TestRateLimitedReaderStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	body := io.NopCloser(bytes.NewReader(make([]byte, 10_000)))
+	r := &rateLimitedReader{ctx: ctx, r: body, limiter: newRateLimiter(1000)}
+	cancel()
+	if _, err := io.Copy(io.Discard, r); err == nil {
+		t.Error("expected a cancelled download to stop instead of waiting for tokens")
+	}
+}
+
+func TestWithDownloadLimit(t *testing.T) {
+	fake := newFakeS3(map[string]string{"a.txt": "hello"})
+	if c := withDownloadLimit(fake, options{}); c != S3API(fake) {
+		t.Errorf("expected no limit to leave the client as it is, got %T", c)
+	}
+
+	c := withDownloadLimit(fake, options{maxDownloadRate: 1_000_000})
+	output, err := c.GetObject(context.Background(), &s3.GetObjectInput{Bucket: aws.String("b"), Key: aws.String("a.txt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := output.Body.(*rateLimitedReader); !ok {
+		t.Fatalf("expected the body to be rate limited, got %T", output.Body)
+	}
+	if got, _ := io.ReadAll(output.Body); string(got) != "hello" {
+		t.Errorf("body = %q, want hello", got)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errReadOnly is what every write returns under --read-only.
+var errReadOnly = errors.New("read-only mode: s3n was started with --read-only, nothing is changed")
+
+// mutatingBindings are the actions that change objects in S3. Downloading, which only
+// writes local files, is not one of them.
+func mutatingBindings(k *keyMap) []*key.Binding {
+	return []*key.Binding{&k.Edit, &k.Add, &k.Delete, &k.Restore, &k.EditTags, &k.NewFolder, &k.Copy, &k.Move, &k.Upload}
+}
+
+// disableMutations turns off the mutating bindings, which hides them from the help too.
+func disableMutations(k *keyMap) {
+	for _, b := range mutatingBindings(k) {
+		b.SetEnabled(false)
+	}
+}
+
+// readOnlyRefusal returns the status shown when msg is the key of a disabled mutating
+// binding, or "" when it is not. Disabled bindings never match, so without this the key
+// would silently do nothing.
+func readOnlyRefusal(msg tea.KeyMsg, k *keyMap) string {
+	for _, b := range mutatingBindings(k) {
+		if slices.Contains(b.Keys(), msg.String()) {
+			return "Read-only mode: " + b.Help().Desc + " is disabled"
+		}
+	}
+	return ""
+}
+
+// readOnlyClient refuses every call that would change S3, in case an action that writes
+// is reached some other way than its key binding.
+type readOnlyClient struct {
+	S3API
+}
+
+func (readOnlyClient) PutObject(context.Context, *s3.PutObjectInput, ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	return nil, errReadOnly
+}
+
+func (readOnlyClient) CopyObject(context.Context, *s3.CopyObjectInput, ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	return nil, errReadOnly
+}
+
+func (readOnlyClient) DeleteObject(context.Context, *s3.DeleteObjectInput, ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	return nil, errReadOnly
+}
+
+func (readOnlyClient) DeleteObjects(context.Context, *s3.DeleteObjectsInput, ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	return nil, errReadOnly
+}
+
+func (readOnlyClient) RestoreObject(context.Context, *s3.RestoreObjectInput, ...func(*s3.Options)) (*s3.RestoreObjectOutput, error) {
+	return nil, errReadOnly
+}
+
+func (readOnlyClient) PutObjectTagging(context.Context, *s3.PutObjectTaggingInput, ...func(*s3.Options)) (*s3.PutObjectTaggingOutput, error) {
+	return nil, errReadOnly
+}
+
+// withReadOnly wraps client to refuse writes when --read-only is set.
+func withReadOnly(client S3API, opts options) S3API {
+	if !opts.readOnly {
+		return client
+	}
+	return readOnlyClient{S3API: client}
+}
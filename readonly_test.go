@@ -0,0 +1,85 @@
+// ABOUTME: Tests for --read-only in readonly.go.
+// ABOUTME: Covers hiding the mutating bindings, refusing their keys and a client that refuses writes.
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadOnlyRemovesMutatingBindings(t *testing.T) {
+	defaults := defaultKeyMap()
+	mutating := map[string]bool{}
+	for _, b := range mutatingBindings(&defaults) {
+		mutating[b.Help().Desc] = true
+	}
+
+	m := initialModel("test-bucket", options{})
+	var enabled int
+	for _, b := range m.list.AdditionalFullHelpKeys() {
+		if mutating[b.Help().Desc] && b.Enabled() {
+			enabled++
+		}
+	}
+	if enabled != len(mutating) {
+		t.Errorf("expected all %d mutating bindings without --read-only, got %d", len(mutating), enabled)
+	}
+
+	m = initialModel("test-bucket", options{readOnly: true})
+	for _, b := range m.list.AdditionalFullHelpKeys() {
+		if mutating[b.Help().Desc] && b.Enabled() {
+			t.Errorf("expected %q to be hidden in read-only mode", b.Help().Desc)
+		}
+	}
+	if !m.keys.ViewBuiltin.Enabled() || !m.keys.Download.Enabled() {
+		t.Error("expected viewing and downloading to stay available")
+	}
+	if !strings.HasSuffix(m.list.Title, "[READ-ONLY]") {
+		t.Errorf("title = %q, want it to say read-only", m.list.Title)
+	}
+}
+
+func TestReadOnlyRefusesMutatingKeys(t *testing.T) {
+	m := initialModel("test-bucket", options{readOnly: true})
+	m.loading = false
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{item{key: "a.txt", displayKey: "a.txt"}}})
+	m = updated.(Model)
+
+	for _, msg := range []tea.KeyMsg{{Type: tea.KeyCtrlD}, {Type: tea.KeyCtrlE}, {Type: tea.KeyRunes, Runes: []rune{'U'}}} {
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+		if !strings.HasPrefix(m.statusMsg, "Read-only mode: ") {
+			t.Errorf("%s: status %q, want a read-only notice", msg, m.statusMsg)
+		}
+		if m.confirmDelete || m.prompt != promptNone {
+			t.Errorf("%s: expected nothing to start in read-only mode", msg)
+		}
+	}
+}
+
+func TestReadOnlyClientRefusesWrites(t *testing.T) {
+	fake := newFakeS3(map[string]string{"a.txt": "hello"})
+	client := withReadOnly(fake, options{readOnly: true})
+
+	_, err := client.PutObject(context.Background(), &s3.PutObjectInput{Bucket: aws.String("b"), Key: aws.String("b.txt")})
+	if !errors.Is(err, errReadOnly) {
+		t.Errorf("PutObject error = %v, want errReadOnly", err)
+	}
+	_, err = client.DeleteObject(context.Background(), &s3.DeleteObjectInput{Bucket: aws.String("b"), Key: aws.String("a.txt")})
+	if !errors.Is(err, errReadOnly) {
+		t.Errorf("DeleteObject error = %v, want errReadOnly", err)
+	}
+	if fake.called("PutObject") != 0 || fake.called("DeleteObject") != 0 {
+		t.Error("expected no write to reach S3")
+	}
+	if _, err := client.GetObject(context.Background(), &s3.GetObjectInput{Bucket: aws.String("b"), Key: aws.String("a.txt")}); err != nil {
+		t.Errorf("expected reads to pass through, got %v", err)
+	}
+}
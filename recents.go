@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+	"github.com/mtyurt/s3n/logger"
+)
+
+// maxRecents caps how many recently visited locations are kept on disk.
+const maxRecents = 50
+
+// recent is a bucket and prefix that was listed, kept across runs.
+type recent struct {
+	Bucket  string    `json:"bucket"`
+	Prefix  string    `json:"prefix"`
+	Visited time.Time `json:"visited"`
+}
+
+func (r recent) Title() string       { return "🕘 " + s3URI(r.Bucket, r.Prefix) }
+func (r recent) Description() string { return "Visited " + humanize.Time(r.Visited) }
+func (r recent) FilterValue() string { return r.Bucket + "/" + r.Prefix }
+
+func recentsFile() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// loadRecents reads the recent locations, most recent first. A missing file means there
+// are none yet, and an unreadable one is logged and treated the same, since it is
+// rewritten on the next visit anyway.
+func loadRecents(path string) []recent {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		logger.Warnf("failed to read recent locations from %s: %v", path, err)
+		return nil
+	}
+	var recents []recent
+	if err := json.Unmarshal(data, &recents); err != nil {
+		logger.Warnf("ignoring corrupt recent locations in %s: %v", path, err)
+		return nil
+	}
+	return recents
+}
+
+func saveRecents(path string, recents []recent) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(recents, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// withRecent puts r first, dropping an earlier visit to the same bucket and prefix and
+// anything beyond maxRecents.
+func withRecent(recents []recent, r recent) []recent {
+	updated := []recent{r}
+	for _, existing := range recents {
+		if existing.Bucket == r.Bucket && existing.Prefix == r.Prefix {
+			continue
+		}
+		updated = append(updated, existing)
+	}
+	if len(updated) > maxRecents {
+		updated = updated[:maxRecents]
+	}
+	return updated
+}
+
+// recordRecent notes a visit to bucket and prefix in the recents file. Nothing is recorded
+// without a recents file; a failed write is only logged.
+func (m *Model) recordRecent(bucket, prefix string) {
+	if m.recentsFile == "" || bucket == "" {
+		return
+	}
+	recents := withRecent(loadRecents(m.recentsFile), recent{Bucket: bucket, Prefix: prefix, Visited: time.Now()})
+	if err := saveRecents(m.recentsFile, recents); err != nil {
+		logger.Warnf("failed to save recent locations to %s: %v", m.recentsFile, err)
+	}
+}
+
+// recentItems lists the recent locations for a picker.
+func (m *Model) recentItems() []list.Item {
+	if m.recentsFile == "" {
+		return nil
+	}
+	var items []list.Item
+	for _, r := range loadRecents(m.recentsFile) {
+		items = append(items, r)
+	}
+	return items
+}
+
+func (m *Model) openRecents() tea.Cmd {
+	items := m.recentItems()
+	if len(items) == 0 {
+		return m.setStatus("No recent locations yet")
+	}
+	m.openPicker(pickerRecents, "Recent locations", items)
+	return nil
+}
@@ -0,0 +1,103 @@
+// ABOUTME: Tests for remembering recently visited locations in recents.go.
+// ABOUTME: Covers ordering, deduplication, the cap and tolerating a missing or corrupt file.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWithRecentDedupesAndCaps(t *testing.T) {
+	var recents []recent
+	for i := 0; i < maxRecents+5; i++ {
+		recents = withRecent(recents, recent{Bucket: "b", Prefix: fmt.Sprintf("p%d/", i)})
+	}
+	if len(recents) != maxRecents {
+		t.Fatalf("kept %d recents, want %d", len(recents), maxRecents)
+	}
+	if recents[0].Prefix != fmt.Sprintf("p%d/", maxRecents+4) {
+		t.Errorf("expected the latest visit first, got %q", recents[0].Prefix)
+	}
+
+	recents = withRecent(recents, recent{Bucket: "b", Prefix: "p30/"})
+	if len(recents) != maxRecents || recents[0].Prefix != "p30/" {
+		t.Fatalf("expected p30/ moved to the front, got %+v", recents[:2])
+	}
+	for _, r := range recents[1:] {
+		if r.Prefix == "p30/" {
+			t.Errorf("expected p30/ to appear only once")
+		}
+	}
+	if recents = withRecent(recents, recent{Bucket: "other", Prefix: "p30/"}); recents[1].Prefix != "p30/" {
+		t.Errorf("expected the same prefix in another bucket to be kept separately")
+	}
+}
+
+func TestLoadRecentsToleratesMissingAndCorruptFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s3n", "history.json")
+	if got := loadRecents(path); len(got) != 0 {
+		t.Errorf("expected no recents without a file, got %+v", got)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadRecents(path); len(got) != 0 {
+		t.Errorf("expected a corrupt file to be ignored, got %+v", got)
+	}
+
+	m := initialModel("test-bucket", options{})
+	m.recentsFile = path
+	m.recordRecent("test-bucket", "logs/")
+	if got := loadRecents(path); len(got) != 1 || got[0].Bucket != "test-bucket" || got[0].Prefix != "logs/" {
+		t.Errorf("expected the corrupt file to be replaced, got %+v", got)
+	}
+}
+
+func TestListingRecordsRecentAndPickerJumpsBack(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.recentsFile = filepath.Join(t.TempDir(), "history.json")
+	m.currentPrefix = "logs/2024/"
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{item{key: "logs/2024/a.log", displayKey: "a.log"}}})
+	m = updated.(Model)
+	m.currentPrefix = ""
+	updated, _ = m.Update(itemsLoadedMsg{items: []list.Item{item{key: "logs/", displayKey: "logs", isDir: true}}})
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("H")})
+	m = updated.(Model)
+	if m.pickerKind != pickerRecents || len(m.picker.Items()) != 2 {
+		t.Fatalf("expected a picker of 2 recent locations, got kind %d", m.pickerKind)
+	}
+	m.picker.Select(1)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.currentPrefix != "logs/2024/" {
+		t.Errorf("expected to jump back to logs/2024/, got %q", m.currentPrefix)
+	}
+}
+
+func TestStartupBucketListOffersRecents(t *testing.T) {
+	m := initialModel("", options{})
+	m.recentsFile = filepath.Join(t.TempDir(), "history.json")
+	if err := saveRecents(m.recentsFile, []recent{{Bucket: "b", Prefix: "deep/"}}); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := m.Update(bucketsLoadedMsg{buckets: []list.Item{bucketEntry{name: "b"}}})
+	m = updated.(Model)
+	if items := m.picker.Items(); len(items) != 2 {
+		t.Fatalf("expected the recent location above the bucket, got %d entries", len(items))
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.bucketName != "b" || m.currentPrefix != "deep/" {
+		t.Errorf("expected to open b/deep/, got %s/%s", m.bucketName, m.currentPrefix)
+	}
+}
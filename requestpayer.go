@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// requesterPaysClient accepts the charges of every object request, which requester pays
+// buckets refuse with 403 Access Denied otherwise. Listing buckets is not charged to a
+// bucket and is left alone.
+type requesterPaysClient struct {
+	S3API
+}
+
+func (c requesterPaysClient) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.ListObjectsV2(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) ListObjectVersions(ctx context.Context, params *s3.ListObjectVersionsInput, optFns ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.ListObjectVersions(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.HeadObject(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.GetObject(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.PutObject(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.CopyObject(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.DeleteObject(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.DeleteObjects(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) RestoreObject(ctx context.Context, params *s3.RestoreObjectInput, optFns ...func(*s3.Options)) (*s3.RestoreObjectOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.RestoreObject(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) GetObjectTagging(ctx context.Context, params *s3.GetObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.GetObjectTagging(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) PutObjectTagging(ctx context.Context, params *s3.PutObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.PutObjectTaggingOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.PutObjectTagging(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) GetObjectRetention(ctx context.Context, params *s3.GetObjectRetentionInput, optFns ...func(*s3.Options)) (*s3.GetObjectRetentionOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.GetObjectRetention(ctx, params, optFns...)
+}
+
+func (c requesterPaysClient) GetObjectLegalHold(ctx context.Context, params *s3.GetObjectLegalHoldInput, optFns ...func(*s3.Options)) (*s3.GetObjectLegalHoldOutput, error) {
+	params.RequestPayer = types.RequestPayerRequester
+	return c.S3API.GetObjectLegalHold(ctx, params, optFns...)
+}
+
+// withRequestPayer wraps client to pay for requests when --request-payer is set. Without
+// it the client is returned as it is, and the bucket owner pays as usual.
+func withRequestPayer(client S3API, opts options) S3API {
+	if !opts.requestPayer {
+		return client
+	}
+	return requesterPaysClient{S3API: client}
+}
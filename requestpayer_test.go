@@ -0,0 +1,73 @@
+// ABOUTME: Tests for paying for the requests to requester pays buckets, in requestpayer.go.
+// ABOUTME: Covers object requests carrying RequestPayer with --request-payer, and none without it.
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// payerCalls makes an object request through a client and returns the RequestPayer of
+// the input the client was given, which the wrapper sets in place.
+var payerCalls = map[string]func(ctx context.Context, c S3API) types.RequestPayer{
+	"ListObjectsV2": func(ctx context.Context, c S3API) types.RequestPayer {
+		in := &s3.ListObjectsV2Input{Bucket: aws.String("b")}
+		c.ListObjectsV2(ctx, in)
+		return in.RequestPayer
+	},
+	"HeadObject": func(ctx context.Context, c S3API) types.RequestPayer {
+		in := &s3.HeadObjectInput{Bucket: aws.String("b"), Key: aws.String("a.txt")}
+		c.HeadObject(ctx, in)
+		return in.RequestPayer
+	},
+	"GetObject": func(ctx context.Context, c S3API) types.RequestPayer {
+		in := &s3.GetObjectInput{Bucket: aws.String("b"), Key: aws.String("a.txt")}
+		c.GetObject(ctx, in)
+		return in.RequestPayer
+	},
+	"PutObject": func(ctx context.Context, c S3API) types.RequestPayer {
+		in := &s3.PutObjectInput{Bucket: aws.String("b"), Key: aws.String("new.txt")}
+		c.PutObject(ctx, in)
+		return in.RequestPayer
+	},
+	"DeleteObject": func(ctx context.Context, c S3API) types.RequestPayer {
+		in := &s3.DeleteObjectInput{Bucket: aws.String("b"), Key: aws.String("a.txt")}
+		c.DeleteObject(ctx, in)
+		return in.RequestPayer
+	},
+	"DeleteObjects": func(ctx context.Context, c S3API) types.RequestPayer {
+		in := &s3.DeleteObjectsInput{Bucket: aws.String("b"), Delete: &types.Delete{Objects: []types.ObjectIdentifier{{Key: aws.String("a.txt")}}}}
+		c.DeleteObjects(ctx, in)
+		return in.RequestPayer
+	},
+}
+
+func TestRequestsCarryRequestPayerOnlyWhenSet(t *testing.T) {
+	ctx := context.Background()
+	for op, call := range payerCalls {
+		fake := newFakeS3(map[string]string{"a.txt": "a"})
+		if got := call(ctx, withRequestPayer(fake, options{requestPayer: true})); got != types.RequestPayerRequester {
+			t.Errorf("%s with --request-payer: RequestPayer = %q, want %q", op, got, types.RequestPayerRequester)
+		}
+		if got := call(ctx, withRequestPayer(fake, options{})); got != "" {
+			t.Errorf("%s without --request-payer: RequestPayer = %q, want it left out", op, got)
+		}
+		if fake.called(op) != 2 {
+			t.Errorf("%s: %d calls reached the client, want 2", op, fake.called(op))
+		}
+	}
+}
+
+func TestRequestPayerIsKeptThroughTheOtherWrappers(t *testing.T) {
+	opts := options{requestPayer: true, sse: "AES256", maxDownloadRate: 1 << 20}
+	client := withReadOnly(withDownloadLimit(withEncryption(withRequestPayer(newFakeS3(map[string]string{"a.txt": "a"}), opts), opts), opts), opts)
+	for _, op := range []string{"ListObjectsV2", "HeadObject", "GetObject", "PutObject"} {
+		if got := payerCalls[op](context.Background(), client); got != types.RequestPayerRequester {
+			t.Errorf("%s: RequestPayer = %q, want %q", op, got, types.RequestPayerRequester)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/smithy-go"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// restoreRequestedMsg reports that S3 accepted a restore of key; alreadyRunning is
+// set when an earlier restore is still in progress.
+type restoreRequestedMsg struct {
+	key            string
+	days           int32
+	tier           types.Tier
+	alreadyRunning bool
+}
+
+// parseRestore reads "<days> [tier]" as typed in the restore prompt; days default to 1
+// and the tier to Standard. Tiers are matched case-insensitively.
+func parseRestore(value string) (int32, types.Tier, error) {
+	fields := strings.Fields(value)
+	days, tier := int32(1), types.TierStandard
+	if len(fields) > 2 {
+		return 0, "", fmt.Errorf("expected <days> [Standard|Bulk|Expedited], got %q", value)
+	}
+	if len(fields) > 0 {
+		n, err := strconv.ParseInt(fields[0], 10, 32)
+		if err != nil || n < 1 {
+			return 0, "", fmt.Errorf("days must be a positive number, got %q", fields[0])
+		}
+		days = int32(n)
+	}
+	if len(fields) > 1 {
+		tier = ""
+		for _, t := range types.TierStandard.Values() {
+			if strings.EqualFold(fields[1], string(t)) {
+				tier = t
+			}
+		}
+		if tier == "" {
+			return 0, "", fmt.Errorf("unknown tier %q, use Standard, Bulk or Expedited", fields[1])
+		}
+	}
+	return days, tier, nil
+}
+
+// restoreRequest keeps a restored copy for days, retrieved with tier.
+func restoreRequest(days int32, tier types.Tier) *types.RestoreRequest {
+	return &types.RestoreRequest{
+		Days:                 aws.Int32(days),
+		GlacierJobParameters: &types.GlacierJobParameters{Tier: tier},
+	}
+}
+
+// promptRestore asks how to restore an archived object, or explains why it needs no restore.
+func (m *Model) promptRestore(i item) tea.Cmd {
+	switch {
+	case i.isDir:
+		return m.setStatus("Only objects can be restored")
+	case i.restoring:
+		return m.setStatus("Restore already in progress")
+	case !i.archived():
+		return m.setStatus(fmt.Sprintf("%s is not archived, no restore is needed", i.displayKey))
+	}
+	m.restoreKey = i.key
+	m.restoreStorageClass = i.storageClass
+	return m.openPrompt(promptRestore, "Restore for <days> [Standard|Bulk|Expedited]: ", "1 Standard")
+}
+
+// startRestore validates the prompt value and requests the restore of m.restoreKey.
+func (m *Model) startRestore(value string) tea.Cmd {
+	days, tier, err := parseRestore(value)
+	if err != nil {
+		m.setErrorStatus(err.Error())
+		return nil
+	}
+	if tier == types.TierExpedited && m.restoreStorageClass == string(types.ObjectStorageClassDeepArchive) {
+		m.setErrorStatus("Deep Archive objects cannot be restored with the Expedited tier")
+		return nil
+	}
+	if m.opts.dryRun {
+		return m.setStatus(skipped("RestoreObject", m.bucketName, m.restoreKey).String())
+	}
+	return restoreObject(m.ctx, m.client, m.bucketName, m.restoreKey, days, tier)
+}
+
+// restoreObject asks S3 to restore an archived object.
+func restoreObject(ctx context.Context, client S3API, bucket, key string, days int32, tier types.Tier) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.RestoreObject(ctx, &s3.RestoreObjectInput{
+			Bucket:         aws.String(bucket),
+			Key:            aws.String(key),
+			RestoreRequest: restoreRequest(days, tier),
+		})
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "RestoreAlreadyInProgress" {
+			return restoreRequestedMsg{key: key, alreadyRunning: true}
+		}
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to restore %s: %w", key, err), retry: restoreObject(ctx, client, bucket, key, days, tier)}
+		}
+		return restoreRequestedMsg{key: key, days: days, tier: tier}
+	}
+}
+
+func (msg restoreRequestedMsg) String() string {
+	if msg.alreadyRunning {
+		return "Restore already in progress"
+	}
+	unit := "days"
+	if msg.days == 1 {
+		unit = "day"
+	}
+	return fmt.Sprintf("Restore of %s requested (%s tier, kept for %d %s)", msg.key, msg.tier, msg.days, unit)
+}
@@ -0,0 +1,98 @@
+// ABOUTME: Tests for restoring archived objects in restore.go.
+// ABOUTME: Covers parsing the prompt, the RestoreRequest per tier and restores already in progress.
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestRestoreRequestPerTier(t *testing.T) {
+	for _, tier := range []types.Tier{types.TierStandard, types.TierBulk, types.TierExpedited} {
+		req := restoreRequest(3, tier)
+		if req.Days == nil || *req.Days != 3 {
+			t.Errorf("%s: days = %v, want 3", tier, req.Days)
+		}
+		if req.GlacierJobParameters == nil || req.GlacierJobParameters.Tier != tier {
+			t.Errorf("%s: job parameters = %+v", tier, req.GlacierJobParameters)
+		}
+	}
+}
+
+func TestParseRestore(t *testing.T) {
+	tests := []struct {
+		value string
+		days  int32
+		tier  types.Tier
+		err   bool
+	}{
+		{"", 1, types.TierStandard, false},
+		{"7", 7, types.TierStandard, false},
+		{"2 bulk", 2, types.TierBulk, false},
+		{" 1  Expedited ", 1, types.TierExpedited, false},
+		{"0", 0, "", true},
+		{"two", 0, "", true},
+		{"1 fast", 0, "", true},
+		{"1 Bulk extra", 0, "", true},
+	}
+	for _, tt := range tests {
+		days, tier, err := parseRestore(tt.value)
+		if (err != nil) != tt.err || days != tt.days || tier != tt.tier {
+			t.Errorf("parseRestore(%q) = %d, %q, %v", tt.value, days, tier, err)
+		}
+	}
+}
+
+func TestRestoreObjectSendsRequest(t *testing.T) {
+	var body string
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	msg := restoreObject(context.Background(), client, "b", "old.log", 2, types.TierBulk)()
+	if msg != (restoreRequestedMsg{key: "old.log", days: 2, tier: types.TierBulk}) {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	if !strings.Contains(body, "<Days>2</Days>") || !strings.Contains(body, "<Tier>Bulk</Tier>") {
+		t.Errorf("unexpected request body %s", body)
+	}
+	if got := msg.(restoreRequestedMsg).String(); got != "Restore of old.log requested (Bulk tier, kept for 2 days)" {
+		t.Errorf("status = %q", got)
+	}
+}
+
+func TestRestoreAlreadyInProgress(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`<Error><Code>RestoreAlreadyInProgress</Code><Message>Object restore is already in progress</Message></Error>`))
+	})
+
+	msg := restoreObject(context.Background(), client, "b", "old.log", 1, types.TierStandard)()
+	restore, ok := msg.(restoreRequestedMsg)
+	if !ok || restore.String() != "Restore already in progress" {
+		t.Errorf("expected an in-progress notice, got %#v", msg)
+	}
+}
+
+func TestRestoreNotNeeded(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.promptRestore(item{key: "a.txt", displayKey: "a.txt", storageClass: "STANDARD"})
+	if m.prompt != promptNone || m.statusMsg != "a.txt is not archived, no restore is needed" {
+		t.Errorf("prompt=%v status=%q", m.prompt, m.statusMsg)
+	}
+
+	m.promptRestore(item{key: "old.log", displayKey: "old.log", storageClass: "DEEP_ARCHIVE"})
+	if m.prompt != promptRestore || m.restoreKey != "old.log" {
+		t.Fatalf("expected a restore prompt for an archived object")
+	}
+	if cmd := m.startRestore("1 Expedited"); cmd != nil || !strings.Contains(m.statusMsg, "cannot be restored with the Expedited tier") {
+		t.Errorf("expected Expedited to be refused for Deep Archive, status %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"time"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/mtyurt/s3n/logger"
+)
+
+// defaultMaxAttempts is how many times a request is sent before its error is shown.
+const defaultMaxAttempts = 5
+
+// maxRetryBackoff caps the exponential delay between attempts.
+const maxRetryBackoff = 20 * time.Second
+
+// newRetryer retries throttling, request timeouts and 5xx errors until maxAttempts
+// requests were sent, backing off exponentially with jitter. Adaptive mode also slows
+// further requests down while S3 keeps throttling.
+func newRetryer(maxAttempts int, maxBackoff time.Duration) awsv2.RetryerV2 {
+	return loggingRetryer{retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+		o.StandardOptions = append(o.StandardOptions, func(so *retry.StandardOptions) {
+			so.MaxAttempts = maxAttempts
+			so.MaxBackoff = maxBackoff
+			so.Backoff = retry.NewExponentialJitterBackoff(maxBackoff)
+		})
+	})}
+}
+
+// loggingRetryer logs each transient failure it retries; the user only sees the error
+// once the attempts run out.
+type loggingRetryer struct {
+	awsv2.RetryerV2
+}
+
+func (r loggingRetryer) RetryDelay(attempt int, err error) (time.Duration, error) {
+	delay, delayErr := r.RetryerV2.RetryDelay(attempt, err)
+	if delayErr == nil {
+		logger.Warnf("S3 request failed (attempt %d of %d), retrying in %s: %v", attempt, r.MaxAttempts(), delay.Round(time.Millisecond), err)
+	}
+	return delay, delayErr
+}
@@ -0,0 +1,70 @@
+// ABOUTME: Tests for retrying transient S3 errors in retry.go.
+// ABOUTME: Covers a listing that succeeds after failures and giving up once attempts run out.
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// flakyServer answers the first failures requests with a transient error, then lists one object.
+func flakyServer(t *testing.T, failures int, requests *int) *s3.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		switch {
+		case *requests > failures:
+			w.Write([]byte(`<ListBucketResult><Name>b</Name><KeyCount>1</KeyCount><IsTruncated>false</IsTruncated><Contents><Key>a.txt</Key><Size>1</Size></Contents></ListBucketResult>`))
+		case *requests%2 == 1:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`<Error><Code>InternalError</Code><Message>We encountered an internal error. Please try again.</Message></Error>`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<Error><Code>RequestTimeout</Code><Message>Your socket connection to the server was not read from or written to within the timeout period.</Message></Error>`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  aws.AnonymousCredentials{},
+		Retryer:      newRetryer(3, time.Millisecond),
+	})
+}
+
+func TestListingRetriesTransientErrors(t *testing.T) {
+	requests := 0
+	m := initialModel("b", options{})
+	m.client = flakyServer(t, 2, &requests)
+
+	msg, ok := m.listItems(context.Background()).(itemsLoadedMsg)
+	if !ok {
+		t.Fatalf("expected itemsLoadedMsg after retries, got %#v", msg)
+	}
+	if len(msg.items) != 1 || msg.items[0].(item).key != "a.txt" {
+		t.Errorf("items = %v", msg.items)
+	}
+	if requests != 3 {
+		t.Errorf("sent %d requests, want 3", requests)
+	}
+}
+
+func TestListingGivesUpAfterMaxAttempts(t *testing.T) {
+	requests := 0
+	m := initialModel("b", options{})
+	m.client = flakyServer(t, 5, &requests)
+
+	if _, ok := m.listItems(context.Background()).(itemsLoadedMsg); ok {
+		t.Fatal("expected the error once attempts run out")
+	}
+	if requests != 3 {
+		t.Errorf("sent %d requests, want 3", requests)
+	}
+}
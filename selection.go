@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// batchResult reports a delete or download of the selected objects; err is the first failure.
+type batchResult struct {
+	succeeded int
+	failed    int
+	err       error
+}
+
+// summary describes the result, e.g. "Deleted 3 objects, 1 failed: AccessDenied".
+func (r batchResult) summary(verb string) string {
+	s := fmt.Sprintf("%s %s", verb, plural(r.succeeded, "object", "objects"))
+	if r.failed > 0 {
+		s += fmt.Sprintf(", %d failed: %v", r.failed, r.err)
+	}
+	return s
+}
+
+type selectionDeletedMsg struct {
+	bucket string
+	keys   []string
+	batchResult
+}
+
+type selectionDownloadedMsg struct {
+	dir string
+	batchResult
+}
+
+// deleteObjectsAPI is the part of the S3 client deleteKeys needs.
+type deleteObjectsAPI interface {
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+}
+
+// deleteKeys removes keys with DeleteObjects in batches of deleteBatchSize. A failed
+// batch counts every key in it as failed and the remaining batches are still sent.
+func deleteKeys(ctx context.Context, client deleteObjectsAPI, bucket string, keys []string) batchResult {
+	var result batchResult
+	for start := 0; start < len(keys); start += deleteBatchSize {
+		batch := keys[start:min(start+deleteBatchSize, len(keys))]
+		objects := make([]types.ObjectIdentifier, 0, len(batch))
+		for _, k := range batch {
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(k)})
+		}
+		output, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			result.failed += len(batch)
+			if result.err == nil {
+				result.err = err
+			}
+			continue
+		}
+		result.succeeded += len(batch) - len(output.Errors)
+		result.failed += len(output.Errors)
+		if len(output.Errors) > 0 && result.err == nil {
+			first := output.Errors[0]
+			result.err = fmt.Errorf("%s: %s", aws.StringValue(first.Key), aws.StringValue(first.Message))
+		}
+	}
+	return result
+}
+
+// downloadKeys writes each object to dir under its base name, counting the bytes received
+// into progress. Existing files are not overwritten; they count as failures.
+func downloadKeys(ctx context.Context, client S3API, bucket string, keys []string, dir string, progress *transferProgress) batchResult {
+	var result batchResult
+	for _, k := range keys {
+		if err := downloadObject(ctx, client, bucket, k, filepath.Join(dir, path.Base(k)), progress); err != nil {
+			result.failed++
+			if result.err == nil {
+				result.err = err
+			}
+			continue
+		}
+		result.succeeded++
+	}
+	return result
+}
+
+func downloadObject(ctx context.Context, client S3API, bucket, key, dest string, progress *transferProgress) error {
+	obj, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return err
+	}
+	defer obj.Body.Close()
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, withProgress(obj.Body, progress)); err != nil {
+		f.Close()
+		os.Remove(dest)
+		return err
+	}
+	return f.Close()
+}
+
+// selectedKeys lists the selected objects in key order.
+func (m Model) selectedKeys() []string {
+	keys := make([]string, 0, len(m.selected))
+	for k := range m.selected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toggleSelected marks or unmarks the object under the cursor.
+func (m *Model) toggleSelected() tea.Cmd {
+	i, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return nil
+	}
+	if i.isDir {
+		return m.setStatus("Only objects can be selected")
+	}
+	if m.selected == nil {
+		m.selected = map[string]bool{}
+	}
+	if m.selected[i.key] {
+		delete(m.selected, i.key)
+	} else {
+		m.selected[i.key] = true
+	}
+	m.currentItems = m.withMarks(m.currentItems)
+	return tea.Batch(m.list.SetItems(m.visibleItems()), m.setStatus(fmt.Sprintf("%d selected", len(m.selected))))
+}
+
+// clearSelection unmarks every object.
+func (m *Model) clearSelection() tea.Cmd {
+	m.selected = nil
+	m.currentItems = m.withMarks(m.currentItems)
+	return m.list.SetItems(m.visibleItems())
+}
+
+// withMarks sets whether each item is selected, in place.
+func (m *Model) withMarks(items []list.Item) []list.Item {
+	for idx, it := range items {
+		if i, ok := it.(item); ok {
+			i.marked = m.selected[i.key]
+			items[idx] = i
+		}
+	}
+	return items
+}
+
+// startDeleteSelected deletes the selected objects as an operation.
+func (m *Model) startDeleteSelected() tea.Cmd {
+	keys := m.selectedKeys()
+	if m.opts.dryRun {
+		for _, k := range keys {
+			skipped("DeleteObjects", m.bucketName, k)
+		}
+		return m.setStatus("[dry-run] would DeleteObjects " + plural(len(keys), "selected object", "selected objects"))
+	}
+	bucket := m.bucketName
+	return m.startDelete(fmt.Sprintf("Deleting %d selected objects", len(keys)), func(ctx context.Context, client S3API) tea.Msg {
+		return selectionDeletedMsg{bucket: bucket, keys: keys, batchResult: deleteKeys(ctx, client, bucket, keys)}
+	})
+}
+
+// promptDownload asks for the local directory to download the selected objects to.
+func (m *Model) promptDownload() tea.Cmd {
+	if len(m.selected) == 0 {
+		return m.setStatus(fmt.Sprintf("Nothing selected, press %s to select objects", m.keys.Select.Help().Key))
+	}
+	return m.openPrompt(promptDownload, fmt.Sprintf("Download %d selected to: ", len(m.selected)), ".")
+}
+
+// startDownloadSelected checks dir is a directory before downloading the selected objects into it.
+func (m *Model) startDownloadSelected(dir string) tea.Cmd {
+	dir = expandHome(strings.TrimSpace(dir))
+	if dir == "" {
+		return nil
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		m.setErrorStatus(fmt.Sprintf("Cannot download to %s: not a directory", dir))
+		return nil
+	}
+	keys := m.selectedKeys()
+	var total int64
+	for _, it := range m.currentItems {
+		if i := it.(item); m.selected[i.key] {
+			total += i.size
+		}
+	}
+	client, bucket := m.client, m.bucketName
+	return m.startTransfer(fmt.Sprintf("Downloading %d selected objects", len(keys)), total, func(ctx context.Context, p *transferProgress) tea.Msg {
+		return selectionDownloadedMsg{dir: dir, batchResult: downloadKeys(ctx, client, bucket, keys, dir, p)}
+	})
+}
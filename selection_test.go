@@ -0,0 +1,189 @@
+// ABOUTME: Tests for selecting several objects and acting on them together in selection.go.
+// ABOUTME: Covers chunking deletes into DeleteObjects batches, downloads and the space/esc flow.
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeDeleteObjects records the size of each DeleteObjects batch and fails the ones listed in fail.
+type fakeDeleteObjects struct {
+	batches []int
+	fail    map[int]bool
+}
+
+func (f *fakeDeleteObjects) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	f.batches = append(f.batches, len(params.Delete.Objects))
+	if f.fail[len(f.batches)] {
+		return nil, errors.New("AccessDenied")
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func TestDeleteKeysChunksBatches(t *testing.T) {
+	keys := make([]string, 2500)
+	for i := range keys {
+		keys[i] = "k" + strings.Repeat("x", i%7)
+	}
+	client := &fakeDeleteObjects{fail: map[int]bool{2: true}}
+
+	result := deleteKeys(context.Background(), client, "b", keys)
+
+	if len(client.batches) != 3 || client.batches[0] != 1000 || client.batches[1] != 1000 || client.batches[2] != 500 {
+		t.Errorf("batches = %v, want [1000 1000 500]", client.batches)
+	}
+	if result.succeeded != 1500 || result.failed != 1000 || result.err == nil {
+		t.Errorf("result = %+v, want 1500 deleted and the failed batch counted", result)
+	}
+	if got := result.summary("Deleted"); got != "Deleted 1500 objects, 1000 failed: AccessDenied" {
+		t.Errorf("summary = %q", got)
+	}
+}
+
+func TestDeleteKeysCountsPerKeyErrors(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<DeleteResult><Error><Key>b.txt</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error></DeleteResult>`))
+	})
+	result := deleteKeys(context.Background(), client, "b", []string{"a.txt", "b.txt"})
+	if result.succeeded != 1 || result.failed != 1 || !strings.Contains(result.err.Error(), "b.txt") {
+		t.Errorf("result = %+v, want b.txt reported as failed", result)
+	}
+}
+
+func TestDownloadKeysKeepsExistingFiles(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("content of " + r.URL.Path))
+	})
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := downloadKeys(context.Background(), client, "bucket", []string{"logs/a.txt", "logs/b.txt"}, dir, nil)
+
+	if result.succeeded != 1 || result.failed != 1 {
+		t.Errorf("result = %+v, want one download and one refused overwrite", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "content of /bucket/logs/a.txt" {
+		t.Errorf("a.txt = %q", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "b.txt")); string(data) != "mine" {
+		t.Errorf("expected b.txt to be left alone, got %q", data)
+	}
+}
+
+func TestSelectAndClearSelection(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "dir/", displayKey: "dir", isDir: true},
+		item{key: "a.txt", displayKey: "a.txt"},
+		item{key: "b.txt", displayKey: "b.txt"},
+	}})
+	m = updated.(Model)
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+
+	updated, _ = m.Update(space)
+	m = updated.(Model)
+	if len(m.selected) != 0 {
+		t.Errorf("expected directories not to be selectable")
+	}
+	m.list.Select(1)
+	updated, _ = m.Update(space)
+	m = updated.(Model)
+	if !m.selected["a.txt"] || !strings.HasPrefix(m.list.Items()[1].(item).Title(), "✅") {
+		t.Fatalf("expected a.txt selected and marked, got %v", m.selected)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	m = updated.(Model)
+	if !m.confirmDelete || !strings.Contains(m.footer(), "Delete 1 selected object?") {
+		t.Errorf("expected ctrl+d to confirm deleting the selection, got %q", m.footer())
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if len(m.selected) != 0 || strings.HasPrefix(m.list.Items()[1].(item).Title(), "✅") {
+		t.Errorf("expected esc to clear the selection")
+	}
+}
+
+// filterMatches runs the commands the list returned for a filter keystroke and returns
+// the matches they compute.
+func filterMatches(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatalf("expected the filter to compute matches, got no command")
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			if c == nil {
+				continue
+			}
+			if m, ok := c().(list.FilterMatchesMsg); ok {
+				return m
+			}
+		}
+	}
+	if m, ok := msg.(list.FilterMatchesMsg); ok {
+		return m
+	}
+	t.Fatalf("expected the filter to compute matches, got %T", msg)
+	return nil
+}
+
+func TestSelectWhileFilteredMarksTheItemUnderTheCursor(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{
+		item{key: "dir/", displayKey: "dir", isDir: true},
+		item{key: "a.txt", displayKey: "a.txt"},
+		item{key: "b.txt", displayKey: "b.txt"},
+	}})
+	m = updated.(Model)
+	m.list, _ = m.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b.txt")})
+	m.list, _ = m.list.Update(filterMatches(t, cmd))
+	m.list, _ = m.list.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if n := len(m.list.VisibleItems()); n != 1 {
+		t.Fatalf("expected the filter to show only b.txt, got %d items", n)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = updated.(Model)
+	if !m.selected["b.txt"] || len(m.selected) != 1 {
+		t.Fatalf("expected b.txt selected, got %v", m.selected)
+	}
+	var titles []string
+	for _, it := range m.list.Items() {
+		titles = append(titles, it.(item).Title())
+	}
+	if want := []string{"📁 dir", "📄 a.txt", "✅ b.txt"}; strings.Join(titles, ",") != strings.Join(want, ",") {
+		t.Errorf("items = %v, want %v", titles, want)
+	}
+}
+
+func TestSelectionDeletedReloadsAndReports(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.client = newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<ListBucketResult><Name>test-bucket</Name></ListBucketResult>`))
+	})
+	m.selected = map[string]bool{"a.txt": true}
+	updated, cmd := m.Update(selectionDeletedMsg{bucket: "test-bucket", keys: []string{"a.txt"}, batchResult: batchResult{succeeded: 1}})
+	m = updated.(Model)
+	if len(m.selected) != 0 || !m.loading || cmd == nil || m.statusMsg != "Deleted 1 object" {
+		t.Errorf("expected the selection cleared and a reload, got status %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sortMode orders the loaded items; directories always come first.
+type sortMode int
+
+const (
+	sortByName sortMode = iota
+	sortBySize
+	sortByModified
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortBySize:
+		return "size"
+	case sortByModified:
+		return "modified"
+	}
+	return "name"
+}
+
+// describe is how the status bar names the mode.
+func (s sortMode) describe() string {
+	switch s {
+	case sortBySize:
+		return "size, largest first"
+	case sortByModified:
+		return "modified time, newest first"
+	}
+	return "name"
+}
+
+func (s sortMode) next() sortMode {
+	return (s + 1) % 3
+}
+
+// sortItems orders items in place by mode, directories first and by name.
+func sortItems(items []list.Item, mode sortMode) {
+	sort.SliceStable(items, func(a, b int) bool {
+		x, y := items[a].(item), items[b].(item)
+		if x.isDir != y.isDir {
+			return x.isDir
+		}
+		if !x.isDir {
+			switch mode {
+			case sortBySize:
+				if x.size != y.size {
+					return x.size > y.size
+				}
+			case sortByModified:
+				if !x.modified.Equal(y.modified) {
+					return x.modified.After(y.modified)
+				}
+			}
+		}
+		return strings.Compare(x.key, y.key) < 0
+	})
+}
+
+// cycleSort switches to the next sort mode and re-sorts the loaded items.
+func (m *Model) cycleSort() tea.Cmd {
+	m.sortMode = m.sortMode.next()
+	sortItems(m.currentItems, m.sortMode)
+	m.updateTitle()
+	m.list.SetItems(m.visibleItems())
+	return m.setStatus(fmt.Sprintf("Sorted by %s", m.sortMode.describe()))
+}
@@ -0,0 +1,77 @@
+// ABOUTME: Tests for ordering loaded items in sort.go.
+// ABOUTME: Covers each sort mode, directories first, and keeping the mode across reloads.
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func mixedItems() []list.Item {
+	now := time.Now()
+	return []list.Item{
+		item{key: "b.txt", size: 10, modified: now.Add(-time.Hour)},
+		item{key: "z/", isDir: true},
+		item{key: "a.txt", size: 30, modified: now.Add(-3 * time.Hour)},
+		item{key: "c.txt", size: 20, modified: now},
+		item{key: "m/", isDir: true},
+	}
+}
+
+func keysOf(items []list.Item) []string {
+	var keys []string
+	for _, it := range items {
+		keys = append(keys, it.(item).key)
+	}
+	return keys
+}
+
+func TestSortItems(t *testing.T) {
+	tests := []struct {
+		mode sortMode
+		want []string
+	}{
+		{sortByName, []string{"m/", "z/", "a.txt", "b.txt", "c.txt"}},
+		{sortBySize, []string{"m/", "z/", "a.txt", "c.txt", "b.txt"}},
+		{sortByModified, []string{"m/", "z/", "c.txt", "b.txt", "a.txt"}},
+	}
+	for _, tc := range tests {
+		items := mixedItems()
+		sortItems(items, tc.mode)
+		if got := keysOf(items); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestSortKeyCyclesAndSurvivesReload(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	updated, _ := m.Update(itemsLoadedMsg{items: mixedItems()})
+	m = updated.(Model)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	m = updated.(Model)
+	if m.sortMode != sortBySize || m.statusMsg != "Sorted by size, largest first" {
+		t.Fatalf("mode=%s status=%q, want size", m.sortMode, m.statusMsg)
+	}
+	if got := keysOf(m.list.Items()); got[2] != "a.txt" {
+		t.Errorf("expected the largest file first, got %v", got)
+	}
+
+	updated, _ = m.Update(itemsLoadedMsg{items: mixedItems()})
+	m = updated.(Model)
+	if got := keysOf(m.list.Items()); !reflect.DeepEqual(got, []string{"m/", "z/", "a.txt", "c.txt", "b.txt"}) {
+		t.Errorf("expected a reload to keep the size order, got %v", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	if m = updated.(Model); m.sortMode != sortByName {
+		t.Errorf("expected the modes to cycle back to name, got %s", m.sortMode)
+	}
+}
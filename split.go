@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	paneStyle        = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240"))
+	focusedPaneStyle = paneStyle.BorderForeground(lipgloss.Color("205"))
+)
+
+type splitKeyMap struct {
+	SwitchPane key.Binding
+	Copy       key.Binding
+	Move       key.Binding
+	Close      key.Binding
+}
+
+func newSplitKeyMap() splitKeyMap {
+	return splitKeyMap{
+		SwitchPane: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch pane"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy to other pane"),
+		),
+		Move: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "move to other pane"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "|"),
+			key.WithHelp("esc", "close split view"),
+		),
+	}
+}
+
+// pane is one side of the split view, browsing its own prefix.
+type pane struct {
+	list    list.Model
+	bucket  string
+	prefix  string
+	loading bool
+}
+
+// splitView shows two prefixes side by side for copying and moving objects between them.
+type splitView struct {
+	panes [2]pane
+	focus int
+	keys  splitKeyMap
+}
+
+type paneLoadedMsg struct {
+	pane   int
+	bucket string
+	prefix string
+	items  []list.Item
+}
+
+type objectCopiedMsg struct {
+	bucket    string
+	key       string
+	dstBucket string
+	dstKey    string
+	moved     bool
+}
+
+// openSplit starts the split view with both panes on the current location.
+func (m *Model) openSplit() tea.Cmd {
+	s := &splitView{keys: newSplitKeyMap()}
+	if m.opts.readOnly {
+		s.keys.Copy.SetEnabled(false)
+		s.keys.Move.SetEnabled(false)
+	}
+	var cmds []tea.Cmd
+	for idx := range s.panes {
+		delegate := list.NewDefaultDelegate()
+		delegate.SetSpacing(1)
+		l := list.New(nil, delegate, 0, 0)
+		l.Styles.Title = m.list.Styles.Title
+		l.SetShowHelp(false)
+		l.DisableQuitKeybindings()
+		l.Filter = fuzzyFilter
+		s.panes[idx] = pane{list: l, bucket: m.bucketName, prefix: m.currentPrefix, loading: true}
+		cmds = append(cmds, loadPane(m.ctx, m.client, idx, m.bucketName, m.currentPrefix, m.pageSize))
+	}
+	m.split = s
+	m.resizeSplit()
+	return tea.Batch(cmds...)
+}
+
+// resizeSplit divides the window between the two panes.
+func (m *Model) resizeSplit() {
+	h, v := docStyle.GetFrameSize()
+	width := (m.lastWindowSize.Width-h)/2 - paneStyle.GetHorizontalFrameSize()
+	height := m.lastWindowSize.Height - v - paneStyle.GetVerticalFrameSize() - 2
+	for idx := range m.split.panes {
+		m.split.panes[idx].list.SetSize(max(width, 0), max(height, 0))
+	}
+}
+
+// loadPane lists the first page of prefix for one pane.
+func loadPane(ctx context.Context, client S3API, idx int, bucket, prefix string, pageSize int) tea.Cmd {
+	return func() tea.Msg {
+		output, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:    aws.String(bucket),
+			Prefix:    aws.String(prefix),
+			MaxKeys:   aws.Int32(int32(pageSize)),
+			Delimiter: aws.String("/"),
+		})
+		if err != nil {
+			return err
+		}
+		items := itemsFromListing(output, prefix, prefix)
+		listItems := make([]list.Item, 0, len(items))
+		for _, i := range items {
+			listItems = append(listItems, i)
+		}
+		return paneLoadedMsg{pane: idx, bucket: bucket, prefix: prefix, items: listItems}
+	}
+}
+
+// copyObject copies bucket/key to dstBucket/dstKey, deleting the source afterwards when move is set.
+// With dryRun nothing is copied.
+func copyObject(ctx context.Context, client S3API, bucket, key, dstBucket, dstKey string, move, dryRun bool) tea.Cmd {
+	return func() tea.Msg {
+		if dryRun {
+			op := "CopyObject"
+			if move {
+				op = "CopyObject and DeleteObject"
+			}
+			return skipped(op, bucket, key+" to "+s3URI(dstBucket, dstKey))
+		}
+		_, err := client.CopyObject(ctx, &s3.CopyObjectInput{
+			Bucket:     aws.String(dstBucket),
+			Key:        aws.String(dstKey),
+			CopySource: aws.String(copySource(bucket, key)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", key, err)
+		}
+		if move {
+			if _, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket: aws.String(bucket),
+				Key:    aws.String(key),
+			}); err != nil {
+				return fmt.Errorf("copied %s but failed to delete the original: %w", key, err)
+			}
+		}
+		return objectCopiedMsg{
+			bucket:    bucket,
+			key:       key,
+			dstBucket: dstBucket,
+			dstKey:    dstKey,
+			moved:     move,
+		}
+	}
+}
+
+// updateSplit handles messages while the split view is open; handled reports whether
+// msg belonged to the split view.
+func (m Model) updateSplit(msg tea.Msg) (Model, tea.Cmd, bool) {
+	s := m.split
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.lastWindowSize = msg
+		m.updateListSize(msg.Width, msg.Height)
+		m.resizeSplit()
+		return m, nil, true
+	case paneLoadedMsg:
+		p := &s.panes[msg.pane]
+		if p.bucket != msg.bucket || p.prefix != msg.prefix {
+			return m, nil, true
+		}
+		p.loading = false
+		cmd := p.list.SetItems(msg.items)
+		return m, cmd, true
+	case objectCopiedMsg:
+		verb := "Copied"
+		if msg.moved {
+			verb = "Moved"
+		}
+		m.cache.invalidateKey(msg.dstBucket, msg.dstKey)
+		if msg.moved {
+			m.cache.invalidateKey(msg.bucket, msg.key)
+		}
+		cmd := tea.Batch(m.setStatus(fmt.Sprintf("%s %s to %s", verb, s3URI(msg.bucket, msg.key), s3URI(msg.dstBucket, msg.dstKey))), m.reloadPane(0), m.reloadPane(1))
+		return m, cmd, true
+	case error:
+		m.setErrorStatus(fmt.Sprintf("Error: %v", msg))
+		for idx := range s.panes {
+			s.panes[idx].loading = false
+		}
+		return m, nil, true
+	case tea.KeyMsg:
+		if m.statusIsError {
+			m.clearStatus()
+		}
+		p := &s.panes[s.focus]
+		if p.list.FilterState() == list.Filtering {
+			break
+		}
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, m.quit(), true
+		case key.Matches(msg, s.keys.Close) && p.list.FilterState() == list.Unfiltered:
+			m.split = nil
+			return m, nil, true
+		case key.Matches(msg, s.keys.SwitchPane):
+			s.focus = 1 - s.focus
+			return m, nil, true
+		case key.Matches(msg, m.keys.Enter):
+			if i, ok := p.list.SelectedItem().(item); ok && i.isDir {
+				p.prefix = i.key
+				cmd := m.reloadPane(s.focus)
+				return m, cmd, true
+			}
+			return m, nil, true
+		case key.Matches(msg, m.keys.Back):
+			if p.prefix != "" {
+				p.prefix = parentPrefix(p.prefix)
+				cmd := m.reloadPane(s.focus)
+				return m, cmd, true
+			}
+			return m, nil, true
+		case key.Matches(msg, m.keys.Reload):
+			cmd := m.reloadPane(s.focus)
+			return m, cmd, true
+		case key.Matches(msg, s.keys.Copy), key.Matches(msg, s.keys.Move):
+			i, ok := p.list.SelectedItem().(item)
+			if !ok || i.isDir {
+				cmd := m.setStatus("Only objects can be copied or moved")
+				return m, cmd, true
+			}
+			if err := checkCopySize(i); err != nil {
+				m.setErrorStatus(err.Error())
+				return m, nil, true
+			}
+			other := s.panes[1-s.focus]
+			dstKey := other.prefix + path.Base(i.key)
+			if other.bucket == p.bucket && dstKey == i.key {
+				cmd := m.setStatus("Both panes show the same prefix, nothing to do")
+				return m, cmd, true
+			}
+			move := key.Matches(msg, s.keys.Move)
+			return m, copyObject(m.ctx, m.client, p.bucket, i.key, other.bucket, dstKey, move, m.opts.dryRun), true
+		}
+	default:
+		return m, nil, false
+	}
+
+	p := &s.panes[s.focus]
+	var cmd tea.Cmd
+	p.list, cmd = p.list.Update(msg)
+	return m, cmd, true
+}
+
+func (m *Model) reloadPane(idx int) tea.Cmd {
+	p := &m.split.panes[idx]
+	p.loading = true
+	p.list.ResetFilter()
+	return loadPane(m.ctx, m.client, idx, p.bucket, p.prefix, m.pageSize)
+}
+
+func (m Model) splitViewRender() string {
+	s := m.split
+	var panes []string
+	for idx, p := range s.panes {
+		l := p.list
+		l.Title = fmt.Sprintf("%s/%s", p.bucket, p.prefix)
+		if p.loading {
+			l.Title += " (loading...)"
+		}
+		style := paneStyle
+		if idx == s.focus {
+			style = focusedPaneStyle
+		}
+		panes = append(panes, style.Render(l.View()))
+	}
+
+	var help []string
+	for _, b := range []key.Binding{s.keys.SwitchPane, s.keys.Copy, s.keys.Move, s.keys.Close} {
+		if !b.Enabled() {
+			continue
+		}
+		help = append(help, fmt.Sprintf("%s %s", helpStyleKey.Render(b.Help().Key), helpStyleVal.Render(b.Help().Desc)))
+	}
+	footer := strings.Join(help, " • ")
+	if m.showStatusMsg {
+		footer = m.statusMsg
+	}
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lipgloss.JoinHorizontal(lipgloss.Top, panes...), footer))
+}
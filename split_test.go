@@ -0,0 +1,71 @@
+// ABOUTME: Tests for the two-pane split view in split.go.
+// ABOUTME: Covers pane loading, focus switching and copy guards.
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newSplitModel(t *testing.T) Model {
+	t.Helper()
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.currentPrefix = "logs/"
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'|'}})
+	m = updated.(Model)
+	if m.split == nil {
+		t.Fatal("expected | to open the split view")
+	}
+	return m
+}
+
+func TestSplitPanesLoadIndependently(t *testing.T) {
+	m := newSplitModel(t)
+	updated, _ := m.Update(paneLoadedMsg{pane: 1, bucket: "test-bucket", prefix: "logs/", items: []list.Item{
+		item{key: "logs/a.log", displayKey: "a.log"},
+	}})
+	m = updated.(Model)
+	if n := len(m.split.panes[1].list.Items()); n != 1 || m.split.panes[1].loading {
+		t.Errorf("right pane has %d items (loading=%v), want 1 loaded", n, m.split.panes[1].loading)
+	}
+	if n := len(m.split.panes[0].list.Items()); n != 0 || !m.split.panes[0].loading {
+		t.Errorf("left pane should still be loading, has %d items", n)
+	}
+
+	// A listing for a prefix the pane has already left is dropped.
+	m.split.panes[0].prefix = "other/"
+	updated, _ = m.Update(paneLoadedMsg{pane: 0, bucket: "test-bucket", prefix: "logs/", items: []list.Item{item{key: "logs/a.log"}}})
+	m = updated.(Model)
+	if n := len(m.split.panes[0].list.Items()); n != 0 {
+		t.Errorf("stale listing was applied to the left pane")
+	}
+}
+
+func TestSplitTabSwitchesFocusAndEscCloses(t *testing.T) {
+	m := newSplitModel(t)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if m.split.focus != 1 {
+		t.Errorf("focus = %d, want 1 after tab", m.split.focus)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).split != nil {
+		t.Errorf("expected esc to close the split view")
+	}
+}
+
+func TestSplitCopyToSamePrefixIsRefused(t *testing.T) {
+	m := newSplitModel(t)
+	updated, _ := m.Update(paneLoadedMsg{pane: 0, bucket: "test-bucket", prefix: "logs/", items: []list.Item{
+		item{key: "logs/a.log", displayKey: "a.log"},
+	}})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m = updated.(Model)
+	if m.statusMsg != "Both panes show the same prefix, nothing to do" {
+		t.Errorf("status = %q, want the same-prefix warning", m.statusMsg)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// sseAlgorithms are the --sse values S3 accepts.
+var sseAlgorithms = []types.ServerSideEncryption{
+	types.ServerSideEncryptionAes256,
+	types.ServerSideEncryptionAwsKms,
+	types.ServerSideEncryptionAwsKmsDsse,
+}
+
+// validateSSE checks --sse names an algorithm S3 knows, and that --sse-kms-key-id is only
+// given with one of the KMS algorithms.
+func validateSSE(sse, kmsKeyID string) error {
+	if sse != "" {
+		known := false
+		var names []string
+		for _, a := range sseAlgorithms {
+			known = known || string(a) == sse
+			names = append(names, string(a))
+		}
+		if !known {
+			return fmt.Errorf("--sse must be one of %s, got %q", strings.Join(names, ", "), sse)
+		}
+	}
+	if kmsKeyID != "" && !strings.HasPrefix(sse, "aws:kms") {
+		return fmt.Errorf("--sse-kms-key-id needs --sse %s or %s", types.ServerSideEncryptionAwsKms, types.ServerSideEncryptionAwsKmsDsse)
+	}
+	return nil
+}
+
+// encryptingClient asks S3 to encrypt every object it puts, whether an edit, an upload or
+// a folder, with the algorithm and KMS key given on the command line.
+type encryptingClient struct {
+	S3API
+	sse      types.ServerSideEncryption
+	kmsKeyID string
+}
+
+func (c encryptingClient) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	params.ServerSideEncryption = c.sse
+	if c.kmsKeyID != "" {
+		params.SSEKMSKeyId = aws.String(c.kmsKeyID)
+	}
+	return c.S3API.PutObject(ctx, params, optFns...)
+}
+
+// withEncryption wraps client to encrypt what it puts when --sse is set. Without it the
+// client is returned as it is, and the bucket's default encryption applies.
+func withEncryption(client S3API, opts options) S3API {
+	if opts.sse == "" {
+		return client
+	}
+	return encryptingClient{S3API: client, sse: types.ServerSideEncryption(opts.sse), kmsKeyID: opts.sseKMSKeyID}
+}
@@ -0,0 +1,82 @@
+// ABOUTME: Tests for server-side encryption of the objects s3n puts, in sse.go.
+// ABOUTME: Covers validating --sse and --sse-kms-key-id and the fields set on uploads and edits.
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// recordingPuts keeps the input of every PutObject call made through it.
+type recordingPuts struct {
+	*fakeS3
+	inputs []*s3.PutObjectInput
+}
+
+func (r *recordingPuts) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	r.inputs = append(r.inputs, params)
+	return r.fakeS3.PutObject(ctx, params, optFns...)
+}
+
+func TestValidateSSE(t *testing.T) {
+	valid := [][2]string{{"", ""}, {"AES256", ""}, {"aws:kms", ""}, {"aws:kms", "alias/my-key"}, {"aws:kms:dsse", "alias/my-key"}}
+	for _, v := range valid {
+		if err := validateSSE(v[0], v[1]); err != nil {
+			t.Errorf("validateSSE(%q, %q) = %v, want it accepted", v[0], v[1], err)
+		}
+	}
+	invalid := [][2]string{{"aes256", ""}, {"kms", ""}, {"", "alias/my-key"}, {"AES256", "alias/my-key"}}
+	for _, v := range invalid {
+		if err := validateSSE(v[0], v[1]); err == nil {
+			t.Errorf("validateSSE(%q, %q) accepted, want an error", v[0], v[1])
+		}
+	}
+}
+
+func TestPutsCarrySSEOnlyWhenSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	if err := os.WriteFile(path, []byte("a,b\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    options
+		wantSSE types.ServerSideEncryption
+		wantKMS string
+	}{
+		{name: "no flags", opts: options{}},
+		{name: "AES256", opts: options{sse: "AES256"}, wantSSE: types.ServerSideEncryptionAes256},
+		{name: "KMS key", opts: options{sse: "aws:kms", sseKMSKeyID: "alias/my-key"}, wantSSE: types.ServerSideEncryptionAwsKms, wantKMS: "alias/my-key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingPuts{fakeS3: newFakeS3(nil)}
+			client := withEncryption(recorder, tt.opts)
+
+			uploadFile(context.Background(), client, "b", "reports/report.csv", path, nil)()
+			if err := putFile(context.Background(), client, "b", "notes.txt", "text/plain", nil, "", path); err != nil {
+				t.Fatal(err)
+			}
+			createFolder(context.Background(), client, "b", "new/", "new/")()
+
+			if len(recorder.inputs) != 3 {
+				t.Fatalf("%d PutObject calls, want an upload, an edit and a folder", len(recorder.inputs))
+			}
+			for _, input := range recorder.inputs {
+				if input.ServerSideEncryption != tt.wantSSE {
+					t.Errorf("%s: ServerSideEncryption = %q, want %q", aws.ToString(input.Key), input.ServerSideEncryption, tt.wantSSE)
+				}
+				if got := aws.ToString(input.SSEKMSKeyId); got != tt.wantKMS || (tt.wantKMS == "" && input.SSEKMSKeyId != nil) {
+					t.Errorf("%s: SSEKMSKeyId = %v, want %q", aws.ToString(input.Key), input.SSEKMSKeyId, tt.wantKMS)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// startupProblem explains why the first listing of a bucket failed. It is shown full
+// screen instead of the error panel, since there is no listing behind it to fall back to.
+type startupProblem struct {
+	title    string
+	guidance []string
+}
+
+// classifyStartupError recognises the failures that mean the bucket cannot be listed at
+// all: a wrong name, missing permissions, or S3 being unreachable. Anything else returns
+// nil and is reported in the error panel as usual.
+func classifyStartupError(bucket string, err error) *startupProblem {
+	var noSuchBucket *types.NoSuchBucket
+	var apiErr smithy.APIError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &noSuchBucket):
+		return noSuchBucketProblem(bucket)
+	case errors.As(err, &apiErr):
+		switch apiErr.ErrorCode() {
+		case "NoSuchBucket":
+			return noSuchBucketProblem(bucket)
+		case "AccessDenied", "AllAccessDisabled":
+			return &startupProblem{
+				title: fmt.Sprintf("Access denied to bucket %q", bucket),
+				guidance: []string{
+					"Check that your credentials allow s3:ListBucket on this bucket.",
+					"Check that --profile (or AWS_PROFILE) selects the account you expect.",
+					"Check that --region (or AWS_REGION) is the bucket's region.",
+				},
+			}
+		}
+	case errors.As(err, &netErr):
+		return &startupProblem{
+			title: "Could not reach S3",
+			guidance: []string{
+				"Check your network connection.",
+				"Check the --endpoint URL if you use one.",
+				"Check that --region (or AWS_REGION) is a valid region.",
+			},
+		}
+	}
+	return nil
+}
+
+func noSuchBucketProblem(bucket string) *startupProblem {
+	return &startupProblem{
+		title: fmt.Sprintf("Bucket %q does not exist", bucket),
+		guidance: []string{
+			"Check the bucket name for typos.",
+			"Check that --region (or AWS_REGION) is the bucket's region.",
+			"Check the --endpoint URL if you use one.",
+		},
+	}
+}
+
+// screenMessage centres a title, some lines of text and a key hint in the window's width
+// and the given height.
+func (m Model) screenMessage(height int, title string, lines []string, bindings ...key.Binding) string {
+	var help []string
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+		help = append(help, helpStyleKey.Render(b.Help().Key)+" "+helpStyleVal.Render(b.Help().Desc))
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left, append(append([]string{title, ""}, lines...), "", strings.Join(help, " • "))...)
+	return lipgloss.Place(m.lastWindowSize.Width, max(height, 0), lipgloss.Center, lipgloss.Center, content)
+}
+
+// startupView shows why the bucket could not be listed, with the raw error below the guidance.
+func (m Model) startupView() string {
+	p := m.startupProblem
+	lines := make([]string, 0, len(p.guidance)+2)
+	for _, g := range p.guidance {
+		lines = append(lines, "• "+g)
+	}
+	lines = append(lines, "", lipgloss.NewStyle().Faint(true).Render(m.errMsg))
+	return m.screenMessage(m.lastWindowSize.Height, errorTitleStyle.Render(p.title), lines, m.keys.Retry, m.keys.Buckets, m.keys.Quit)
+}
+
+// isEmptyBucket reports whether the shown listing is the root of a bucket with no objects.
+func (m Model) isEmptyBucket() bool {
+	return m.listed && !m.loading && m.bucketName != "" && m.shownBucket == m.bucketName && m.currentPrefix == "" &&
+		m.searchTerm == "" && len(m.currentItems) == 0 && !m.hasMoreItems
+}
+
+func (m Model) emptyBucketView(height int) string {
+	title := m.list.Styles.Title.Render(m.list.Title)
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("Bucket is empty")}
+	return m.screenMessage(height, title, lines, m.keys.Upload, m.keys.NewFolder, m.keys.Buckets, m.keys.Quit)
+}
@@ -0,0 +1,104 @@
+// ABOUTME: Tests for the full-screen messages shown when a bucket cannot be listed or is empty.
+// ABOUTME: Covers mapping error codes to guidance and when the messages replace the list.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestClassifyStartupError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantTitle string
+		wantHint  string
+	}{
+		{"typed no such bucket", fmt.Errorf("list: %w", &types.NoSuchBucket{}), `Bucket "b" does not exist`, "typos"},
+		{"no such bucket code", &smithy.GenericAPIError{Code: "NoSuchBucket"}, `Bucket "b" does not exist`, "typos"},
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, `Access denied to bucket "b"`, "s3:ListBucket"},
+		{"all access disabled", &smithy.GenericAPIError{Code: "AllAccessDisabled"}, `Access denied to bucket "b"`, "--profile"},
+		{"network", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, "Could not reach S3", "--endpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := classifyStartupError("b", tt.err)
+			if p == nil {
+				t.Fatalf("expected a startup problem for %v", tt.err)
+			}
+			if p.title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", p.title, tt.wantTitle)
+			}
+			if !strings.Contains(strings.Join(p.guidance, "\n"), tt.wantHint) {
+				t.Errorf("guidance %q, want it to mention %q", p.guidance, tt.wantHint)
+			}
+		})
+	}
+
+	for _, err := range []error{errors.New("boom"), &smithy.GenericAPIError{Code: "SlowDown"}} {
+		if p := classifyStartupError("b", err); p != nil {
+			t.Errorf("classifyStartupError(%v) = %q, want nil so the error panel is used", err, p.title)
+		}
+	}
+}
+
+func TestFirstListingFailureIsShownFullScreen(t *testing.T) {
+	m := initialModel("missing-bucket", options{})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+
+	updated, _ = m.Update(errorMsg{err: &smithy.GenericAPIError{Code: "NoSuchBucket", Message: "The specified bucket does not exist"}})
+	m = updated.(Model)
+
+	view := m.View()
+	for _, want := range []string{`Bucket "missing-bucket" does not exist`, "Check the bucket name for typos.", "The specified bucket does not exist"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected the startup message to contain %q, got %q", want, view)
+		}
+	}
+	if strings.Contains(view, "No objects") {
+		t.Errorf("expected the empty list to be replaced, got %q", view)
+	}
+}
+
+func TestLaterListingFailureKeepsErrorPanel(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(itemsLoadedMsg{items: []list.Item{item{key: "a.txt", displayKey: "a.txt"}}})
+	m = updated.(Model)
+
+	updated, _ = m.Update(errorMsg{err: &smithy.GenericAPIError{Code: "AccessDenied"}})
+	m = updated.(Model)
+
+	if m.startupProblem != nil {
+		t.Errorf("expected a failure after the first listing to use the error panel, got %q", m.startupProblem.title)
+	}
+}
+
+func TestEmptyBucketIsExplained(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "Bucket is empty") {
+		t.Fatalf("expected no empty message before the first listing")
+	}
+
+	updated, _ = m.Update(itemsLoadedMsg{})
+	m = updated.(Model)
+	if !strings.Contains(m.View(), "Bucket is empty") {
+		t.Errorf("expected an empty bucket to say so, got %q", m.View())
+	}
+
+	m.currentPrefix = "logs/"
+	m.shownPrefix = "logs/"
+	if strings.Contains(m.View(), "Bucket is empty") {
+		t.Errorf("expected an empty directory to show the list as usual")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// archived reports whether the object sits in an archive tier and must be restored
+// before GetObject can read it.
+func (i item) archived() bool {
+	switch types.ObjectStorageClass(i.storageClass) {
+	case types.ObjectStorageClassGlacier, types.ObjectStorageClassDeepArchive:
+		return !i.restored()
+	}
+	return false
+}
+
+func (i item) restored() bool {
+	return !i.restoredUntil.IsZero()
+}
+
+// storageDescription names a non-standard storage class and, for archived objects, how
+// far a restore has got; it is empty for STANDARD objects.
+func (i item) storageDescription() string {
+	if i.storageClass == "" || i.storageClass == string(types.ObjectStorageClassStandard) {
+		return ""
+	}
+	d := "Storage: " + i.storageClass
+	switch {
+	case i.restoring:
+		d += " (restoring)"
+	case i.restored():
+		d += fmt.Sprintf(" (restored until %s)", i.restoredUntil.Format("2006-01-02"))
+	}
+	return d
+}
+
+// withStorage copies the storage class and restore status of obj onto i.
+func (i item) withStorage(obj types.Object) item {
+	i.storageClass = string(obj.StorageClass)
+	if obj.RestoreStatus != nil {
+		i.restoring = aws.BoolValue(obj.RestoreStatus.IsRestoreInProgress)
+		i.restoredUntil = aws.TimeValue(obj.RestoreStatus.RestoreExpiryDate)
+	}
+	return i
+}
@@ -0,0 +1,50 @@
+// ABOUTME: Tests for storage class and restore status display in storage.go.
+// ABOUTME: Covers archived objects, restores in progress or done, and hiding STANDARD.
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestGlacierObjectShowsStorageClass(t *testing.T) {
+	items := itemsFromListing(&s3.ListObjectsV2Output{Contents: []types.Object{
+		{Key: aws.String("old.log"), Size: aws.Int64(1), StorageClass: types.ObjectStorageClassGlacier},
+		{Key: aws.String("new.log"), Size: aws.Int64(1), StorageClass: types.ObjectStorageClassStandard},
+	}}, "", "")
+
+	glacier := items[0]
+	if d := glacier.Description(); !strings.Contains(d, "Storage: GLACIER") {
+		t.Errorf("description %q lacks the storage class", d)
+	}
+	if title := glacier.Title(); !strings.HasPrefix(title, "🧊 ") {
+		t.Errorf("title %q lacks the archive icon", title)
+	}
+	if d := items[1].Description(); strings.Contains(d, "Storage") {
+		t.Errorf("expected STANDARD to be left out, got %q", d)
+	}
+}
+
+func TestRestoreStatus(t *testing.T) {
+	until := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	restoring := item{key: "a", displayKey: "a"}.withStorage(types.Object{
+		StorageClass:  types.ObjectStorageClassDeepArchive,
+		RestoreStatus: &types.RestoreStatus{IsRestoreInProgress: aws.Bool(true)},
+	})
+	restored := item{key: "b", displayKey: "b"}.withStorage(types.Object{
+		StorageClass:  types.ObjectStorageClassGlacier,
+		RestoreStatus: &types.RestoreStatus{IsRestoreInProgress: aws.Bool(false), RestoreExpiryDate: &until},
+	})
+
+	if d := restoring.Description(); !strings.Contains(d, "Storage: DEEP_ARCHIVE (restoring)") || !restoring.archived() {
+		t.Errorf("restoring object: %q archived=%v", d, restoring.archived())
+	}
+	if d := restored.Description(); !strings.Contains(d, "Storage: GLACIER (restored until 2030-01-02)") || restored.archived() {
+		t.Errorf("restored object: %q archived=%v", d, restored.archived())
+	}
+}
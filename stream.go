@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// continueStreaming reports whether, with --all-pages, the page after the one just shown
+// should be listed and appended without waiting for 'n'. Streaming stops at --max-items.
+func (m Model) continueStreaming(hasMore bool) bool {
+	return m.opts.allPages && hasMore && (m.opts.maxItems == 0 || len(m.currentItems) < m.opts.maxItems)
+}
+
+// streamedPage returns the page a streaming listing asked for, to be appended right away.
+// It is handled in the same Update as its arrival, so a page of a listing the user has
+// since left, whose prefetch reload cancelled, is never appended to the new one.
+func (m *Model) streamedPage(msg prefetchedMsg) (tea.Msg, bool) {
+	p := m.prefetch
+	if !m.streaming || p == nil || p.key != msg.key || p.token != msg.token {
+		return nil, false
+	}
+	m.cancelPrefetch()
+	return msg.msg, true
+}
+
+// stopStreaming leaves the listing at the pages shown so far; 'n' loads the rest.
+func (m *Model) stopStreaming() tea.Cmd {
+	m.cancelPrefetch()
+	m.streaming = false
+	m.loadingMore = false
+	return m.setStatus(fmt.Sprintf("Stopped listing at %s, press '%s' for the next page", listingSummary(m.currentItems, true), m.keys.NextPage.Help().Key))
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Tests for streaming the pages of a listing with --all-pages in stream.go.
+// ABOUTME: Covers appending pages in order as they arrive, dropping pages of a left listing and stopping with esc.
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// streamingModel lists logs/ two keys per page with --all-pages and shows the first page.
+func streamingModel(t *testing.T) Model {
+	t.Helper()
+	client := newFakeS3(map[string]string{
+		"logs/a.log": "a", "logs/b.log": "b", "logs/c.log": "c", "logs/d.log": "d", "logs/e.log": "e",
+	})
+	m := initialModel("test-bucket", options{allPages: true, pageSize: 2})
+	m.client = client
+	m.currentPrefix = "logs/"
+	updated, _ := m.Update(m.listItems(context.Background()))
+	return updated.(Model)
+}
+
+// arrive delivers the page the model prefetched, as its background request would.
+func arrive(m Model) Model {
+	key, token := m.prefetch.key, m.prefetch.token
+	page := m.listItems(context.Background())
+	updated, _ := m.Update(prefetchedMsg{key: key, token: token, msg: page})
+	return updated.(Model)
+}
+
+func TestAllPagesStreamsPagesInOrder(t *testing.T) {
+	m := streamingModel(t)
+	if !m.streaming || m.prefetch == nil {
+		t.Fatalf("expected the second page to be listed right after the first")
+	}
+
+	var shown []int
+	for m.streaming {
+		shown = append(shown, len(m.list.Items()))
+		m = arrive(m)
+	}
+	shown = append(shown, len(m.list.Items()))
+	if !reflect.DeepEqual(shown, []int{2, 4, 5}) {
+		t.Errorf("items shown after each page = %v, want [2 4 5]", shown)
+	}
+	want := []string{"logs/a.log", "logs/b.log", "logs/c.log", "logs/d.log", "logs/e.log"}
+	if got := keysOf(m.list.Items()); !reflect.DeepEqual(got, want) {
+		t.Errorf("items = %v, want %v", got, want)
+	}
+	if m.loadingMore || m.hasMoreItems || m.statusMsg != "Showing 5 objects, 5 B (End of list)" {
+		t.Errorf("expected the listing to be complete, loadingMore %v, status %q", m.loadingMore, m.statusMsg)
+	}
+}
+
+func TestAllPagesStopsAtMaxItems(t *testing.T) {
+	client := newFakeS3(map[string]string{"a": "", "b": "", "c": "", "d": "", "e": ""})
+	m := initialModel("test-bucket", options{allPages: true, pageSize: 2, maxItems: 3})
+	m.client = client
+	updated, _ := m.Update(m.listItems(context.Background()))
+	m = arrive(updated.(Model))
+	if m.streaming || !m.hasMoreItems || len(m.list.Items()) != 4 {
+		t.Errorf("expected streaming to stop past --max-items with more left for n, got %d items", len(m.list.Items()))
+	}
+}
+
+func TestStreamedPageOfLeftListingIsDropped(t *testing.T) {
+	m := streamingModel(t)
+	key, token := m.prefetch.key, m.prefetch.token
+	page := m.listItems(context.Background())
+
+	m.currentPrefix = "other/"
+	m.reload()
+	updated, _ := m.Update(prefetchedMsg{key: key, token: token, msg: page})
+	m = updated.(Model)
+	if n := len(m.currentItems); n != 2 {
+		t.Errorf("expected the page of logs/ not to be appended after leaving it, got %d items", n)
+	}
+	if m.streaming || m.loadingMore {
+		t.Errorf("expected leaving the listing to stop streaming")
+	}
+}
+
+func TestEscStopsStreaming(t *testing.T) {
+	m := streamingModel(t)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.streaming || m.loadingMore || m.prefetch != nil {
+		t.Fatalf("expected esc to stop listing more pages")
+	}
+	if !m.hasMoreItems {
+		t.Errorf("expected the rest to stay available with n")
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// sizeTotal counts objects and adds up their sizes.
+type sizeTotal struct {
+	objects int
+	bytes   int64
+}
+
+func (t *sizeTotal) add(size int64) {
+	t.objects++
+	t.bytes += size
+}
+
+// prefixSummary totals every object under a prefix, at any depth, broken down by the
+// first path segment below the prefix and by storage class.
+type prefixSummary struct {
+	bucket         string
+	prefix         string
+	total          sizeTotal
+	byTopLevel     map[string]*sizeTotal // "" for objects directly under the prefix
+	byStorageClass map[string]*sizeTotal
+	computed       time.Time
+}
+
+// summaryMsg carries a finished summary.
+type summaryMsg struct {
+	summary *prefixSummary
+}
+
+func newPrefixSummary(bucket, prefix string) *prefixSummary {
+	return &prefixSummary{
+		bucket:         bucket,
+		prefix:         prefix,
+		byTopLevel:     map[string]*sizeTotal{},
+		byStorageClass: map[string]*sizeTotal{},
+	}
+}
+
+// add counts obj into the total and its groups.
+func (s *prefixSummary) add(obj types.Object) {
+	size := aws.Int64Value(obj.Size)
+	s.total.add(size)
+
+	top := strings.TrimPrefix(aws.StringValue(obj.Key), s.prefix)
+	if i := strings.Index(top, "/"); i >= 0 {
+		top = top[:i+1]
+	} else {
+		top = ""
+	}
+	group(s.byTopLevel, top).add(size)
+
+	class := string(obj.StorageClass)
+	if class == "" {
+		class = string(types.ObjectStorageClassStandard)
+	}
+	group(s.byStorageClass, class).add(size)
+}
+
+func group(groups map[string]*sizeTotal, name string) *sizeTotal {
+	t, ok := groups[name]
+	if !ok {
+		t = &sizeTotal{}
+		groups[name] = t
+	}
+	return t
+}
+
+// summarize walks every object under prefix, visiting at most limit objects (0 for no
+// limit), and counts each into scanned as it goes so the operation can show how far it
+// got. Hitting limit shows no partial totals and asks whether to summarize everything.
+func summarize(ctx context.Context, client S3API, bucket, prefix string, limit int, scanned *atomic.Int64) tea.Msg {
+	s := newPrefixSummary(bucket, prefix)
+	err := walkObjects(ctx, client, bucket, prefix, limit, func(obj types.Object) error {
+		s.add(obj)
+		scanned.Add(1)
+		return nil
+	})
+	var maxErr *maxItemsError
+	if errors.As(err, &maxErr) {
+		return overCapMsg{
+			question: fmt.Sprintf("More than %s objects under %s. Summarize them all?", humanize.Comma(int64(limit)), s3URI(bucket, prefix)),
+			proceed:  summaryRequestMsg{bucket: bucket, prefix: prefix, limit: 0},
+		}
+	}
+	if err != nil {
+		return errorMsg{err: fmt.Errorf("failed to summarize %s: %w", s3URI(bucket, prefix), err), retry: func() tea.Msg {
+			return summaryRequestMsg{bucket: bucket, prefix: prefix, limit: limit}
+		}}
+	}
+	s.computed = time.Now()
+	return summaryMsg{summary: s}
+}
+
+// summaryRequestMsg asks for the summary of prefix again, from the error panel, visiting
+// at most limit objects (0 for no limit).
+type summaryRequestMsg struct {
+	bucket string
+	prefix string
+	limit  int
+}
+
+// formatSummary renders a summary for the viewer, each breakdown largest first.
+func formatSummary(s *prefixSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-9s %s\n", "Objects:", humanize.Comma(int64(s.total.objects)))
+	fmt.Fprintf(&b, "%-9s %s (%s bytes)\n", "Size:", humanize.Bytes(uint64(s.total.bytes)), humanize.Comma(s.total.bytes))
+	fmt.Fprintf(&b, "%-9s %s\n", "Computed:", s.computed.Format("2006-01-02 15:04:05 MST"))
+
+	b.WriteString("\nBy storage class:\n")
+	writeGroups(&b, s.byStorageClass, "")
+	b.WriteString("\nBy top-level prefix:\n")
+	writeGroups(&b, s.byTopLevel, "(directly under "+s3URI(s.bucket, s.prefix)+")")
+	return b.String()
+}
+
+// writeGroups lists groups by size, then name; unnamed labels the "" group.
+func writeGroups(b *strings.Builder, groups map[string]*sizeTotal, unnamed string) {
+	if len(groups) == 0 {
+		b.WriteString("  (none)\n")
+		return
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, c := groups[names[i]], groups[names[j]]
+		if a.bytes != c.bytes {
+			return a.bytes > c.bytes
+		}
+		return names[i] < names[j]
+	})
+	for _, name := range names {
+		t := groups[name]
+		label := name
+		if label == "" {
+			label = unnamed
+		}
+		unit := "objects"
+		if t.objects == 1 {
+			unit = "object"
+		}
+		fmt.Fprintf(b, "  %-30s %12s %-7s %10s\n", label, humanize.Comma(int64(t.objects)), unit, humanize.Bytes(uint64(t.bytes)))
+	}
+}
+
+// startSummary totals everything under prefix, up to limit objects, as a cancellable
+// operation. A summary already computed this session is shown again without listing anything.
+func (m *Model) startSummary(bucket, prefix string, limit int) tea.Cmd {
+	if s, ok := m.cache.getSummary(bucket, prefix); ok {
+		return func() tea.Msg { return summaryMsg{summary: s} }
+	}
+	client := m.client
+	scanned := &atomic.Int64{}
+	cmd := m.startOperation("Summarizing "+s3URI(bucket, prefix), func(ctx context.Context) tea.Msg {
+		return summarize(ctx, client, bucket, prefix, limit, scanned)
+	})
+	m.operation.scanned = scanned
+	return cmd
+}
+
+// openSummary shows a summary in the built-in viewer.
+func (m *Model) openSummary(s *prefixSummary) {
+	viewer := NewViewModel("Summary: "+s3URI(s.bucket, s.prefix), "", formatSummary(s), m.lastWindowSize.Width, m.lastWindowSize.Height)
+	m.viewer = &viewer
+}
@@ -0,0 +1,124 @@
+// ABOUTME: Tests for the size and count summary of a prefix tree in summary.go.
+// ABOUTME: Covers grouping by storage class and top-level prefix over several pages, the --max-items cap and reusing a summary.
+package main
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSummarizeGroupsAcrossPages(t *testing.T) {
+	client := newFakeS3(map[string]string{
+		"data/logs/a.log":      "aaaa",
+		"data/logs/2024/b.log": "bb",
+		"data/img/c.png":       "cccccc",
+		"data/readme.txt":      "r",
+		"other/d.txt":          "ignored",
+	})
+	client.objects["data/img/c.png"] = fakeObject{body: []byte("cccccc"), storageClass: types.ObjectStorageClassGlacier}
+	client.pageSize = 2
+
+	var scanned atomic.Int64
+	msg, ok := summarize(context.Background(), client, "b", "data/", 0, &scanned).(summaryMsg)
+	if !ok {
+		t.Fatalf("expected a summary")
+	}
+	s := msg.summary
+
+	if got := client.called("ListObjectsV2"); got != 2 {
+		t.Errorf("%d ListObjectsV2 calls, want the 4 objects listed 2 per page", got)
+	}
+	if s.total != (sizeTotal{objects: 4, bytes: 13}) || scanned.Load() != 4 {
+		t.Errorf("total = %+v (scanned %d), want 4 objects of 13 bytes", s.total, scanned.Load())
+	}
+	wantTop := map[string]sizeTotal{"logs/": {2, 6}, "img/": {1, 6}, "": {1, 1}}
+	for name, want := range wantTop {
+		if got := s.byTopLevel[name]; got == nil || *got != want {
+			t.Errorf("byTopLevel[%q] = %v, want %+v", name, got, want)
+		}
+	}
+	if len(s.byTopLevel) != len(wantTop) {
+		t.Errorf("byTopLevel has %d groups, want %d", len(s.byTopLevel), len(wantTop))
+	}
+	if got := *s.byStorageClass["STANDARD"]; got != (sizeTotal{3, 7}) {
+		t.Errorf("STANDARD = %+v, want 3 objects of 7 bytes", got)
+	}
+	if got := *s.byStorageClass["GLACIER"]; got != (sizeTotal{1, 6}) {
+		t.Errorf("GLACIER = %+v, want 1 object of 6 bytes", got)
+	}
+
+	text := formatSummary(s)
+	for _, want := range []string{"Objects:  4", "GLACIER", "logs/", "(directly under s3://b/data/)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected the summary to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+func TestSummaryIsReusedUntilSomethingChanges(t *testing.T) {
+	client := newFakeS3(map[string]string{"a.txt": "a", "dir/b.txt": "b"})
+	m := initialModel("test-bucket", options{})
+	m.client = client
+	m.loading = false
+
+	summarizeRoot := func() {
+		t.Helper()
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'$'}})
+		m = updated.(Model)
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			msg = batch[0]().(operationDoneMsg)
+		}
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+		if m.viewer == nil || !strings.Contains(m.viewer.body, "Objects:  2") {
+			t.Fatalf("expected the summary in the viewer")
+		}
+		m.viewer = nil
+	}
+
+	summarizeRoot()
+	summarizeRoot()
+	if got := client.called("ListObjectsV2"); got != 1 {
+		t.Errorf("%d ListObjectsV2 calls, want the second summary from the cache", got)
+	}
+
+	m.cache.invalidateKey("test-bucket", "dir/new.txt")
+	summarizeRoot()
+	if got := client.called("ListObjectsV2"); got != 2 {
+		t.Errorf("%d ListObjectsV2 calls, want a change in the bucket to recompute it", got)
+	}
+}
+
+func TestSummaryStopsAtMaxItemsAndAsksToGoOn(t *testing.T) {
+	client := newFakeS3(map[string]string{"a.txt": "a", "b.txt": "b", "c.txt": "c"})
+	m := initialModel("test-bucket", options{maxItems: 2})
+	m.client = client
+	m.loading = false
+
+	updated, _ := m.Update(operationResult(t, m.startSummary("test-bucket", "", m.opts.maxItems)))
+	m = updated.(Model)
+	if m.overCap == nil || !strings.Contains(m.footer(), "More than 2 objects under s3://test-bucket/. Summarize them all? (y/N)") {
+		t.Fatalf("expected the cap to ask before going on, footer %q", m.footer())
+	}
+	if m.viewer != nil {
+		t.Errorf("expected no partial summary")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if m := updated.(Model); m.overCap != nil || m.operation != nil {
+		t.Errorf("expected n to leave the summary stopped")
+	}
+
+	updated, proceed := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	updated, cmd := updated.(Model).Update(proceed())
+	updated, _ = updated.(Model).Update(operationResult(t, cmd))
+	if m = updated.(Model); m.viewer == nil || !strings.Contains(m.viewer.body, "Objects:  3") {
+		t.Errorf("expected y to total all 3 objects, err %q", m.errMsg)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// S3 limits on object tags.
+const (
+	maxTags           = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// tagsLoadedMsg carries the tags of key; saved is set when they were re-read after a write.
+type tagsLoadedMsg struct {
+	key   string
+	tags  []types.Tag
+	saved bool
+}
+
+// tagsSavedMsg reports that the tag set of key was replaced.
+type tagsSavedMsg struct {
+	key string
+}
+
+type tagFormKeyMap struct {
+	Next   key.Binding
+	Prev   key.Binding
+	AddRow key.Binding
+	Save   key.Binding
+	Cancel key.Binding
+}
+
+func newTagFormKeyMap() tagFormKeyMap {
+	return tagFormKeyMap{
+		Next:   key.NewBinding(key.WithKeys("tab", "down"), key.WithHelp("tab", "next field")),
+		Prev:   key.NewBinding(key.WithKeys("shift+tab", "up"), key.WithHelp("shift+tab", "previous field")),
+		AddRow: key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "add tag")),
+		Save:   key.NewBinding(key.WithKeys("ctrl+s", "enter"), key.WithHelp("enter", "save")),
+		Cancel: key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "cancel")),
+	}
+}
+
+// tagForm edits the tag set of one object: inputs alternate key, value, key, value...
+// A row whose key is left empty is dropped on save.
+type tagForm struct {
+	key    string
+	inputs []textinput.Model
+	focus  int
+	keys   tagFormKeyMap
+}
+
+// loadTags fetches the tag set of key, for the form or to confirm a save.
+func loadTags(ctx context.Context, client S3API, bucket, key string, saved bool) tea.Cmd {
+	return func() tea.Msg {
+		output, err := client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to read the tags of %s: %w", key, err), retry: loadTags(ctx, client, bucket, key, saved)}
+		}
+		return tagsLoadedMsg{key: key, tags: output.TagSet, saved: saved}
+	}
+}
+
+// putTags replaces the tag set of key.
+func putTags(ctx context.Context, client S3API, bucket, key string, tags []types.Tag) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+			Bucket:  aws.String(bucket),
+			Key:     aws.String(key),
+			Tagging: &types.Tagging{TagSet: tags},
+		})
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to save the tags of %s: %w", key, err), retry: putTags(ctx, client, bucket, key, tags)}
+		}
+		return tagsSavedMsg{key: key}
+	}
+}
+
+// validateTags checks tags against the S3 limits so an invalid set is never sent.
+func validateTags(tags []types.Tag) error {
+	if len(tags) > maxTags {
+		return fmt.Errorf("an object can have at most %d tags, got %d", maxTags, len(tags))
+	}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		k, v := aws.StringValue(t.Key), aws.StringValue(t.Value)
+		if n := utf8.RuneCountInString(k); n > maxTagKeyLength {
+			return fmt.Errorf("tag key %q is %d characters, the limit is %d", k, n, maxTagKeyLength)
+		}
+		if n := utf8.RuneCountInString(v); n > maxTagValueLength {
+			return fmt.Errorf("value of tag %q is %d characters, the limit is %d", k, n, maxTagValueLength)
+		}
+		if seen[k] {
+			return fmt.Errorf("tag %q is set twice", k)
+		}
+		seen[k] = true
+	}
+	return nil
+}
+
+// tagInputs turns a tag set into form inputs, plus an empty row to add a tag.
+func tagInputs(tags []types.Tag) []textinput.Model {
+	var inputs []textinput.Model
+	for _, t := range tags {
+		inputs = append(inputs, tagInput("key", aws.StringValue(t.Key)), tagInput("value", aws.StringValue(t.Value)))
+	}
+	return append(inputs, tagInput("key", ""), tagInput("value", ""))
+}
+
+func tagInput(placeholder, value string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.Prompt = ""
+	input.Width = 40
+	input.SetValue(value)
+	return input
+}
+
+// formTags reads the tag set back from the form inputs, skipping rows without a key.
+func formTags(inputs []textinput.Model) []types.Tag {
+	var tags []types.Tag
+	for i := 0; i+1 < len(inputs); i += 2 {
+		k := strings.TrimSpace(inputs[i].Value())
+		if k == "" {
+			continue
+		}
+		tags = append(tags, types.Tag{Key: aws.String(k), Value: aws.String(inputs[i+1].Value())})
+	}
+	return tags
+}
+
+// openTagForm starts editing the tags of key.
+func (m *Model) openTagForm(key string, tags []types.Tag) tea.Cmd {
+	m.tagForm = &tagForm{key: key, inputs: tagInputs(tags), keys: newTagFormKeyMap()}
+	return m.tagForm.inputs[0].Focus()
+}
+
+// updateTagForm handles a key press while the tag form is open.
+func (m Model) updateTagForm(msg tea.KeyMsg) (Model, tea.Cmd) {
+	f := m.tagForm
+	switch {
+	case key.Matches(msg, f.keys.Cancel):
+		m.tagForm = nil
+		return m, nil
+	case key.Matches(msg, f.keys.Save):
+		tags := formTags(f.inputs)
+		if err := validateTags(tags); err != nil {
+			m.setErrorStatus(err.Error())
+			return m, nil
+		}
+		m.tagForm = nil
+		if m.opts.dryRun {
+			cmd := m.setStatus(skipped("PutObjectTagging", m.bucketName, f.key).String())
+			return m, cmd
+		}
+		return m, putTags(m.ctx, m.client, m.bucketName, f.key, tags)
+	case key.Matches(msg, f.keys.AddRow):
+		f.inputs = append(f.inputs, tagInput("key", ""), tagInput("value", ""))
+		cmd := f.focusOn(len(f.inputs) - 2)
+		return m, cmd
+	case key.Matches(msg, f.keys.Next):
+		cmd := f.focusOn((f.focus + 1) % len(f.inputs))
+		return m, cmd
+	case key.Matches(msg, f.keys.Prev):
+		cmd := f.focusOn((f.focus + len(f.inputs) - 1) % len(f.inputs))
+		return m, cmd
+	}
+	if m.statusIsError {
+		m.clearStatus()
+	}
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return m, cmd
+}
+
+func (f *tagForm) focusOn(idx int) tea.Cmd {
+	f.inputs[f.focus].Blur()
+	f.focus = idx
+	return f.inputs[idx].Focus()
+}
+
+func (m Model) tagFormView() string {
+	f := m.tagForm
+	rows := []string{m.list.Styles.Title.Render("Tags of " + s3URI(m.bucketName, f.key))}
+	for i := 0; i+1 < len(f.inputs); i += 2 {
+		rows = append(rows, fmt.Sprintf("%s = %s", f.inputs[i].View(), f.inputs[i+1].View()))
+	}
+	var help []string
+	for _, b := range []key.Binding{f.keys.Next, f.keys.AddRow, f.keys.Save, f.keys.Cancel} {
+		help = append(help, fmt.Sprintf("%s %s", helpStyleKey.Render(b.Help().Key), helpStyleVal.Render(b.Help().Desc)))
+	}
+	rows = append(rows, "", "Clear a key to remove its tag. "+strings.Join(help, " • "))
+	if m.showStatusMsg {
+		rows = append(rows, m.statusMsg)
+	}
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
@@ -0,0 +1,87 @@
+// ABOUTME: Tests for editing object tags in tags.go.
+// ABOUTME: Covers the S3 tag limits and reading a tag set in and out of the form inputs.
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func tag(k, v string) types.Tag {
+	return types.Tag{Key: aws.String(k), Value: aws.String(v)}
+}
+
+func TestValidateTags(t *testing.T) {
+	var eleven []types.Tag
+	for _, k := range strings.Split("a b c d e f g h i j k", " ") {
+		eleven = append(eleven, tag(k, "v"))
+	}
+	tests := []struct {
+		name string
+		tags []types.Tag
+		want string
+	}{
+		{"valid", []types.Tag{tag("env", "prod"), tag("team", "")}, ""},
+		{"empty", nil, ""},
+		{"too many", eleven, "at most 10 tags"},
+		{"long key", []types.Tag{tag(strings.Repeat("k", 129), "v")}, "the limit is 128"},
+		{"long value", []types.Tag{tag("k", strings.Repeat("v", 257))}, "the limit is 256"},
+		{"limits", []types.Tag{tag(strings.Repeat("k", 128), strings.Repeat("v", 256))}, ""},
+		{"duplicate", []types.Tag{tag("env", "a"), tag("env", "b")}, `tag "env" is set twice`},
+	}
+	for _, tt := range tests {
+		err := validateTags(tt.tags)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestTagInputsRoundTrip(t *testing.T) {
+	tags := []types.Tag{tag("env", "prod"), tag("owner", "")}
+	inputs := tagInputs(tags)
+	if len(inputs) != 6 {
+		t.Fatalf("got %d inputs, want 2 rows plus an empty one", len(inputs))
+	}
+	if got := formTags(inputs); !reflect.DeepEqual(got, tags) {
+		t.Errorf("round trip = %v, want %v", got, tags)
+	}
+}
+
+func TestFormTagsDropsRowsWithoutKey(t *testing.T) {
+	inputs := tagInputs([]types.Tag{tag("env", "prod"), tag("old", "x")})
+	inputs[2].SetValue("  ")
+	got := formTags(inputs)
+	if want := []types.Tag{tag("env", "prod")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+}
+
+func TestTagFormRejectsInvalidSet(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.openTagForm("a.txt", []types.Tag{tag("env", "a")})
+	m.tagForm.inputs[2].SetValue("env")
+
+	m, cmd := m.updateTagForm(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd != nil {
+		t.Error("an invalid tag set must not be saved")
+	}
+	if m.tagForm == nil {
+		t.Error("the form should stay open to fix the tags")
+	}
+	if !m.statusIsError || !strings.Contains(m.statusMsg, "set twice") {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mtyurt/s3n/logger"
+)
+
+// tmpFilePrefix starts the name of every temp file, so leftovers can be recognised.
+const tmpFilePrefix = "s3n-"
+
+// staleTmpFileAge is how old a leftover temp file must be before sweepStaleTmpFiles
+// removes it; younger ones may belong to another s3n that is still running.
+const staleTmpFileAge = 24 * time.Hour
+
+// tmpFileNameReplacer flattens an object key into a single file name component.
+var tmpFileNameReplacer = strings.NewReplacer("/", "_", string(os.PathSeparator), "_")
+
+// tmpFiles tracks the temp files handed to the pager or editor so whatever is
+// still around when the program exits can be removed.
+var tmpFiles = struct {
+	sync.Mutex
+	paths map[string]struct{}
+}{paths: map[string]struct{}{}}
+
+// writeToTmpFile writes metadata followed by the reader's contents to a new temp file in dir
+// (os.TempDir() when empty). fileName is kept as the suffix so editors still detect the file type;
+// path separators in it are replaced, so it may be an object key.
+func writeToTmpFile(dir, metadata string, reader io.Reader, fileName string) (string, error) {
+	tmpFile, err := os.CreateTemp(dir, tmpFilePrefix+"*-"+tmpFileNameReplacer.Replace(fileName))
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tmpFile.Close()
+	trackTmpFile(tmpFile.Name())
+
+	// Copy the contents of the reader to the temporary file
+	if metadata != "" {
+		if _, err := io.WriteString(tmpFile, metadata); err != nil {
+			return "", fmt.Errorf("failed to write metadata to temp file: %w", err)
+		}
+	}
+	if reader != nil {
+		if _, err := io.Copy(tmpFile, reader); err != nil {
+			return "", fmt.Errorf("failed to write to temp file: %w", err)
+		}
+	}
+
+	// Return the path to the temporary file
+	return tmpFile.Name(), nil
+}
+
+// fileHash returns the SHA-256 of a file's contents, used to tell whether an edit changed anything.
+func fileHash(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func trackTmpFile(path string) {
+	tmpFiles.Lock()
+	defer tmpFiles.Unlock()
+	tmpFiles.paths[path] = struct{}{}
+}
+
+// removeTmpFile deletes a temp file and stops tracking it.
+func removeTmpFile(path string) error {
+	tmpFiles.Lock()
+	delete(tmpFiles.paths, path)
+	tmpFiles.Unlock()
+	return os.Remove(path)
+}
+
+// keepTmpFile stops tracking a temp file so it survives cleanupTmpFiles.
+func keepTmpFile(path string) {
+	tmpFiles.Lock()
+	defer tmpFiles.Unlock()
+	delete(tmpFiles.paths, path)
+}
+
+// cleanupTmpFiles removes every temp file that is still tracked.
+func cleanupTmpFiles() {
+	tmpFiles.Lock()
+	defer tmpFiles.Unlock()
+	for path := range tmpFiles.paths {
+		os.Remove(path)
+		delete(tmpFiles.paths, path)
+	}
+}
+
+// cleanupOnSignal removes the tracked temp files when s3n is told to terminate or loses
+// its terminal, then calls quit. SIGINT is not watched: in raw mode ctrl+c arrives as a
+// key, and while the pager runs an interrupt is the pager's to handle.
+func cleanupOnSignal(signals <-chan os.Signal, quit func()) {
+	for sig := range signals {
+		if sig != syscall.SIGTERM && sig != syscall.SIGHUP {
+			continue
+		}
+		logger.Infof("received %v, removing temp files", sig)
+		cleanupTmpFiles()
+		quit()
+		return
+	}
+}
+
+// sweepStaleTmpFiles removes temp files in dir (os.TempDir() when empty) that an earlier
+// s3n left behind, such as one that was killed. Only untracked files named with
+// tmpFilePrefix and last modified more than olderThan ago are removed.
+func sweepStaleTmpFiles(dir string, olderThan time.Duration) (int, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, tmpFilePrefix+"*"))
+	if err != nil {
+		return 0, err
+	}
+	tmpFiles.Lock()
+	defer tmpFiles.Unlock()
+	removed := 0
+	for _, path := range paths {
+		if _, tracked := tmpFiles.paths[path]; tracked {
+			continue
+		}
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || time.Since(info.ModTime()) < olderThan {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			logger.Warnf("failed to remove stale temp file %s: %v", path, err)
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,168 @@
+// ABOUTME: Tests for temp file creation and cleanup in tmpfile.go.
+// ABOUTME: Covers unique names per download, removal of leftover files on exit or on a signal, and sweeping stale files.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWriteToTmpFileCreatesUniqueFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := writeToTmpFile(dir, "", strings.NewReader("one"), "bucket-a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := writeToTmpFile(dir, "", strings.NewReader("two"), "bucket-a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first == second {
+		t.Errorf("expected viewing the same key twice to use different files, both got %q", first)
+	}
+	if filepath.Dir(first) != dir {
+		t.Errorf("expected file in %q, got %q", dir, first)
+	}
+	if !strings.HasSuffix(first, "bucket-a.txt") {
+		t.Errorf("expected the key name to be kept as suffix, got %q", first)
+	}
+}
+
+func TestWriteToTmpFileFlattensNestedKeys(t *testing.T) {
+	dir := t.TempDir()
+	for _, key := range []string{"bucket-logs/2024/app.log", "bucket-a/b/c/", "bucket-/leading.txt"} {
+		path, err := writeToTmpFile(dir, "", strings.NewReader("x"), key)
+		if err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if filepath.Dir(path) != dir {
+			t.Errorf("%s: expected the file directly in %q, got %q", key, dir, path)
+		}
+		if err := os.WriteFile(path, []byte("edited"), 0o600); err != nil {
+			t.Errorf("%s: path %q is not writable: %v", key, path, err)
+		}
+	}
+	path, _ := writeToTmpFile(dir, "", nil, "bucket-logs/2024/app.log")
+	if !strings.HasSuffix(path, "bucket-logs_2024_app.log") {
+		t.Errorf("expected the flattened key as suffix, got %q", path)
+	}
+}
+
+func TestCleanupTmpFilesRemovesTrackedFiles(t *testing.T) {
+	dir := t.TempDir()
+	path, err := writeToTmpFile(dir, "header\n", strings.NewReader("body"), "bucket-b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cleanupTmpFiles()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %q to be removed on cleanup", path)
+	}
+}
+
+func TestRemoveTmpFileStopsTracking(t *testing.T) {
+	dir := t.TempDir()
+	removed, _ := writeToTmpFile(dir, "", strings.NewReader("a"), "a.txt")
+	kept, _ := writeToTmpFile(dir, "", strings.NewReader("b"), "b.txt")
+
+	if err := removeTmpFile(removed); err != nil {
+		t.Fatal(err)
+	}
+	keepTmpFile(kept)
+	cleanupTmpFiles()
+
+	if _, err := os.Stat(removed); !os.IsNotExist(err) {
+		t.Errorf("expected %q to be removed", removed)
+	}
+	if _, err := os.Stat(kept); err != nil {
+		t.Errorf("expected a kept file to survive cleanup: %v", err)
+	}
+}
+
+func TestCleanupOnSignalRemovesTrackedFiles(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("body"), "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signals := make(chan os.Signal, 2)
+	signals <- syscall.SIGINT
+	signals <- syscall.SIGTERM
+	quit := false
+
+	cleanupOnSignal(signals, func() { quit = true })
+
+	if !quit {
+		t.Errorf("expected SIGTERM to stop the program")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected %q to be removed on SIGTERM", path)
+	}
+}
+
+func TestSweepStaleTmpFiles(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-2 * staleTmpFileAge)
+	write := func(name string, modified time.Time) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	stale := write("s3n-123-bucket-a.txt", old)
+	recent := write("s3n-456-bucket-b.txt", time.Now())
+	other := write("other-789.txt", old)
+	tracked := write("s3n-999-bucket-c.txt", old)
+	trackTmpFile(tracked)
+	t.Cleanup(func() { keepTmpFile(tracked) })
+
+	n, err := sweepStaleTmpFiles(dir, staleTmpFileAge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("removed %d files, want 1", n)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("expected the stale s3n file to be removed")
+	}
+	for _, path := range []string{recent, other, tracked} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be kept: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestFileHashDetectsChanges(t *testing.T) {
+	path, err := writeToTmpFile(t.TempDir(), "", strings.NewReader("original"), "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := fileHash(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, _ := fileHash(path)
+	if string(before) != string(same) {
+		t.Errorf("hash of an unchanged file differs")
+	}
+
+	if err := os.WriteFile(path, []byte("edited"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := fileHash(path)
+	if string(before) == string(after) {
+		t.Errorf("hash did not change after editing the file")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// transferProgress counts the bytes an upload or download has moved so far. The transfer
+// updates it from its own goroutine while the view reads it on every spinner tick.
+type transferProgress struct {
+	done  atomic.Int64
+	total atomic.Int64
+}
+
+func newTransferProgress(total int64) *transferProgress {
+	p := &transferProgress{}
+	p.total.Store(total)
+	return p
+}
+
+// fraction is how much of the transfer is done, from 0 to 1; 0 while the total is unknown.
+func (p *transferProgress) fraction() float64 {
+	total := p.total.Load()
+	if total <= 0 {
+		return 0
+	}
+	return min(max(float64(p.done.Load())/float64(total), 0), 1)
+}
+
+func (p *transferProgress) String() string {
+	return fmt.Sprintf("%s / %s", humanize.Bytes(uint64(p.done.Load())), humanize.Bytes(uint64(max(p.total.Load(), 0))))
+}
+
+// progressReader counts what is read through it into p. It can seek when r can, which
+// the SDK does to sign an upload body before sending it; the count follows the position.
+type progressReader struct {
+	r io.Reader
+	p *transferProgress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.done.Add(int64(n))
+	return n, err
+}
+
+func (r *progressReader) Seek(offset int64, whence int) (int64, error) {
+	s, ok := r.r.(io.Seeker)
+	if !ok {
+		return 0, errors.New("progressReader: underlying reader cannot seek")
+	}
+	pos, err := s.Seek(offset, whence)
+	if err == nil {
+		r.p.done.Store(pos)
+	}
+	return pos, err
+}
+
+// withProgress counts reads from r into p; a nil p leaves r as it is.
+func withProgress(r io.Reader, p *transferProgress) io.Reader {
+	if p == nil {
+		return r
+	}
+	return &progressReader{r: r, p: p}
+}
+
+// startTransfer runs an upload or download as the current operation, with a progress bar
+// of the total bytes it is expected to move. Cancelling the operation cancels ctx, which
+// aborts the request; navigating waits for it instead, as for deletes.
+func (m *Model) startTransfer(label string, total int64, run func(ctx context.Context, p *transferProgress) tea.Msg) tea.Cmd {
+	p := newTransferProgress(total)
+	cmd := m.startOperation(label, func(ctx context.Context) tea.Msg { return run(ctx, p) })
+	m.operation.progress = p
+	m.operation.protected = true
+	return cmd
+}
@@ -0,0 +1,115 @@
+// ABOUTME: Tests for transfer progress in transfer.go.
+// ABOUTME: Covers the counting reader's byte math, seeking, and the progress bar of a running upload.
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestProgressReaderCountsBytes(t *testing.T) {
+	p := newTransferProgress(10)
+	r := withProgress(strings.NewReader("0123456789"), p)
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.fraction(); got != 0.4 {
+		t.Errorf("fraction after 4 of 10 bytes = %v, want 0.4", got)
+	}
+	if got := p.String(); got != "4 B / 10 B" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if _, err := r.(io.Seeker).Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.fraction(); got != 0 {
+		t.Errorf("fraction after seeking back = %v, want 0", got)
+	}
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if got := p.fraction(); got != 1 {
+		t.Errorf("fraction after reading everything = %v, want 1", got)
+	}
+}
+
+func TestTransferProgressFractionBounds(t *testing.T) {
+	unknown := newTransferProgress(0)
+	unknown.done.Store(100)
+	if got := unknown.fraction(); got != 0 {
+		t.Errorf("fraction with an unknown total = %v, want 0", got)
+	}
+	over := newTransferProgress(10)
+	over.done.Store(15)
+	if got := over.fraction(); got != 1 {
+		t.Errorf("fraction past the total = %v, want it capped at 1", got)
+	}
+	if withProgress(strings.NewReader("x"), nil) == nil {
+		t.Errorf("expected the reader back without progress")
+	}
+}
+
+func TestProgressReaderSeekNeedsSeeker(t *testing.T) {
+	r := withProgress(io.MultiReader(strings.NewReader("x")), newTransferProgress(1))
+	if _, err := r.(io.Seeker).Seek(0, io.SeekStart); err == nil {
+		t.Errorf("expected seeking a plain reader to fail")
+	}
+}
+
+func TestUploadShowsProgressBar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, make([]byte, 4096), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.client = newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	})
+
+	cmd := m.startUpload(path)
+	if m.operation == nil || m.operation.progress == nil {
+		t.Fatalf("expected the upload to run as a transfer")
+	}
+	if v := m.operationView(); !strings.Contains(v, "0 B / 4.1 kB") {
+		t.Errorf("operation view %q, want the bytes sent so far", v)
+	}
+	p := m.operation.progress
+	done := operationResult(t, cmd)
+	if p.fraction() != 1 {
+		t.Errorf("fraction after the upload = %v, want 1", p.fraction())
+	}
+	updated, _ := m.Update(done)
+	m = updated.(Model)
+	if (m.operation != nil && m.operation.progress != nil) || m.statusMsg != "Uploaded s3://test-bucket/big.bin" {
+		t.Errorf("expected the bar gone and the upload reported, got %q", m.statusMsg)
+	}
+}
+
+func TestCancelledUploadIsAborted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(path, []byte("a"), 0o600)
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.client = newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request after cancelling")
+	})
+
+	cmd := m.startUpload(path)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	msg := operationResult(t, cmd).msg
+	if errMsg, ok := msg.(errorMsg); !ok || !strings.Contains(errMsg.Error(), context.Canceled.Error()) {
+		t.Errorf("expected the upload to fail with the cancelled context, got %#v", msg)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fileUploadedMsg reports a local file uploaded to bucket/key.
+type fileUploadedMsg struct {
+	bucket string
+	key    string
+}
+
+// uploadMsg asks Update to upload the local file at path to bucket/key, e.g. to retry.
+type uploadMsg struct {
+	bucket string
+	key    string
+	path   string
+}
+
+// promptUpload asks for the local file to upload into the current prefix.
+func (m *Model) promptUpload() tea.Cmd {
+	return m.openPrompt(promptUpload, fmt.Sprintf("Upload to %s: ", s3URI(m.bucketName, m.currentPrefix)), "")
+}
+
+// startUpload checks path is a regular file before uploading it into the current prefix.
+func (m *Model) startUpload(path string) tea.Cmd {
+	path = expandHome(strings.TrimSpace(path))
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		m.setErrorStatus(fmt.Sprintf("Cannot upload %s: %v", path, err))
+		return nil
+	}
+	if info.IsDir() {
+		m.setErrorStatus(fmt.Sprintf("Cannot upload %s: it is a directory", path))
+		return nil
+	}
+	key := m.currentPrefix + filepath.Base(path)
+	if m.opts.dryRun {
+		return m.setStatus(skipped("PutObject", m.bucketName, key).String())
+	}
+	return m.startUploadTransfer(m.bucketName, key, path)
+}
+
+// startUploadTransfer uploads path to bucket/key as an operation with a progress bar.
+func (m *Model) startUploadTransfer(bucket, key, path string) tea.Cmd {
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	client := m.client
+	return m.startTransfer(fmt.Sprintf("Uploading %s to %s", path, s3URI(bucket, key)), size, func(ctx context.Context, p *transferProgress) tea.Msg {
+		return uploadFile(ctx, client, bucket, key, path, p)()
+	})
+}
+
+// uploadFile uploads the local file at path to bucket/key, with the content type
+// detected from its first 512 bytes, counting the bytes sent into progress.
+func uploadFile(ctx context.Context, client S3API, bucket, key, path string, progress *transferProgress) tea.Cmd {
+	return func() tea.Msg {
+		err := func() error {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			head := make([]byte, 512)
+			n, err := io.ReadFull(f, head)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			_, err = client.PutObject(ctx, &s3.PutObjectInput{
+				Bucket:      aws.String(bucket),
+				Key:         aws.String(key),
+				Body:        withProgress(f, progress),
+				ContentType: aws.String(http.DetectContentType(head[:n])),
+			})
+			return err
+		}()
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to upload %s: %w", path, err), retry: func() tea.Msg {
+				return uploadMsg{bucket: bucket, key: key, path: path}
+			}}
+		}
+		return fileUploadedMsg{bucket: bucket, key: key}
+	}
+}
+
+// expandHome replaces a leading ~/ with the user's home directory.
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Tests for uploading local files in upload.go.
+// ABOUTME: Covers content-type detection, the target key and rejecting missing files and directories.
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUploadFileDetectsContentType(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.html")
+	if err := os.WriteFile(path, []byte("<html><body>hi</body></html>"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var gotPath, gotType, gotBody string
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotType, gotBody = r.URL.Path, r.Header.Get("Content-Type"), string(body)
+	})
+
+	msg := uploadFile(context.Background(), client, "b", "site/page.html", path, nil)()
+	if msg != (fileUploadedMsg{bucket: "b", key: "site/page.html"}) {
+		t.Fatalf("unexpected message %#v", msg)
+	}
+	if gotPath != "/b/site/page.html" {
+		t.Errorf("uploaded to %q", gotPath)
+	}
+	if gotType != "text/html; charset=utf-8" {
+		t.Errorf("content type = %q", gotType)
+	}
+	if gotBody != "<html><body>hi</body></html>" {
+		t.Errorf("body = %q, want the whole file", gotBody)
+	}
+}
+
+func TestUploadFileErrorOffersRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(path, []byte("a"), 0o600)
+
+	msg := uploadFile(context.Background(), failingS3Client(t), "b", "a.txt", path, nil)()
+	errMsg, ok := msg.(errorMsg)
+	if !ok || errMsg.retry == nil {
+		t.Fatalf("expected errorMsg with a retry, got %#v", msg)
+	}
+	if !strings.Contains(errMsg.Error(), "failed to upload "+path) {
+		t.Errorf("unexpected error %q", errMsg.Error())
+	}
+}
+
+func TestStartUploadRejectsDirectoriesAndMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		path string
+		want string
+	}{
+		{dir, "it is a directory"},
+		{filepath.Join(dir, "missing.txt"), "no such file"},
+	} {
+		m := initialModel("test-bucket", options{})
+		m.loading = false
+		if cmd := m.startUpload(tc.path); cmd != nil {
+			t.Errorf("%s: expected no upload", tc.path)
+		}
+		if !m.statusIsError || !strings.Contains(m.statusMsg, tc.want) {
+			t.Errorf("%s: status = %q, want an error containing %q", tc.path, m.statusMsg, tc.want)
+		}
+	}
+}
+
+func TestStartUploadDryRunSkipsCall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(path, []byte("a"), 0o600)
+	m := initialModel("test-bucket", options{dryRun: true})
+	m.currentPrefix = "docs/"
+
+	m.startUpload(path)
+
+	if m.statusMsg != "[dry-run] would PutObject s3://test-bucket/docs/a.txt" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version and commit identify the build. Releases set them with
+//
+//	go build -ldflags "-X main.version=v1.2.3 -X main.commit=$(git rev-parse HEAD)"
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// buildVersion describes the running build. Without -ldflags, the module version and VCS
+// revision the Go toolchain recorded in the binary are used when there are any.
+func buildVersion() string {
+	v, c := version, commit
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			if c == "" && s.Key == "vcs.revision" {
+				c = s.Value
+			}
+		}
+	}
+	return formatVersion(v, c, runtime.Version())
+}
+
+// formatVersion lays out a build description, e.g. "s3n v1.2.3 (commit 1a2b3c4, go1.23.2)".
+func formatVersion(version, commit, goVersion string) string {
+	if commit == "" {
+		commit = "unknown"
+	} else if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return fmt.Sprintf("s3n %s (commit %s, %s)", version, commit, goVersion)
+}
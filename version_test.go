@@ -0,0 +1,21 @@
+// ABOUTME: Tests for describing the running build in version.go.
+// ABOUTME: Covers the layout of the version string and shortening the commit.
+package main
+
+import "testing"
+
+func TestFormatVersion(t *testing.T) {
+	tests := []struct {
+		version, commit string
+		want            string
+	}{
+		{"v1.2.3", "1a2b3c4d5e6f7a8b9c0d", "s3n v1.2.3 (commit 1a2b3c4, go1.23.2)"},
+		{"v1.2.3", "abc", "s3n v1.2.3 (commit abc, go1.23.2)"},
+		{"dev", "", "s3n dev (commit unknown, go1.23.2)"},
+	}
+	for _, tt := range tests {
+		if got := formatVersion(tt.version, tt.commit, "go1.23.2"); got != tt.want {
+			t.Errorf("formatVersion(%q, %q) = %q, want %q", tt.version, tt.commit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+)
+
+// maxVersions is how many versions of one object are listed.
+const maxVersions = 1000
+
+// nullVersion is the version id S3 gives objects written while versioning was off or suspended.
+const nullVersion = "null"
+
+// objectVersion is one version of an object, or a delete marker, in the versions picker.
+type objectVersion struct {
+	key          string
+	versionID    string
+	modified     time.Time
+	size         int64
+	latest       bool
+	deleteMarker bool
+	relativeTime bool
+}
+
+func (v objectVersion) Title() string {
+	title := v.versionID
+	if v.versionID == nullVersion {
+		title += " (unversioned)"
+	}
+	if v.deleteMarker {
+		title = "🗑 " + title
+	} else {
+		title = "📄 " + title
+	}
+	if v.latest {
+		title += " [latest]"
+	}
+	return title
+}
+
+func (v objectVersion) Description() string {
+	if v.deleteMarker {
+		return fmt.Sprintf("Delete marker, Modified: %s", formatTime(v.modified, v.relativeTime))
+	}
+	return fmt.Sprintf("%s, Modified: %s", humanize.Bytes(uint64(v.size)), formatTime(v.modified, v.relativeTime))
+}
+
+func (v objectVersion) FilterValue() string { return v.versionID }
+
+// item is the version as an object the viewer can open.
+func (v objectVersion) item() item {
+	return item{
+		key:        v.key,
+		displayKey: v.key + "@" + v.versionID,
+		size:       v.size,
+		modified:   v.modified,
+		versionID:  v.versionID,
+	}
+}
+
+// versionsLoadedMsg carries the versions of key, newest first.
+type versionsLoadedMsg struct {
+	bucket   string
+	key      string
+	versions []list.Item
+}
+
+// versionItems turns a ListObjectVersions page into the versions and delete markers of key
+// alone; the prefix filter also returns keys that merely start with it.
+func versionItems(output *s3.ListObjectVersionsOutput, key string, relativeTime bool) []objectVersion {
+	var versions []objectVersion
+	for _, v := range output.Versions {
+		if aws.StringValue(v.Key) != key {
+			continue
+		}
+		versions = append(versions, objectVersion{
+			key:          key,
+			versionID:    aws.StringValue(v.VersionId),
+			modified:     aws.TimeValue(v.LastModified),
+			size:         aws.Int64Value(v.Size),
+			latest:       aws.BoolValue(v.IsLatest),
+			relativeTime: relativeTime,
+		})
+	}
+	for _, d := range output.DeleteMarkers {
+		if aws.StringValue(d.Key) != key {
+			continue
+		}
+		versions = append(versions, objectVersion{
+			key:          key,
+			versionID:    aws.StringValue(d.VersionId),
+			modified:     aws.TimeValue(d.LastModified),
+			latest:       aws.BoolValue(d.IsLatest),
+			deleteMarker: true,
+			relativeTime: relativeTime,
+		})
+	}
+	return versions
+}
+
+// listVersions fetches up to maxVersions versions of key, newest first.
+func listVersions(ctx context.Context, client S3API, bucket, key string, relativeTime bool) tea.Msg {
+	paginator := s3.NewListObjectVersionsPaginator(client, &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(key),
+	})
+	var versions []objectVersion
+	for paginator.HasMorePages() && len(versions) < maxVersions {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return errorMsg{err: fmt.Errorf("failed to list versions of %s: %w", s3URI(bucket, key), err), retry: func() tea.Msg {
+				return versionsRequestMsg{bucket: bucket, key: key}
+			}}
+		}
+		versions = append(versions, versionItems(page, key, relativeTime)...)
+	}
+	sort.SliceStable(versions, func(a, b int) bool { return versions[a].modified.After(versions[b].modified) })
+	items := make([]list.Item, 0, min(len(versions), maxVersions))
+	for _, v := range versions[:min(len(versions), maxVersions)] {
+		items = append(items, v)
+	}
+	return versionsLoadedMsg{bucket: bucket, key: key, versions: items}
+}
+
+// versionsRequestMsg asks for the versions of key again, from the error panel.
+type versionsRequestMsg struct {
+	bucket string
+	key    string
+}
+
+// startListVersions lists the versions of the object i as an operation.
+func (m *Model) startListVersions(i item) tea.Cmd {
+	if i.isDir {
+		return m.setStatus("Only objects have versions")
+	}
+	return m.startListVersionsOf(m.bucketName, i.key)
+}
+
+// startListVersionsOf lists the versions of key in bucket as an operation.
+func (m *Model) startListVersionsOf(bucket, key string) tea.Cmd {
+	client, relativeTime := m.client, m.relativeTime
+	return m.startOperation("Listing versions of "+s3URI(bucket, key), func(ctx context.Context) tea.Msg {
+		return listVersions(ctx, client, bucket, key, relativeTime)
+	})
+}
+
+// showVersions lists the versions in the picker, where enter opens one in the viewer.
+func (m *Model) showVersions(msg versionsLoadedMsg) tea.Cmd {
+	if len(msg.versions) == 0 {
+		return m.setStatus(fmt.Sprintf("No versions of %s found", s3URI(msg.bucket, msg.key)))
+	}
+	title := fmt.Sprintf("Versions of %s", msg.key)
+	if len(msg.versions) == 1 && msg.versions[0].(objectVersion).versionID == nullVersion {
+		title += " (versioning is off or suspended)"
+	}
+	m.openPicker(pickerVersions, title, msg.versions)
+	m.picker.SetStatusBarItemName("version", "versions")
+	return nil
+}
+
+// openVersion opens a picked version in the built-in viewer; delete markers have no content.
+func (m *Model) openVersion(v objectVersion) tea.Cmd {
+	if v.deleteMarker {
+		return m.setStatus(fmt.Sprintf("%s is a delete marker, there is nothing to view", v.versionID))
+	}
+	return m.viewObject(v.item(), true)
+}
@@ -0,0 +1,109 @@
+// ABOUTME: Tests for listing and opening object versions in versions.go.
+// ABOUTME: Covers mapping ListObjectVersions responses to items, suspended versioning and delete markers.
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestVersionItems(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	output := &s3.ListObjectVersionsOutput{
+		Versions: []types.ObjectVersion{
+			{Key: aws.String("a.txt"), VersionId: aws.String("v2"), LastModified: aws.Time(newer), Size: aws.Int64(20)},
+			{Key: aws.String("a.txt"), VersionId: aws.String("v1"), LastModified: aws.Time(older), Size: aws.Int64(10)},
+			{Key: aws.String("a.txt.bak"), VersionId: aws.String("x"), LastModified: aws.Time(older), Size: aws.Int64(1)},
+		},
+		DeleteMarkers: []types.DeleteMarkerEntry{
+			{Key: aws.String("a.txt"), VersionId: aws.String("v3"), LastModified: aws.Time(newer.Add(time.Hour)), IsLatest: aws.Bool(true)},
+		},
+	}
+	got := versionItems(output, "a.txt", false)
+	if len(got) != 3 {
+		t.Fatalf("got %d versions, want the 3 of a.txt: %+v", len(got), got)
+	}
+	if v := got[0]; v.versionID != "v2" || v.size != 20 || !v.modified.Equal(newer) || v.deleteMarker {
+		t.Errorf("first version = %+v", v)
+	}
+	marker := got[2]
+	if !marker.deleteMarker || !marker.latest || marker.Title() != "🗑 v3 [latest]" {
+		t.Errorf("delete marker = %+v, title %q", marker, marker.Title())
+	}
+	if i := got[1].item(); i.key != "a.txt" || i.versionID != "v1" || i.size != 10 {
+		t.Errorf("version as item = %+v", i)
+	}
+}
+
+func TestListVersionsNewestFirst(t *testing.T) {
+	client := newTestS3Client(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<ListVersionsResult><Name>b</Name><Prefix>a.txt</Prefix><IsTruncated>false</IsTruncated>
+<Version><Key>a.txt</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><LastModified>2024-01-01T00:00:00.000Z</LastModified><Size>1</Size></Version>
+<DeleteMarker><Key>a.txt</Key><VersionId>v3</VersionId><IsLatest>true</IsLatest><LastModified>2024-01-03T00:00:00.000Z</LastModified></DeleteMarker>
+<Version><Key>a.txt</Key><VersionId>v2</VersionId><IsLatest>false</IsLatest><LastModified>2024-01-02T00:00:00.000Z</LastModified><Size>2</Size></Version>
+</ListVersionsResult>`))
+	})
+	msg, ok := listVersions(context.Background(), client, "b", "a.txt", false).(versionsLoadedMsg)
+	if !ok {
+		t.Fatalf("expected versionsLoadedMsg, got %#v", msg)
+	}
+	var ids []string
+	for _, v := range msg.versions {
+		ids = append(ids, v.(objectVersion).versionID)
+	}
+	if len(ids) != 3 || ids[0] != "v3" || ids[1] != "v2" || ids[2] != "v1" {
+		t.Errorf("versions = %v, want newest first", ids)
+	}
+}
+
+func TestShowVersionsOfUnversionedBucket(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.showVersions(versionsLoadedMsg{bucket: "test-bucket", key: "a.txt", versions: versionItemsOf(objectVersion{key: "a.txt", versionID: nullVersion, latest: true})})
+	if m.pickerKind != pickerVersions || m.picker.Title != "Versions of a.txt (versioning is off or suspended)" {
+		t.Errorf("picker %v titled %q", m.pickerKind, m.picker.Title)
+	}
+	if title := m.picker.Items()[0].(objectVersion).Title(); title != "📄 null (unversioned) [latest]" {
+		t.Errorf("title = %q", title)
+	}
+}
+
+func TestOpenDeleteMarkerShowsStatus(t *testing.T) {
+	// nil client: fetching the marker would panic.
+	m := initialModel("test-bucket", options{})
+	m.loading = false
+	m.openVersion(objectVersion{key: "a.txt", versionID: "v3", deleteMarker: true})
+	if m.statusMsg != "v3 is a delete marker, there is nothing to view" {
+		t.Errorf("status = %q", m.statusMsg)
+	}
+}
+
+func versionItemsOf(versions ...objectVersion) []list.Item {
+	var items []list.Item
+	for _, v := range versions {
+		items = append(items, v)
+	}
+	return items
+}
+
+func TestListVersionsRetryStartsANewListing(t *testing.T) {
+	m := initialModel("test-bucket", options{})
+	m.client = newTestS3Client(t, failingOnce(`<ListVersionsResult><Name>test-bucket</Name><IsTruncated>false</IsTruncated>
+<Version><Key>a.txt</Key><VersionId>v1</VersionId><IsLatest>true</IsLatest><LastModified>2024-01-01T00:00:00.000Z</LastModified><Size>1</Size></Version>
+</ListVersionsResult>`))
+	m.loading = false
+
+	m, cmd := retryFailedOperation(t, m, m.startListVersions(item{key: "a.txt"}))
+	updated, _ := m.Update(operationResult(t, cmd))
+	if m = updated.(Model); m.pickerKind != pickerVersions || len(m.picker.Items()) != 1 {
+		t.Errorf("expected the retried listing to show the version, got picker %v, err %q", m.pickerKind, m.errMsg)
+	}
+}
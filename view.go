@@ -0,0 +1,344 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	viewTitleStyle = func() lipgloss.Style {
+		b := lipgloss.RoundedBorder()
+		b.Right = "├"
+		return lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
+	}()
+
+	viewInfoStyle = func() lipgloss.Style {
+		b := lipgloss.RoundedBorder()
+		b.Left = "┤"
+		return viewTitleStyle.BorderStyle(b)
+	}()
+
+	viewHighlightStyle = lipgloss.NewStyle().Background(lipgloss.Color("205")).Foreground(lipgloss.Color("0"))
+)
+
+// ansiSequence matches SGR escape codes so highlighting never splits one.
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+type viewKeyMap struct {
+	Filter         key.Binding
+	ClearFilter    key.Binding
+	ToggleMarkdown key.Binding
+	NextMatch      key.Binding
+	PrevMatch      key.Binding
+	Exit           key.Binding
+}
+
+func newViewKeyMap() viewKeyMap {
+	return viewKeyMap{
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "highlight"),
+		),
+		ClearFilter: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "clear highlight"),
+		),
+		ToggleMarkdown: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "render markdown"),
+		),
+		NextMatch: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next match"),
+		),
+		PrevMatch: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous match"),
+		),
+		Exit: key.NewBinding(
+			key.WithKeys("q", "esc", "backspace"),
+			key.WithHelp("q", "back"),
+		),
+	}
+}
+
+// ViewModel is the built-in, scrollable object viewer.
+type ViewModel struct {
+	title     string
+	header    string
+	body      string
+	notice    string
+	viewport  viewport.Model
+	filter    textinput.Model
+	filtering bool
+	keys      viewKeyMap
+
+	// matches holds the lines containing the filter term; match indexes the one last
+	// jumped to, or is -1 before the first jump.
+	matches []int
+	match   int
+
+	// markdown enables the rendered view toggle; renderMarkdown is whether it is on.
+	markdown       bool
+	renderMarkdown bool
+
+	// highlighted is the body coloured by its language, or "" when it is shown plain.
+	highlighted string
+}
+
+// NewViewModel shows header as-is above body, which may be rendered as Markdown.
+func NewViewModel(title, header, body string, width, height int) ViewModel {
+	filter := textinput.New()
+	filter.Prompt = "/"
+
+	m := ViewModel{
+		title:    title,
+		header:   header,
+		body:     body,
+		viewport: viewport.New(0, 0),
+		filter:   filter,
+		keys:     newViewKeyMap(),
+	}
+	m.SetSize(width, height)
+	m.updateContent()
+	return m
+}
+
+func (m ViewModel) Update(msg tea.Msg) (ViewModel, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.filtering {
+			switch msg.Type {
+			case tea.KeyEnter:
+				m.filtering = false
+				m.filter.Blur()
+				return m, nil
+			case tea.KeyEsc:
+				m.filtering = false
+				m.filter.Blur()
+				m.filter.SetValue("")
+				m.updateContent()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.filter, cmd = m.filter.Update(msg)
+			m.updateContent()
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Filter):
+			m.filtering = true
+			cmd := m.filter.Focus()
+
+			return m, cmd
+		case key.Matches(msg, m.keys.NextMatch) && len(m.matches) > 0:
+			m.jumpToMatch((m.match + 1) % len(m.matches))
+			return m, nil
+		case key.Matches(msg, m.keys.PrevMatch) && len(m.matches) > 0:
+			m.jumpToMatch((max(m.match, 0) + len(m.matches) - 1) % len(m.matches))
+			return m, nil
+		case key.Matches(msg, m.keys.ClearFilter) && m.filter.Value() != "":
+			m.filter.SetValue("")
+			m.updateContent()
+			return m, nil
+		case key.Matches(msg, m.keys.ToggleMarkdown) && m.markdown:
+			m.renderMarkdown = !m.renderMarkdown
+			m.notice = ""
+			m.updateContent()
+			return m, nil
+		case key.Matches(msg, m.keys.Exit):
+			return m, func() tea.Msg { return viewExit{} }
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	cmds = append(cmds, cmd)
+	return m, tea.Batch(cmds...)
+}
+
+func (m ViewModel) View() string {
+	return fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.viewport.View(), m.footerView())
+}
+
+// setSyntax colours the body by the language its key or content type suggests. Bodies
+// over maxHighlightBytes and unrecognised types are left plain.
+func (m *ViewModel) setSyntax(key, contentType string) {
+	lexer := syntaxLexer(key, contentType)
+	if lexer == nil || len(m.body) > maxHighlightBytes {
+		return
+	}
+	highlighted, err := highlightSyntax(m.body, lexer)
+	if err != nil {
+		m.notice = fmt.Sprintf("Cannot highlight syntax: %v", err)
+		return
+	}
+	m.highlighted = highlighted
+	m.updateContent()
+}
+
+// SetSize fits the viewer, including its header and footer, into width x height.
+func (m *ViewModel) SetSize(width, height int) {
+	headerHeight := lipgloss.Height(m.headerView())
+	footerHeight := lipgloss.Height(m.footerView())
+	m.viewport.Width = width
+	m.viewport.Height = max(height-headerHeight-footerHeight, 0)
+	m.filter.Width = max(width/2, 10)
+	if m.renderMarkdown {
+		// Markdown is wrapped to the viewport, so it has to be rendered again.
+		m.updateContent()
+	}
+}
+
+// updateContent re-renders the viewport content with the current filter highlighted.
+func (m *ViewModel) updateContent() {
+	body := m.body
+	if m.highlighted != "" {
+		body = m.highlighted
+	}
+	if m.renderMarkdown {
+		rendered, err := renderMarkdown(m.body, m.viewport.Width)
+		if err != nil {
+			m.renderMarkdown = false
+			m.notice = fmt.Sprintf("Cannot render markdown: %v", err)
+		} else {
+			body = rendered
+		}
+	}
+	m.viewport.SetContent(highlightOccurencesCaseInsensitive(m.header+body, m.filter.Value()))
+	m.matches = matchLines(m.header+body, m.filter.Value())
+	m.match = -1
+}
+
+// jumpToMatch scrolls the viewport to the idx-th matching line.
+func (m *ViewModel) jumpToMatch(idx int) {
+	m.match = idx
+	m.viewport.SetYOffset(m.matches[idx])
+}
+
+// matchLines returns the numbers of the lines of content containing term, ignoring case
+// and ANSI styling.
+func matchLines(content, term string) []int {
+	if term == "" {
+		return nil
+	}
+	term = strings.ToLower(term)
+	var lines []int
+	for n, line := range strings.Split(content, "\n") {
+		if strings.Contains(strings.ToLower(ansiSequence.ReplaceAllString(line, "")), term) {
+			lines = append(lines, n)
+		}
+	}
+	return lines
+}
+
+func (m ViewModel) headerView() string {
+	title := viewTitleStyle.Render(m.title)
+	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(title)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
+}
+
+func (m ViewModel) footerView() string {
+	var left string
+	switch {
+	case m.filtering || m.filter.Value() != "":
+		left = m.filter.View() + " " + m.matchInfo()
+	case m.notice != "":
+		left = m.notice
+	default:
+		bindings := []key.Binding{m.keys.Filter, m.keys.Exit}
+		if m.markdown {
+			bindings = []key.Binding{m.keys.Filter, m.keys.ToggleMarkdown, m.keys.Exit}
+		}
+		var help []string
+		for _, b := range bindings {
+			help = append(help, fmt.Sprintf("%s %s", helpStyleKey.Render(b.Help().Key), helpStyleVal.Render(b.Help().Desc)))
+		}
+		left = strings.Join(help, " • ")
+	}
+	info := viewInfoStyle.Render(fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100))
+	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(info)-lipgloss.Width(left)-1))
+	return lipgloss.JoinHorizontal(lipgloss.Center, left, " ", line, info)
+}
+
+// matchInfo describes the matches of the filter term, e.g. "match 3/12".
+func (m ViewModel) matchInfo() string {
+	switch {
+	case len(m.matches) == 0:
+		return "no matches"
+	case m.match < 0:
+		return fmt.Sprintf("%d matches, %s/%s to jump", len(m.matches), m.keys.NextMatch.Help().Key, m.keys.PrevMatch.Help().Key)
+	}
+	return fmt.Sprintf("match %d/%d", m.match+1, len(m.matches))
+}
+
+// highlightOccurencesCaseInsensitive marks every case-insensitive match of term in content,
+// leaving any ANSI styling already in content intact. A match may span styling, as when
+// the term covers several syntax-highlighted tokens.
+func highlightOccurencesCaseInsensitive(content, term string) string {
+	if term == "" {
+		return content
+	}
+	var chars []string
+	for _, r := range term {
+		chars = append(chars, regexp.QuoteMeta(string(r)))
+	}
+	styled := "(?i:" + strings.Join(chars, "(?:"+ansiSequence.String()+")*") + ")"
+	re := regexp.MustCompile(ansiSequence.String() + "|" + styled)
+	return re.ReplaceAllStringFunc(content, func(match string) string {
+		if ansiSequence.FindString(match) == match {
+			return match
+		}
+		// Mark the text between any sequences inside the match, keeping the sequences.
+		var b strings.Builder
+		last := 0
+		for _, loc := range append(ansiSequence.FindAllStringIndex(match, -1), []int{len(match), len(match)}) {
+			if loc[0] > last {
+				b.WriteString(viewHighlightStyle.Render(match[last:loc[0]]))
+			}
+			b.WriteString(match[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		return b.String()
+	})
+}
+
+// isMarkdown reports whether an object should offer the rendered Markdown view.
+func isMarkdown(key, contentType string) bool {
+	if strings.HasPrefix(contentType, "text/markdown") || strings.HasPrefix(contentType, "text/x-markdown") {
+		return true
+	}
+	switch strings.ToLower(path.Ext(key)) {
+	case ".md", ".markdown":
+		return true
+	}
+	return false
+}
+
+// renderMarkdown formats markdown for the terminal, wrapped to width columns.
+func renderMarkdown(markdown string, width int) (string, error) {
+	style := "light"
+	if lipgloss.HasDarkBackground() {
+		style = "dark"
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(max(width-2, 20)),
+	)
+	if err != nil {
+		return "", err
+	}
+	return r.Render(markdown)
+}
@@ -0,0 +1,173 @@
+// ABOUTME: Tests for the built-in object viewer in view.go.
+// ABOUTME: Covers match and syntax highlighting, jumping, the rendered Markdown toggle and fitting the window.
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestIsMarkdown(t *testing.T) {
+	tests := []struct {
+		key, contentType string
+		want             bool
+	}{
+		{"docs/README.md", "binary/octet-stream", true},
+		{"notes.MARKDOWN", "", true},
+		{"notes", "text/markdown; charset=utf-8", true},
+		{"main.go", "text/plain", false},
+	}
+	for _, tt := range tests {
+		if got := isMarkdown(tt.key, tt.contentType); got != tt.want {
+			t.Errorf("isMarkdown(%q, %q) = %v, want %v", tt.key, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightSkipsAnsiSequences(t *testing.T) {
+	content := "\x1b[38;5;1mred\x1b[0m text"
+	got := highlightOccurencesCaseInsensitive(content, "38")
+	if got != content {
+		t.Errorf("expected escape codes to be left untouched, got %q", got)
+	}
+	if got := highlightOccurencesCaseInsensitive("Foo foo", "FOO"); strings.Count(got, "Foo")+strings.Count(got, "foo") != 2 {
+		t.Errorf("expected both case-insensitive matches to be kept, got %q", got)
+	}
+}
+
+func TestHighlightSpansAnsiSequences(t *testing.T) {
+	content := "\x1b[1mfunc\x1b[0m \x1b[32mmain\x1b[0m()"
+	got := highlightOccurencesCaseInsensitive(content, "FUNC MAIN")
+	if !strings.Contains(got, "\x1b[1m") || !strings.Contains(got, "\x1b[32m") {
+		t.Errorf("expected the syntax colours inside the match to be kept, got %q", got)
+	}
+	if plain := ansiSequence.ReplaceAllString(got, ""); plain != "func main()" {
+		t.Errorf("expected the text to be unchanged, got %q", plain)
+	}
+}
+
+func TestSyntaxLexer(t *testing.T) {
+	tests := []struct {
+		key, contentType string
+		want             string
+	}{
+		{"cmd/main.go", "binary/octet-stream", "Go"},
+		{"config/app.YAML", "", "YAML"},
+		{"data/export", "application/json; charset=utf-8", "JSON"},
+		{"scripts/run.py", "application/json", "Python"},
+		{"notes.txt", "text/plain", ""},
+		{"blob", "application/octet-stream", ""},
+		{"blob", "", ""},
+	}
+	for _, tt := range tests {
+		var got string
+		if lexer := syntaxLexer(tt.key, tt.contentType); lexer != nil {
+			got = lexer.Config().Name
+		}
+		if got != tt.want {
+			t.Errorf("syntaxLexer(%q, %q) = %q, want %q", tt.key, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestViewerHighlightsSyntax(t *testing.T) {
+	v := NewViewModel("s3://b/main.go", "", "package main", 80, 24)
+	v.setSyntax("main.go", "text/plain")
+	if v.highlighted == "" || !strings.Contains(v.viewport.View(), "\x1b[") {
+		t.Errorf("expected Go source to be coloured, got %q", v.viewport.View())
+	}
+
+	big := NewViewModel("s3://b/big.go", "", strings.Repeat("x", maxHighlightBytes+1), 80, 24)
+	big.setSyntax("big.go", "")
+	if big.highlighted != "" {
+		t.Error("expected a body over maxHighlightBytes to stay plain")
+	}
+}
+
+func TestMarkdownToggleRendersBody(t *testing.T) {
+	v := NewViewModel("s3://b/README.md", "", "# Heading\n\nSome *text*.", 80, 24)
+	v.markdown = true
+	if !strings.Contains(v.viewport.View(), "# Heading") {
+		t.Fatalf("expected raw markdown by default")
+	}
+
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+
+	if !v.renderMarkdown {
+		t.Fatalf("expected m to switch to the rendered view")
+	}
+	if strings.Contains(v.viewport.View(), "*text*") {
+		t.Errorf("expected markdown emphasis to be rendered, got %q", v.viewport.View())
+	}
+}
+
+func TestViewerFitsWindow(t *testing.T) {
+	v := NewViewModel("s3://b/notes.txt", "", "line 1\nline 2\nline 3", 60, 20)
+	if w := lipgloss.Width(v.headerView()); w != 60 {
+		t.Errorf("header is %d columns wide, want 60", w)
+	}
+	if w := lipgloss.Width(v.footerView()); w != 60 {
+		t.Errorf("footer is %d columns wide, want 60", w)
+	}
+	if h := lipgloss.Height(v.View()); h != 20 {
+		t.Errorf("viewer is %d lines high, want 20", h)
+	}
+
+	v.SetSize(80, 30)
+	if w := lipgloss.Width(v.footerView()); w != 80 || v.viewport.Width != 80 {
+		t.Errorf("after resizing the footer is %d and the viewport %d columns wide, want 80", w, v.viewport.Width)
+	}
+	if h := lipgloss.Height(v.View()); h != 30 {
+		t.Errorf("after resizing the viewer is %d lines high, want 30", h)
+	}
+}
+
+func TestMatchLines(t *testing.T) {
+	content := "header\n\nfoo one\nbar\n\x1b[1mFo\x1b[0mo split by styling\nsome FOO\n"
+	got := matchLines(content, "foo")
+	if fmt.Sprint(got) != "[2 4 5]" {
+		t.Errorf("matchLines = %v, want [2 4 5]", got)
+	}
+	if got := matchLines(content, ""); got != nil {
+		t.Errorf("an empty term matched %v", got)
+	}
+}
+
+func TestViewerJumpsBetweenMatches(t *testing.T) {
+	var body []string
+	for i := 0; i < 50; i++ {
+		line := fmt.Sprintf("line %d", i)
+		if i%20 == 5 {
+			line += " needle"
+		}
+		body = append(body, line)
+	}
+	v := NewViewModel("t", "", strings.Join(body, "\n"), 40, 10)
+	v.filter.SetValue("NEEDLE")
+	v.updateContent()
+	if fmt.Sprint(v.matches) != "[5 25 45]" {
+		t.Fatalf("matches = %v", v.matches)
+	}
+
+	press := func(r rune) {
+		v, _ = v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	press('n')
+	press('n')
+	if v.viewport.YOffset != 25 || v.matchInfo() != "match 2/3" {
+		t.Errorf("after n n: offset %d, %q", v.viewport.YOffset, v.matchInfo())
+	}
+	press('n')
+	press('n')
+	if v.match != 0 || v.viewport.YOffset != 5 {
+		t.Errorf("n should wrap to the first match, got match %d at offset %d", v.match, v.viewport.YOffset)
+	}
+	press('N')
+	if v.match != 2 || !strings.Contains(v.footerView(), "match 3/3") {
+		t.Errorf("N should wrap to the last match, got match %d, footer %q", v.match, v.footerView())
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go/aws"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultMaxItems is how many objects a recursive operation visits before giving up.
+const defaultMaxItems = 100_000
+
+// maxItemsError stops a recursive walk that would visit more than limit objects.
+type maxItemsError struct {
+	bucket string
+	prefix string
+	limit  int
+}
+
+func (e *maxItemsError) Error() string {
+	return fmt.Sprintf("more than %d objects under %s; narrow the prefix or raise --max-items", e.limit, s3URI(e.bucket, e.prefix))
+}
+
+// overCapMsg reports a recursive operation that stopped at --max-items. The user is
+// asked question, and proceed starts the operation again without the cap on y.
+type overCapMsg struct {
+	question string
+	proceed  tea.Msg
+}
+
+// resolveOverCap runs the operation without the cap on y; anything else leaves it stopped.
+func (m *Model) resolveOverCap(msg tea.KeyMsg) tea.Cmd {
+	proceed := m.overCap.proceed
+	m.overCap = nil
+	if msg.String() == "y" || msg.String() == "Y" {
+		return func() tea.Msg { return proceed }
+	}
+	return m.setStatus("Stopped at --max-items")
+}
+
+// walkObjects calls fn for every object under prefix, at any depth. It stops with a
+// *maxItemsError once more than limit objects are seen; a limit of 0 walks everything,
+// which is how callers override the cap after the user confirms.
+func walkObjects(ctx context.Context, client S3API, bucket, prefix string, limit int, fn func(types.Object) error) error {
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+	seen := 0
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, obj := range page.Contents {
+			seen++
+			if limit > 0 && seen > limit {
+				return &maxItemsError{bucket: bucket, prefix: prefix, limit: limit}
+			}
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
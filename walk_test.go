@@ -0,0 +1,60 @@
+// ABOUTME: Tests for recursive object walks in walk.go.
+// ABOUTME: Covers visiting every object across pages and stopping at the --max-items cap.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// listingServer answers ListObjectsV2 with one object per page, keys a/0 .. a/(n-1).
+func listingServer(t *testing.T, n int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page := 0
+		fmt.Sscan(r.URL.Query().Get("continuation-token"), &page)
+		truncated, next := page < n-1, ""
+		if truncated {
+			next = fmt.Sprintf("<NextContinuationToken>%d</NextContinuationToken>", page+1)
+		}
+		fmt.Fprintf(w, `<ListBucketResult><Name>b</Name><KeyCount>1</KeyCount><IsTruncated>%v</IsTruncated>%s<Contents><Key>a/%d</Key><Size>1</Size></Contents></ListBucketResult>`, truncated, next, page)
+	}
+}
+
+func TestWalkObjectsVisitsAllPages(t *testing.T) {
+	client := newTestS3Client(t, listingServer(t, 3))
+	var keys []string
+	err := walkObjects(context.Background(), client, "b", "a/", 0, func(obj types.Object) error {
+		keys = append(keys, *obj.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(keys) != "[a/0 a/1 a/2]" {
+		t.Errorf("visited %v", keys)
+	}
+}
+
+func TestWalkObjectsStopsAtCap(t *testing.T) {
+	client := newTestS3Client(t, listingServer(t, 3))
+	visited := 0
+	err := walkObjects(context.Background(), client, "b", "a/", 2, func(types.Object) error {
+		visited++
+		return nil
+	})
+	var capErr *maxItemsError
+	if !errors.As(err, &capErr) {
+		t.Fatalf("expected a maxItemsError, got %v", err)
+	}
+	if visited != 2 {
+		t.Errorf("visited %d objects, want 2", visited)
+	}
+	if capErr.Error() != "more than 2 objects under s3://b/a/; narrow the prefix or raise --max-items" {
+		t.Errorf("unexpected message %q", capErr.Error())
+	}
+}